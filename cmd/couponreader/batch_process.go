@@ -8,40 +8,71 @@ import (
 	"github.com/jackc/pgx/v5"
 )
 
-func (u *Uploader) insertBatch(ctx context.Context, batch []string, fileNum, batchNum int) error {
+// maxSources is the number of coupon files that fit in the sources bitmask.
+const maxSources = 31
+
+// upsertCoupons merges the staged codes into the target table, setting the bit
+// for the file. Codes are sorted so concurrent batches lock rows in the same order.
+const upsertCoupons = `
+INSERT INTO %s AS c (code, sources)
+SELECT DISTINCT code, $1::INTEGER
+FROM coupon_staging
+ORDER BY code
+ON CONFLICT (code) DO UPDATE SET sources = c.sources | EXCLUDED.sources`
+
+func (u *Uploader) insertBatch(ctx context.Context, batch []string, fileName string, fileNum, batchNum int) error {
 	if len(batch) == 0 {
 		return nil
 	}
+	if fileNum < 1 || fileNum > maxSources {
+		return fmt.Errorf("file number %d outside of 1-%d", fileNum, maxSources)
+	}
 
 	start := time.Now()
 
 	rows := make([][]any, len(batch))
-	for i, id := range batch {
-		newID := fmt.Sprintf("%s-%d", id, fileNum)
-		rows[i] = []any{newID}
+	for i, code := range batch {
+		rows[i] = []any{code}
+	}
+
+	tx, err := u.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
+	defer tx.Rollback(ctx) // no-op once committed
 
-	conn, err := u.pool.Acquire(ctx)
+	_, err = tx.Exec(ctx, `CREATE TEMP TABLE coupon_staging (code VARCHAR(255)) ON COMMIT DROP`)
 	if err != nil {
-		return fmt.Errorf("failed to acquire connection: %w", err)
+		return fmt.Errorf("failed to create staging table: %w", err)
 	}
-	defer conn.Release()
 
-	_, err = conn.Conn().CopyFrom(
+	_, err = tx.CopyFrom(
 		ctx,
-		pgx.Identifier{"coupons"},
-		[]string{"id"},
+		pgx.Identifier{"coupon_staging"},
+		[]string{"code"},
 		pgx.CopyFromRows(rows),
 	)
 	if err != nil {
 		return fmt.Errorf("insert failed: %w", err)
 	}
 
+	if _, err := tx.Exec(ctx, u.upsertSQL, 1<<(fileNum-1)); err != nil {
+		return fmt.Errorf("upsert failed: %w", err)
+	}
+
+	if err := recordBatch(ctx, tx, fileName, batchNum, len(batch)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit batch: %w", err)
+	}
+
 	duration := time.Since(start)
 	rowsPerSec := float64(len(batch)) / duration.Seconds()
 
-	fmt.Printf("Batch %d: %d rows in %v (%.0f rows/sec)\n",
-		batchNum, len(batch), duration, rowsPerSec)
+	u.progress.Batchf("Batch %d of %s: %d rows in %v (%.0f rows/sec)\n",
+		batchNum, fileName, len(batch), duration, rowsPerSec)
 
 	return nil
 }
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	ErrFileChanged       = errors.New("file changed since the last import, run with --restart")
+	ErrBatchSizeChanged  = errors.New("batch size changed since the last import, run with --restart")
+	ErrValidationChanged = errors.New("validation settings changed since the last import, run with --restart")
+)
+
+// checkpoint is the progress of a file from a previous run. Batches are cut every
+// batch size valid codes, so a batch number only holds the same lines while the
+// file, the batch size and the validation settings are unchanged.
+type checkpoint struct {
+	committed map[int]bool
+	completed bool
+}
+
+// loadCheckpoint returns the batches already committed for the file, starting a
+// new import record when there is none or when restarting.
+func (u *Uploader) loadCheckpoint(ctx context.Context, fileName string, fileSize int64) (checkpoint, error) {
+	cp := checkpoint{committed: make(map[int]bool)}
+
+	if u.opts.Restart {
+		if _, err := u.pool.Exec(ctx, `DELETE FROM coupon_imports WHERE file_name = $1`, fileName); err != nil {
+			return cp, fmt.Errorf("failed to clear checkpoint: %w", err)
+		}
+	}
+
+	var stored importRecord
+	err := u.pool.QueryRow(ctx,
+		`SELECT file_size, batch_size, min_length, max_length, alphabet, completed_at FROM coupon_imports WHERE file_name = $1`,
+		fileName,
+	).Scan(&stored.FileSize, &stored.BatchSize, &stored.Validation.MinLength, &stored.Validation.MaxLength,
+		&stored.Validation.Alphabet, &stored.CompletedAt)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		_, err := u.pool.Exec(ctx,
+			`INSERT INTO coupon_imports (file_name, file_size, batch_size, min_length, max_length, alphabet, started_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+			fileName, fileSize, u.opts.BatchSize, u.opts.MinLength, u.opts.MaxLength, u.opts.Alphabet, time.Now().UTC().UnixNano(),
+		)
+		if err != nil {
+			return cp, fmt.Errorf("failed to start checkpoint: %w", err)
+		}
+		return cp, nil
+
+	case err != nil:
+		return cp, fmt.Errorf("failed to load checkpoint: %w", err)
+	}
+
+	cp.completed, err = stored.resume(fileSize, u.opts)
+	if err != nil || cp.completed {
+		return cp, err
+	}
+
+	rows, err := u.pool.Query(ctx, `SELECT batch_num FROM coupon_import_batches WHERE file_name = $1`, fileName)
+	if err != nil {
+		return cp, fmt.Errorf("failed to load committed batches: %w", err)
+	}
+	batches, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return cp, fmt.Errorf("failed to load committed batches: %w", err)
+	}
+	for _, b := range batches {
+		cp.committed[b] = true
+	}
+
+	return cp, nil
+}
+
+// importRecord is the coupon_imports row of a file from a previous run.
+type importRecord struct {
+	FileSize    int64
+	BatchSize   int
+	Validation  validationSettings
+	CompletedAt *int64
+}
+
+// resume reports whether the file was already imported, or why its committed
+// batches cannot be picked up with the given options. A finished import is
+// skipped whatever the batching, only resuming needs the batches to line up.
+func (r importRecord) resume(fileSize int64, opts Options) (completed bool, err error) {
+	switch {
+	case r.FileSize != fileSize:
+		return false, ErrFileChanged
+	case r.CompletedAt != nil:
+		return true, nil
+	case r.BatchSize != opts.BatchSize:
+		return false, ErrBatchSizeChanged
+	case !r.Validation.matches(opts):
+		return false, ErrValidationChanged
+	}
+	return false, nil
+}
+
+// validationSettings are the options deciding which lines are valid codes as
+// stored with a checkpoint, null for imports started before they were recorded.
+type validationSettings struct {
+	MinLength *int
+	MaxLength *int
+	Alphabet  *string
+}
+
+func (v validationSettings) matches(opts Options) bool {
+	return v.MinLength != nil && *v.MinLength == opts.MinLength &&
+		v.MaxLength != nil && *v.MaxLength == opts.MaxLength &&
+		v.Alphabet != nil && *v.Alphabet == opts.Alphabet
+}
+
+// recordBatch marks the batch committed as part of the transaction that writes it.
+// A retried batch may already be recorded if its commit reached the server.
+func recordBatch(ctx context.Context, tx pgx.Tx, fileName string, batchNum, rowCount int) error {
+	_, err := tx.Exec(ctx,
+		`INSERT INTO coupon_import_batches (file_name, batch_num, row_count, committed_at) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (file_name, batch_num) DO NOTHING`,
+		fileName, batchNum, rowCount, time.Now().UTC().UnixNano(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record batch: %w", err)
+	}
+	return nil
+}
+
+func (u *Uploader) completeCheckpoint(ctx context.Context, fileName string) error {
+	_, err := u.pool.Exec(ctx,
+		`UPDATE coupon_imports SET completed_at = $2 WHERE file_name = $1`,
+		fileName, time.Now().UTC().UnixNano(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to complete checkpoint: %w", err)
+	}
+	return nil
+}
+
+// markSynced records a synced file as completely imported so a later import run
+// does not load it again on top of the sync.
+func (u *Uploader) markSynced(ctx context.Context, tx pgx.Tx, fileName string, fileSize int64) error {
+	now := time.Now().UTC().UnixNano()
+	_, err := tx.Exec(ctx, `
+		INSERT INTO coupon_imports (file_name, file_size, batch_size, min_length, max_length, alphabet, started_at, completed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $7)
+		ON CONFLICT (file_name) DO UPDATE SET
+			file_size = EXCLUDED.file_size,
+			batch_size = EXCLUDED.batch_size,
+			min_length = EXCLUDED.min_length,
+			max_length = EXCLUDED.max_length,
+			alphabet = EXCLUDED.alphabet,
+			started_at = EXCLUDED.started_at,
+			completed_at = EXCLUDED.completed_at`,
+		fileName, fileSize, u.opts.BatchSize, u.opts.MinLength, u.opts.MaxLength, u.opts.Alphabet, now,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record sync: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM coupon_import_batches WHERE file_name = $1`, fileName); err != nil {
+		return fmt.Errorf("failed to record sync: %w", err)
+	}
+
+	return nil
+}
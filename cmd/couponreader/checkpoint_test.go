@@ -0,0 +1,76 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ImportRecord_Resume(t *testing.T) {
+	t.Parallel()
+	opts := Options{
+		BatchSize: 1000,
+		MinLength: 8,
+		MaxLength: 10,
+		Alphabet:  "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789",
+	}
+	ptr := func(v int) *int { return &v }
+	alphabet := opts.Alphabet
+	settings := validationSettings{MinLength: ptr(8), MaxLength: ptr(10), Alphabet: &alphabet}
+	completedAt := int64(1)
+
+	testCases := map[string]struct {
+		record        importRecord
+		fileSize      int64
+		wantCompleted bool
+		wantErr       error
+	}{
+		"success/partial_import": {
+			record:   importRecord{FileSize: 100, BatchSize: 1000, Validation: settings},
+			fileSize: 100,
+		},
+		"success/completed": {
+			record:        importRecord{FileSize: 100, BatchSize: 1000, Validation: settings, CompletedAt: &completedAt},
+			fileSize:      100,
+			wantCompleted: true,
+		},
+		"success/completed_with_other_batch_size": {
+			record:        importRecord{FileSize: 100, BatchSize: 50, Validation: settings, CompletedAt: &completedAt},
+			fileSize:      100,
+			wantCompleted: true,
+		},
+		"error/completed_file_changed": {
+			record:   importRecord{FileSize: 100, BatchSize: 1000, Validation: settings, CompletedAt: &completedAt},
+			fileSize: 120,
+			wantErr:  ErrFileChanged,
+		},
+		"error/partial_file_changed": {
+			record:   importRecord{FileSize: 100, BatchSize: 1000, Validation: settings},
+			fileSize: 120,
+			wantErr:  ErrFileChanged,
+		},
+		"error/partial_batch_size_changed": {
+			record:   importRecord{FileSize: 100, BatchSize: 50, Validation: settings},
+			fileSize: 100,
+			wantErr:  ErrBatchSizeChanged,
+		},
+		"error/partial_validation_changed": {
+			record:   importRecord{FileSize: 100, BatchSize: 1000},
+			fileSize: 100,
+			wantErr:  ErrValidationChanged,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			completed, err := tc.record.resume(tc.fileSize, opts)
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantCompleted, completed)
+		})
+	}
+}
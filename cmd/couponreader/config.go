@@ -0,0 +1,228 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+	"github.com/sgrumley/kart-challenge/pkg/db"
+)
+
+type Config struct {
+	Database *DataConfig `yaml:"database"`
+}
+
+type DataConfig struct {
+	PostgreSQL *db.DBConfig `yaml:"postgres"`
+}
+
+// EnvVar holds the environment overrides for the defaults, flags take
+// precedence over both.
+type EnvVar struct {
+	ConfigFilePath   string        `envconfig:"CONFIG_FILE_PATH" default:"./config/local.yaml"`
+	Inputs           string        `envconfig:"COUPON_INPUTS" default:"./coupons"`
+	BatchSize        int           `envconfig:"COUPON_BATCH_SIZE" default:"100000"`
+	MinLength        int           `envconfig:"COUPON_MIN_LENGTH" default:"8"`
+	MaxLength        int           `envconfig:"COUPON_MAX_LENGTH" default:"10"`
+	Alphabet         string        `envconfig:"COUPON_ALPHABET" default:"ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"`
+	RejectsPath      string        `envconfig:"COUPON_REJECTS" default:"./coupon_rejects.csv"`
+	Workers          int           `envconfig:"COUPON_WORKERS" default:"12"`
+	PoolSize         int           `envconfig:"COUPON_POOL_SIZE" default:"0"`
+	Table            string        `envconfig:"COUPON_TABLE" default:"coupons"`
+	DryRun           bool          `envconfig:"COUPON_DRY_RUN" default:"false"`
+	MaxRetries       int           `envconfig:"COUPON_MAX_RETRIES" default:"5"`
+	RetryBackoff     time.Duration `envconfig:"COUPON_RETRY_BACKOFF" default:"500ms"`
+	ReportPath       string        `envconfig:"COUPON_REPORT" default:""`
+	Progress         string        `envconfig:"COUPON_PROGRESS" default:"auto"`
+	ProgressInterval time.Duration `envconfig:"COUPON_PROGRESS_INTERVAL" default:"2s"`
+	Sync             bool          `envconfig:"COUPON_SYNC" default:"false"`
+}
+
+type Options struct {
+	ConfigFilePath string
+	// Inputs are files, directories of couponbaseN files or globs
+	Inputs    []string
+	BatchSize int
+	// MinLength and MaxLength bound the number of characters in a valid code
+	MinLength int
+	MaxLength int
+	// Alphabet holds every character allowed in a code
+	Alphabet string
+	// RejectsPath receives a CSV of the rejected lines, empty discards them
+	RejectsPath string
+	Workers     int
+	// PoolSize is the max number of Postgres connections, zero sizes it off Workers
+	PoolSize int
+	Table    string
+	// DryRun parses the files and reports their stats without connecting to Postgres
+	DryRun bool
+	// Restart discards any checkpoint and imports every file from the first line
+	Restart bool
+	// MaxRetries is the number of times a batch is retried after a transient error
+	MaxRetries   int
+	RetryBackoff time.Duration
+	// ReportPath receives a JSON report of the run, "-" writes it to stdout
+	ReportPath string
+	// Progress picks how live progress is shown
+	Progress         ProgressMode
+	ProgressInterval time.Duration
+	// Sync makes each file the complete set of codes for its source number,
+	// adding new codes and removing the ones no longer listed
+	Sync bool
+	// Force lets a sync remove codes even when the file looks truncated
+	Force bool
+}
+
+func ParseOptions(args []string) (Options, error) {
+	env := EnvVar{}
+	if err := envconfig.Process("", &env); err != nil {
+		return Options{}, fmt.Errorf("failed to load environment: %w", err)
+	}
+
+	opts := Options{}
+	var inputs string
+	var resume bool
+
+	fs := flag.NewFlagSet("couponreader", flag.ContinueOnError)
+	fs.StringVar(&opts.ConfigFilePath, "config", env.ConfigFilePath, "path to the yaml config (CONFIG_FILE_PATH)")
+	fs.StringVar(&inputs, "inputs", env.Inputs, "comma separated files, directories or globs to import, positional args replace the default (COUPON_INPUTS)")
+	fs.IntVar(&opts.BatchSize, "batch-size", env.BatchSize, "codes per batch (COUPON_BATCH_SIZE)")
+	fs.IntVar(&opts.MinLength, "min-length", env.MinLength, "shortest valid code (COUPON_MIN_LENGTH)")
+	fs.IntVar(&opts.MaxLength, "max-length", env.MaxLength, "longest valid code (COUPON_MAX_LENGTH)")
+	fs.StringVar(&opts.Alphabet, "alphabet", env.Alphabet, "characters allowed in a code (COUPON_ALPHABET)")
+	fs.StringVar(&opts.RejectsPath, "rejects", env.RejectsPath, "write rejected lines to this CSV file, empty to discard (COUPON_REJECTS)")
+	fs.IntVar(&opts.Workers, "workers", env.Workers, "batches written concurrently across all files (COUPON_WORKERS)")
+	fs.IntVar(&opts.PoolSize, "pool-size", env.PoolSize, "max Postgres connections, 0 uses workers+2 (COUPON_POOL_SIZE)")
+	fs.StringVar(&opts.Table, "table", env.Table, "table the codes are upserted into (COUPON_TABLE)")
+	fs.BoolVar(&opts.DryRun, "dry-run", env.DryRun, "parse the files and report stats without touching Postgres (COUPON_DRY_RUN)")
+	fs.IntVar(&opts.MaxRetries, "max-retries", env.MaxRetries, "retries per batch after a transient error (COUPON_MAX_RETRIES)")
+	fs.DurationVar(&opts.RetryBackoff, "retry-backoff", env.RetryBackoff, "wait before the first retry, doubled each attempt (COUPON_RETRY_BACKOFF)")
+	fs.StringVar(&opts.ReportPath, "report", env.ReportPath, "write a JSON report to this path, - for stdout (COUPON_REPORT)")
+	progress := fs.String("progress", env.Progress, "live progress: auto, tty, log or off (COUPON_PROGRESS)")
+	fs.DurationVar(&opts.ProgressInterval, "progress-interval", env.ProgressInterval, "time between progress updates (COUPON_PROGRESS_INTERVAL)")
+	fs.BoolVar(&opts.Sync, "sync", env.Sync, "replace the stored codes of each file's source with the file, adding and removing codes (COUPON_SYNC)")
+	fs.BoolVar(&opts.Force, "force", false, "let --sync remove codes when the file is empty or mostly rejected")
+	fs.BoolVar(&resume, "resume", false, "skip batches committed by a previous run (default)")
+	fs.BoolVar(&opts.Restart, "restart", false, "discard checkpoints and import every file from the start")
+	if err := fs.Parse(args); err != nil {
+		return Options{}, err
+	}
+
+	// positional inputs replace the default unless --inputs was given as well
+	inputsSet := false
+	fs.Visit(func(f *flag.Flag) {
+		inputsSet = inputsSet || f.Name == "inputs"
+	})
+	if fs.NArg() > 0 && !inputsSet {
+		inputs = ""
+	}
+
+	for _, in := range strings.Split(inputs, ",") {
+		if in = strings.TrimSpace(in); in != "" {
+			opts.Inputs = append(opts.Inputs, in)
+		}
+	}
+	opts.Inputs = append(opts.Inputs, fs.Args()...)
+
+	opts.Progress = ProgressMode(*progress)
+
+	if opts.PoolSize == 0 {
+		opts.PoolSize = opts.Workers + 2
+	}
+
+	switch {
+	case resume && opts.Restart:
+		return Options{}, errors.New("--resume and --restart cannot be used together")
+	case opts.Sync && (resume || opts.Restart):
+		return Options{}, errors.New("--sync applies each file in one transaction and does not use checkpoints")
+	case opts.Force && !opts.Sync:
+		return Options{}, errors.New("--force only applies to --sync")
+	case len(opts.Inputs) == 0:
+		return Options{}, errors.New("no inputs given")
+	case opts.BatchSize < 1:
+		return Options{}, errors.New("--batch-size must be positive")
+	case opts.MinLength < 1 || opts.MaxLength < opts.MinLength:
+		return Options{}, errors.New("--min-length must be positive and not above --max-length")
+	case opts.Alphabet == "":
+		return Options{}, errors.New("--alphabet must not be empty")
+	case opts.Workers < 1:
+		return Options{}, errors.New("--workers must be positive")
+	case opts.PoolSize < 1:
+		return Options{}, errors.New("--pool-size must be positive")
+	case opts.MaxRetries < 0:
+		return Options{}, errors.New("--max-retries must not be negative")
+	case opts.RetryBackoff <= 0:
+		return Options{}, errors.New("--retry-backoff must be positive")
+	case opts.Progress != ProgressAuto && opts.Progress != ProgressTTY &&
+		opts.Progress != ProgressLog && opts.Progress != ProgressOff:
+		return Options{}, errors.New("--progress must be one of auto, tty, log or off")
+	case opts.ProgressInterval <= 0:
+		return Options{}, errors.New("--progress-interval must be positive")
+	case opts.Table == "":
+		return Options{}, errors.New("--table must not be empty")
+	}
+
+	return opts, nil
+}
+
+// resolveInputs expands the inputs into the list of files to import. Directories
+// contribute their couponbaseN files and anything else is treated as a glob.
+// Checkpoints and coupon sources are kept per file name, so two different files
+// with the same name are rejected.
+func resolveInputs(inputs []string) ([]string, error) {
+	seen := make(map[string]bool)
+	byName := make(map[string]string)
+	files := make([]string, 0, len(inputs))
+	add := func(path string) error {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return fmt.Errorf("invalid input %s: %w", path, err)
+		}
+		if seen[abs] {
+			return nil
+		}
+		name := filepath.Base(abs)
+		if other, ok := byName[name]; ok {
+			return fmt.Errorf("inputs %s and %s have the same file name", other, path)
+		}
+		seen[abs] = true
+		byName[name] = path
+		files = append(files, path)
+		return nil
+	}
+
+	for _, in := range inputs {
+		if info, err := os.Stat(in); err == nil && info.IsDir() {
+			names, err := discoverCouponFiles(in)
+			if err != nil {
+				return nil, err
+			}
+			for _, name := range names {
+				if err := add(filepath.Join(in, name)); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+
+		matches, err := filepath.Glob(in)
+		if err != nil {
+			return nil, fmt.Errorf("invalid input %s: %w", in, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no files match %s", in)
+		}
+		for _, m := range matches {
+			if err := add(m); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return files, nil
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ParseOptions reads the environment, so these cases use t.Setenv and cannot
+// run in parallel.
+func Test_ParseOptions(t *testing.T) {
+	testCases := map[string]struct {
+		args          []string
+		env           map[string]string
+		wantAssertion func(t *testing.T, got Options)
+		wantErr       string
+	}{
+		"success/defaults": {
+			wantAssertion: func(t *testing.T, got Options) {
+				assert.Equal(t, []string{"./coupons"}, got.Inputs)
+				assert.Equal(t, 100000, got.BatchSize)
+				assert.Equal(t, 8, got.MinLength)
+				assert.Equal(t, 10, got.MaxLength)
+				assert.Equal(t, 12, got.Workers)
+				assert.Equal(t, 14, got.PoolSize)
+				assert.Equal(t, 5, got.MaxRetries)
+				assert.Equal(t, 500*time.Millisecond, got.RetryBackoff)
+				assert.Equal(t, ProgressAuto, got.Progress)
+				assert.Equal(t, "coupons", got.Table)
+			},
+		},
+		"success/env_overrides_default": {
+			env: map[string]string{
+				"COUPON_BATCH_SIZE": "500",
+				"COUPON_WORKERS":    "3",
+				"COUPON_INPUTS":     "a.gz, b.gz",
+			},
+			wantAssertion: func(t *testing.T, got Options) {
+				assert.Equal(t, 500, got.BatchSize)
+				assert.Equal(t, 3, got.Workers)
+				assert.Equal(t, 5, got.PoolSize)
+				assert.Equal(t, []string{"a.gz", "b.gz"}, got.Inputs)
+			},
+		},
+		"success/flag_overrides_env": {
+			args: []string{"--batch-size", "20", "--pool-size", "4"},
+			env: map[string]string{
+				"COUPON_BATCH_SIZE": "500",
+				"COUPON_POOL_SIZE":  "9",
+			},
+			wantAssertion: func(t *testing.T, got Options) {
+				assert.Equal(t, 20, got.BatchSize)
+				assert.Equal(t, 4, got.PoolSize)
+			},
+		},
+		"success/positional_inputs_replace_default": {
+			args: []string{"--dry-run", "one.gz", "two.gz"},
+			wantAssertion: func(t *testing.T, got Options) {
+				assert.True(t, got.DryRun)
+				assert.Equal(t, []string{"one.gz", "two.gz"}, got.Inputs)
+			},
+		},
+		"success/positional_inputs_added_to_flag": {
+			args: []string{"--inputs", "one.gz", "two.gz"},
+			wantAssertion: func(t *testing.T, got Options) {
+				assert.Equal(t, []string{"one.gz", "two.gz"}, got.Inputs)
+			},
+		},
+		"success/positional_inputs_replace_env": {
+			args: []string{"two.gz"},
+			env:  map[string]string{"COUPON_INPUTS": "one.gz"},
+			wantAssertion: func(t *testing.T, got Options) {
+				assert.Equal(t, []string{"two.gz"}, got.Inputs)
+			},
+		},
+		"error/resume_and_restart": {
+			args:    []string{"--resume", "--restart"},
+			wantErr: "--resume and --restart cannot be used together",
+		},
+		"error/sync_with_checkpoints": {
+			args:    []string{"--sync", "--restart"},
+			wantErr: "--sync applies each file in one transaction and does not use checkpoints",
+		},
+		"error/force_without_sync": {
+			args:    []string{"--force"},
+			wantErr: "--force only applies to --sync",
+		},
+		"error/no_inputs": {
+			args:    []string{"--inputs", " , "},
+			wantErr: "no inputs given",
+		},
+		"error/min_above_max_length": {
+			args:    []string{"--min-length", "11"},
+			wantErr: "--min-length must be positive and not above --max-length",
+		},
+		"error/invalid_env": {
+			env:     map[string]string{"COUPON_WORKERS": "many"},
+			wantErr: "failed to load environment",
+		},
+		"error/invalid_progress": {
+			args:    []string{"--progress", "loud"},
+			wantErr: "--progress must be one of auto, tty, log or off",
+		},
+		"error/unknown_flag": {
+			args:    []string{"--verbose"},
+			wantErr: "flag provided but not defined: -verbose",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			for k, v := range tc.env {
+				t.Setenv(k, v)
+			}
+
+			got, err := ParseOptions(tc.args)
+			if tc.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			tc.wantAssertion(t, got)
+		})
+	}
+}
+
+func Test_ResolveInputs(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	for _, name := range []string{"couponbase10.gz", "couponbase2", "couponbase1", "notes.txt"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), nil, 0o600))
+	}
+
+	otherDir := filepath.Join(dir, "other")
+	require.NoError(t, os.Mkdir(otherDir, 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(otherDir, "couponbase1"), nil, 0o600))
+
+	testCases := map[string]struct {
+		inputs  []string
+		want    []string
+		wantErr string
+	}{
+		"success/directory_in_file_number_order": {
+			inputs: []string{dir},
+			want: []string{
+				filepath.Join(dir, "couponbase1"),
+				filepath.Join(dir, "couponbase2"),
+				filepath.Join(dir, "couponbase10.gz"),
+			},
+		},
+		"success/glob": {
+			inputs: []string{filepath.Join(dir, "*.txt")},
+			want:   []string{filepath.Join(dir, "notes.txt")},
+		},
+		"success/duplicates_dropped": {
+			inputs: []string{filepath.Join(dir, "couponbase1"), dir},
+			want: []string{
+				filepath.Join(dir, "couponbase1"),
+				filepath.Join(dir, "couponbase2"),
+				filepath.Join(dir, "couponbase10.gz"),
+			},
+		},
+		"success/same_file_by_another_path": {
+			inputs: []string{filepath.Join(dir, "couponbase1"), filepath.Join(otherDir, "..", "couponbase1")},
+			want:   []string{filepath.Join(dir, "couponbase1")},
+		},
+		"error/same_name_in_two_directories": {
+			inputs:  []string{dir, otherDir},
+			wantErr: "have the same file name",
+		},
+		"error/no_match": {
+			inputs:  []string{filepath.Join(dir, "couponbase9")},
+			wantErr: "no files match",
+		},
+		"error/bad_pattern": {
+			inputs:  []string{filepath.Join(dir, "[")},
+			wantErr: "invalid input",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, err := resolveInputs(tc.inputs)
+			if tc.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func Test_DiscoverCouponFiles(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	for _, name := range []string{"couponbase3.gz", "couponbase12", "couponbase1", "couponbase", "couponbase2.zip"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), nil, 0o600))
+	}
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "couponbase4"), 0o700))
+
+	got, err := discoverCouponFiles(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"couponbase1", "couponbase3.gz", "couponbase12"}, got)
+
+	_, err = discoverCouponFiles(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+}
+
+func Test_ParseFileNumber(t *testing.T) {
+	t.Parallel()
+	testCases := map[string]struct {
+		filename string
+		want     int
+		wantErr  bool
+	}{
+		"success/raw":         {filename: "couponbase1", want: 1},
+		"success/gzipped":     {filename: "couponbase3.gz", want: 3},
+		"success/multi_digit": {filename: "couponbase12", want: 12},
+		"success/path":        {filename: "./coupons/couponbase2.gz", want: 2},
+		"error/no_number":     {filename: "couponbase", wantErr: true},
+		"error/other_suffix":  {filename: "couponbase2.zip", wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, err := parseFileNumber(tc.filename)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
@@ -2,11 +2,14 @@ package main
 
 import (
 	"bufio"
+	"compress/gzip"
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -14,125 +17,268 @@ import (
 	"time"
 )
 
+var couponFilePattern = regexp.MustCompile(`^couponbase\d+(\.gz)?$`)
+
 const (
-	dataDir    = "./coupons"
-	batchSize  = 100_000
-	charLimit  = 10
-	maxWorkers = 12
+	gzipExt = ".gz"
+	utf8BOM = "\uFEFF"
+	// maxLineBytes is the longest line read, anything longer fails the file
+	maxLineBytes = 64 * 1024
 )
 
-func (u *Uploader) processFile(filename string, fileNum int) {
+func (u *Uploader) processFile(filename string, fileNum int) *FileSummary {
+	ctx := context.Background()
+	name := filepath.Base(filename)
+	summary := &FileSummary{
+		File:       name,
+		FileNumber: fileNum,
+	}
+	fail := func(msg string, err error) *FileSummary {
+		u.progress.Printf("%s %s: %v\n", msg, filename, err)
+		summary.Status = FileFailed
+		summary.Error = fmt.Sprintf("%s: %v", msg, err)
+		return summary
+	}
+
 	f, err := os.Open(filename)
 	if err != nil {
-		fmt.Printf("failed to open %s: %v\n", filename, err)
-		return
+		return fail("failed to open", err)
 	}
 	defer f.Close()
 
-	fmt.Printf("Processing file: %s (file number: %d)\n", filename, fileNum)
-	start := time.Now()
-
-	sem := make(chan struct{}, maxWorkers)
-	var wg sync.WaitGroup
-	var totalRows int64
-	batchNum := 1
+	info, err := f.Stat()
+	if err != nil {
+		return fail("failed to stat", err)
+	}
 
-	scanner := bufio.NewScanner(f)
-	// small buffer since we truncate anyway
-	// any valid coupon will be 8-10
-	buf := make([]byte, 0, 64)
-	scanner.Buffer(buf, 1024)
-	batch := make([]string, 0, batchSize)
+	progress := u.progress.Track(name, info.Size())
+	defer progress.done.Store(true)
 
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if line == "" {
-			continue
+	var r io.Reader = countingReader{r: f, n: &progress.read}
+	if strings.HasSuffix(filename, gzipExt) {
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return fail("failed to read gzip", err)
 		}
+		defer gz.Close()
+		r = gz
+	}
+
+	if u.opts.Sync && !u.opts.DryRun {
+		return u.syncFile(ctx, r, fileNum, info.Size(), summary, progress)
+	}
 
-		if len(line) > charLimit {
-			line = line[:charLimit]
+	cp := checkpoint{committed: make(map[int]bool)}
+	if !u.opts.DryRun {
+		cp, err = u.loadCheckpoint(ctx, name, info.Size())
+		if err != nil {
+			return fail("failed to load checkpoint for", err)
 		}
+	}
+	if cp.completed {
+		u.progress.Printf("Skipping file: %s already imported, use --restart to import it again\n", name)
+		summary.Status = FileSkipped
+		return summary
+	}
 
-		batch = append(batch, line)
-		if len(batch) >= batchSize {
-			c := make([]string, len(batch))
-			copy(c, batch)
-
-			sem <- struct{}{}
-			wg.Add(1)
-			go func(b []string, n int) {
-				defer wg.Done()
-				defer func() { <-sem }()
-				err = u.insertBatch(context.Background(), b, fileNum, n)
-				if err != nil {
-					fmt.Printf("error on file %d:%s\n", fileNum, err.Error())
-				}
-				atomic.AddInt64(&totalRows, int64(len(b)))
-			}(c, batchNum)
+	u.progress.Printf("Processing file: %s (file number: %d, resuming after %d batches)\n", filename, fileNum, len(cp.committed))
+	start := time.Now()
 
-			batch = batch[:0]
-			batchNum++
+	var wg sync.WaitGroup
+
+	submit := func(b []string, n int) {
+		summary.Batches++
+		// batches committed by a previous run are still read to keep the numbering
+		if cp.committed[n] {
+			summary.Skipped += int64(len(b))
+			return
+		}
+		if u.opts.DryRun {
+			summary.Inserted += int64(len(b))
+			progress.rows.Add(int64(len(b)))
+			return
 		}
-	}
 
-	if len(batch) > 0 {
-		c := make([]string, len(batch))
-		copy(c, batch)
+		c := make([]string, len(b))
+		copy(c, b)
 
-		sem <- struct{}{}
+		// the budget is shared with every other file being read
+		u.sem <- struct{}{}
 		wg.Add(1)
-		go func(b []string, n int) {
+		go func() {
 			defer wg.Done()
-			defer func() { <-sem }()
-			err = u.insertBatch(context.Background(), b, fileNum, n)
+			defer func() { <-u.sem }()
+			retries, err := u.insertBatchWithRetry(ctx, c, name, fileNum, n)
+			atomic.AddInt64(&summary.Retries, int64(retries))
 			if err != nil {
-				fmt.Printf("error on leftover batch on file %d:%s\n", fileNum, err.Error())
+				u.progress.Printf("error on file %d batch %d:%s\n", fileNum, n, err.Error())
+				atomic.AddInt64(&summary.FailedBatches, 1)
+				atomic.AddInt64(&summary.Failed, int64(len(c)))
+				return
 			}
-			atomic.AddInt64(&totalRows, int64(len(b)))
-		}(c, batchNum)
+			atomic.AddInt64(&summary.Inserted, int64(len(c)))
+			progress.rows.Add(int64(len(c)))
+		}()
 	}
 
-	if serr := scanner.Err(); serr != nil {
-		fmt.Printf("warning: error scanning %s: %v\n", filename, serr)
+	scanErr := u.scanFile(r, name, summary, func(b []string, n int) error {
+		submit(b, n)
+		return nil
+	})
+	if scanErr != nil {
+		u.progress.Printf("warning: error scanning %s: %v\n", filename, scanErr)
+		summary.Error = fmt.Sprintf("error scanning: %v", scanErr)
 	}
 
 	wg.Wait()
 
 	duration := time.Since(start)
-	total := atomic.LoadInt64(&totalRows)
-	rowsPerSec := float64(total) / duration.Seconds()
+	summary.DurationMS = duration.Milliseconds()
+	rowsPerSec := float64(summary.Inserted) / duration.Seconds()
 
-	fmt.Printf("✓ Completed %s: %d rows in %v (%.0f rows/sec)\n",
-		filepath.Base(filename), total, duration, rowsPerSec)
+	if u.opts.DryRun {
+		summary.Status = FileParsed
+		u.progress.Printf("✓ Parsed %s: %d rows in %d batches, %d rejected in %v (%.0f rows/sec)\n",
+			name, summary.Inserted, summary.Batches, summary.Invalid, duration, rowsPerSec)
+		return summary
+	}
+
+	if summary.FailedBatches > 0 || scanErr != nil {
+		summary.Status = FileIncomplete
+		u.progress.Printf("✗ Incomplete %s: %d rows in %v, %d batches failed, rerun to resume\n",
+			name, summary.Inserted, duration, summary.FailedBatches)
+		return summary
+	}
+
+	if err := u.completeCheckpoint(ctx, name); err != nil {
+		u.progress.Printf("failed to complete checkpoint for %s: %v\n", name, err)
+	}
+
+	summary.Status = FileCompleted
+	u.progress.Printf("✓ Completed %s: %d rows in %v (%.0f rows/sec)\n",
+		name, summary.Inserted, duration, rowsPerSec)
+
+	return summary
 }
 
-func (u *Uploader) processFiles(couponFiles []string) {
-	totalStart := time.Now()
+// scanFile validates every line of r, recording rejects, and hands the valid
+// codes to submit in numbered batches of the configured size.
+func (u *Uploader) scanFile(r io.Reader, name string, summary *FileSummary, submit func(batch []string, batchNum int) error) error {
+	scanner := bufio.NewScanner(r)
+	// lines well past the max length are still read so they can be rejected
+	buf := make([]byte, 0, 64)
+	scanner.Buffer(buf, maxLineBytes)
+	batch := make([]string, 0, u.opts.BatchSize)
+	batchNum := 1
+	var lineNum int64
 
-	for _, filename := range couponFiles {
-		fullPath := filepath.Join(dataDir, filename)
-		if _, err := os.Stat(fullPath); os.IsNotExist(err) {
-			fmt.Printf("Warning: File %s does not exist, skipping...\n", fullPath)
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if lineNum == 1 {
+			line = strings.TrimPrefix(line, utf8BOM)
+		}
+
+		if reason, ok := u.validator.Validate(line); !ok {
+			summary.Invalid++
+			if summary.Rejects == nil {
+				summary.Rejects = make(map[RejectReason]int64)
+			}
+			summary.Rejects[reason]++
+			if err := u.rejects.Write(name, lineNum, reason, line); err != nil {
+				u.progress.Printf("warning: failed to record reject for %s: %v\n", name, err)
+			}
 			continue
 		}
 
-		fileNum, err := parseFileNumber(filename)
+		batch = append(batch, line)
+		if len(batch) >= u.opts.BatchSize {
+			if err := submit(batch, batchNum); err != nil {
+				return err
+			}
+			batch = batch[:0]
+			batchNum++
+		}
+	}
+
+	if len(batch) > 0 {
+		if err := submit(batch, batchNum); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
+
+// processFiles reads every file at once. Their batches share the uploader's
+// worker budget so the pool is never oversubscribed however many files there are.
+func (u *Uploader) processFiles(couponFiles []string) *Report {
+	totalStart := time.Now()
+	report := &Report{DryRun: u.opts.DryRun}
+	summaries := make([]*FileSummary, len(couponFiles))
+
+	u.progress.Start()
+
+	var wg sync.WaitGroup
+	for i, path := range couponFiles {
+		fileNum, err := parseFileNumber(filepath.Base(path))
 		if err != nil {
-			fmt.Printf("Error for %s: %v\n", filename, err)
+			u.progress.Printf("Error for %s: %v\n", path, err)
+			summaries[i] = &FileSummary{
+				File:   filepath.Base(path),
+				Status: FileFailed,
+				Error:  err.Error(),
+			}
 			continue
 		}
 
-		u.processFile(fullPath, fileNum)
-		fmt.Println(strings.Repeat("-", 80))
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			summaries[i] = u.processFile(path, fileNum)
+		}()
+	}
+	wg.Wait()
+
+	u.progress.Stop()
+
+	for _, summary := range summaries {
+		report.add(summary)
 	}
 
+	report.DurationMS = time.Since(totalStart).Milliseconds()
 	fmt.Printf("\n All files processed in %v\n", time.Since(totalStart))
+
+	return report
+}
+
+// discoverCouponFiles lists the couponbaseN files in dir, plain or gzipped,
+// ordered by file number.
+func discoverCouponFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+
+	files := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if !e.IsDir() && couponFilePattern.MatchString(e.Name()) {
+			files = append(files, e.Name())
+		}
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		a, _ := parseFileNumber(files[i])
+		b, _ := parseFileNumber(files[j])
+		return a < b
+	})
+
+	return files, nil
 }
 
 func parseFileNumber(filename string) (int, error) {
 	re := regexp.MustCompile(`(\d+)$`)
-	matches := re.FindStringSubmatch(filename)
+	matches := re.FindStringSubmatch(strings.TrimSuffix(filename, gzipExt))
 	if len(matches) < 2 {
 		return 0, fmt.Errorf("no integer found in filename: %s", filename)
 	}
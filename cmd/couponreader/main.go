@@ -2,30 +2,49 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"os"
+	"sync"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/sgrumley/kart-challenge/pkg/config"
 	"github.com/sgrumley/kart-challenge/pkg/db"
 	"github.com/sgrumley/kart-challenge/pkg/logger"
 )
 
-type Config struct {
-	Database *DataConfig `yaml:"database"`
-}
-
-type DataConfig struct {
-	PostgreSQL *db.DBConfig `yaml:"postgres"`
-}
-
 type Uploader struct {
-	pool *pgxpool.Pool
+	pool      *pgxpool.Pool
+	opts      Options
+	upsertSQL string
+	validator *Validator
+	rejects   *RejectWriter
+	progress  *Progress
+	// sem is the worker budget shared by the batches of every file
+	sem chan struct{}
+	// applyMu serialises applying sync deltas
+	applyMu sync.Mutex
 }
 
-func NewUploader(pool *pgxpool.Pool) *Uploader {
-	return &Uploader{pool: pool}
+func NewUploader(pool *pgxpool.Pool, opts Options, rejects *RejectWriter, progress *Progress) *Uploader {
+	// one connection is left over for the checkpoint bookkeeping between batches
+	budget := opts.Workers
+	if pool != nil {
+		budget = max(min(opts.Workers, int(pool.Config().MaxConns)-1), 1)
+	}
+
+	return &Uploader{
+		pool:      pool,
+		opts:      opts,
+		upsertSQL: fmt.Sprintf(upsertCoupons, pgx.Identifier{opts.Table}.Sanitize()),
+		validator: NewValidator(opts),
+		rejects:   rejects,
+		progress:  progress,
+		sem:       make(chan struct{}, budget),
+	}
 }
 
 func main() {
@@ -34,36 +53,78 @@ func main() {
 		logger.WithFormat(logger.HandlerJSON),
 	)
 
-	fp := "./config/local.yaml"
-	cfg, err := config.LoadYAMLDocument[Config](fp)
+	opts, err := ParseOptions(os.Args[1:])
 	if err != nil {
-		log.Error("error", "failed to configure environment", err)
-		return
+		log.Error("error", "invalid options", err)
+		os.Exit(2)
 	}
 
-	connStr := db.URLForConfig(cfg.Database.PostgreSQL.CC)
-	pgxpool, err := NewPool(context.Background(), connStr)
+	if err := run(context.Background(), log, opts); err != nil {
+		log.Error("error", "coupon import failed", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, log *slog.Logger, opts Options) error {
+	couponFiles, err := resolveInputs(opts.Inputs)
 	if err != nil {
-		log.Error("error", "unable to create DB pool with pgx", err)
-		return
+		return fmt.Errorf("unable to find coupon files: %w", err)
 	}
-	defer pgxpool.Close()
 
-	uploader := NewUploader(pgxpool)
-	couponFiles := []string{"ucouponbase1", "ucouponbase2", "ucouponbase3"}
-	uploader.processFiles(couponFiles)
+	var pool *pgxpool.Pool
+	if !opts.DryRun {
+		cfg, err := config.LoadYAMLDocument[Config](opts.ConfigFilePath)
+		if err != nil {
+			return fmt.Errorf("failed to configure environment: %w", err)
+		}
+
+		connStr := db.URLForConfig(cfg.Database.PostgreSQL.CC)
+		pool, err = NewPool(ctx, connStr, opts)
+		if err != nil {
+			return fmt.Errorf("unable to create DB pool with pgx: %w", err)
+		}
+		defer pool.Close()
+	}
+
+	rejects, err := NewRejectWriter(opts.RejectsPath)
+	if err != nil {
+		return err
+	}
+
+	progress := NewProgress(opts.Progress, opts.ProgressInterval, log)
+	report := NewUploader(pool, opts, rejects, progress).processFiles(couponFiles)
+	report.Print(os.Stdout)
+
+	if err := rejects.Close(); err != nil {
+		return err
+	}
+
+	if opts.ReportPath != "" {
+		if err := WriteReport(opts.ReportPath, report); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case report.Failed:
+		return errors.New("some coupon files were not fully imported, rerun to resume")
+	case opts.DryRun:
+		fmt.Println("✓ Dry run complete, nothing was written")
+	default:
+		fmt.Println("✓ All coupon files processed")
+	}
 
-	fmt.Println("✓ All coupon files processed")
+	return nil
 }
 
-func NewPool(ctx context.Context, connStr string) (*pgxpool.Pool, error) {
+func NewPool(ctx context.Context, connStr string, opts Options) (*pgxpool.Pool, error) {
 	config, err := pgxpool.ParseConfig(connStr)
 	if err != nil {
 		return nil, err
 	}
 
-	config.MaxConns = int32(maxWorkers + 2)
-	config.MinConns = int32(maxWorkers)
+	config.MaxConns = int32(opts.PoolSize)
+	config.MinConns = int32(min(opts.Workers, opts.PoolSize))
 	config.MaxConnLifetime = time.Hour
 	config.MaxConnIdleTime = 30 * time.Minute
 	config.HealthCheckPeriod = time.Minute
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type ProgressMode string
+
+const (
+	// ProgressAuto renders to the terminal when stdout is one and logs otherwise.
+	ProgressAuto ProgressMode = "auto"
+	// ProgressTTY redraws a line per file in place.
+	ProgressTTY ProgressMode = "tty"
+	// ProgressLog emits a structured progress event per file.
+	ProgressLog ProgressMode = "log"
+	// ProgressOff prints a line per batch instead.
+	ProgressOff ProgressMode = "off"
+)
+
+// fileProgress is updated by the file reader and its batches while the
+// Progress ticker reads it.
+type fileProgress struct {
+	name  string
+	size  int64
+	start time.Time
+	read  atomic.Int64
+	rows  atomic.Int64
+	done  atomic.Bool
+}
+
+// eta estimates the time left from the share of the file read so far.
+func (f *fileProgress) eta(elapsed time.Duration) time.Duration {
+	read := f.read.Load()
+	if read == 0 || f.size == 0 {
+		return 0
+	}
+	left := max(f.size-read, 0)
+	return time.Duration(float64(elapsed) * float64(left) / float64(read)).Round(time.Second)
+}
+
+// Progress reports the progress of every file being imported. It owns stdout
+// while running so messages do not tear the lines it redraws.
+type Progress struct {
+	mode     ProgressMode
+	out      io.Writer
+	log      *slog.Logger
+	interval time.Duration
+
+	mu    sync.Mutex
+	files []*fileProgress
+	drawn int
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+func NewProgress(mode ProgressMode, interval time.Duration, log *slog.Logger) *Progress {
+	if mode == ProgressAuto {
+		mode = ProgressLog
+		if info, err := os.Stdout.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+			mode = ProgressTTY
+		}
+	}
+
+	return &Progress{
+		mode:     mode,
+		out:      os.Stdout,
+		log:      log,
+		interval: interval,
+		stop:     make(chan struct{}),
+	}
+}
+
+// Track registers a file of size bytes and returns the counters to update.
+func (p *Progress) Track(name string, size int64) *fileProgress {
+	f := &fileProgress{
+		name:  name,
+		size:  size,
+		start: time.Now(),
+	}
+
+	p.mu.Lock()
+	p.files = append(p.files, f)
+	p.mu.Unlock()
+
+	return f
+}
+
+func (p *Progress) Start() {
+	if p.mode == ProgressOff {
+		return
+	}
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-p.stop:
+				p.render()
+				return
+			case <-ticker.C:
+				p.render()
+			}
+		}
+	}()
+}
+
+// Stop renders a final update and waits for the ticker to exit.
+func (p *Progress) Stop() {
+	close(p.stop)
+	p.wg.Wait()
+}
+
+// Printf prints a message above the progress lines.
+func (p *Progress) Printf(format string, args ...any) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.clear()
+	fmt.Fprintf(p.out, format, args...)
+	p.draw()
+}
+
+// Batchf prints per batch detail, only when live progress is off.
+func (p *Progress) Batchf(format string, args ...any) {
+	if p.mode == ProgressOff {
+		p.Printf(format, args...)
+	}
+}
+
+func (p *Progress) render() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	switch p.mode {
+	case ProgressTTY:
+		p.clear()
+		p.draw()
+
+	case ProgressLog:
+		for _, f := range p.files {
+			if f.done.Load() {
+				continue
+			}
+			elapsed := time.Since(f.start)
+			rows := f.rows.Load()
+			p.log.Info("coupon import progress",
+				slog.String("file", f.name),
+				slog.Int64("bytes_read", f.read.Load()),
+				slog.Int64("bytes_total", f.size),
+				slog.Int64("rows", rows),
+				slog.Float64("rows_per_sec", float64(rows)/elapsed.Seconds()),
+				slog.Duration("eta", f.eta(elapsed)),
+			)
+		}
+	}
+}
+
+// clear erases the lines drawn last time, p.mu must be held.
+func (p *Progress) clear() {
+	if p.mode != ProgressTTY || p.drawn == 0 {
+		return
+	}
+	fmt.Fprintf(p.out, "\033[%dA", p.drawn)
+	for range p.drawn {
+		fmt.Fprint(p.out, "\033[2K\n")
+	}
+	fmt.Fprintf(p.out, "\033[%dA", p.drawn)
+	p.drawn = 0
+}
+
+// draw writes a line per file, p.mu must be held.
+func (p *Progress) draw() {
+	if p.mode != ProgressTTY {
+		return
+	}
+
+	for _, f := range p.files {
+		elapsed := time.Since(f.start)
+		read := f.read.Load()
+		rows := f.rows.Load()
+
+		pct := 100.0
+		if f.size > 0 {
+			pct = min(float64(read)/float64(f.size)*100, 100)
+		}
+
+		eta := "done"
+		if !f.done.Load() {
+			eta = "ETA " + f.eta(elapsed).String()
+		}
+
+		fmt.Fprintf(p.out, "%-20s %5.1f%% %9s/%-9s %12d rows %10.0f rows/s  %s\n",
+			f.name, pct, formatBytes(read), formatBytes(f.size), rows, float64(rows)/elapsed.Seconds(), eta)
+	}
+	p.drawn = len(p.files)
+}
+
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%ciB", float64(n)/float64(div), strings.ToUpper("kmgtpe")[exp])
+}
+
+// countingReader counts the bytes read through it into n.
+type countingReader struct {
+	r io.Reader
+	n *atomic.Int64
+}
+
+func (c countingReader) Read(b []byte) (int, error) {
+	n, err := c.r.Read(b)
+	c.n.Add(int64(n))
+	return n, err
+}
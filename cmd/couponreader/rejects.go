@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// maxRejectValue is the number of bytes of a rejected line kept in the file.
+const maxRejectValue = 64
+
+// RejectWriter appends rejected lines to a CSV file shared by every input file.
+// A nil RejectWriter discards them.
+type RejectWriter struct {
+	mu sync.Mutex
+	f  *os.File
+	w  *csv.Writer
+}
+
+func NewRejectWriter(path string) (*RejectWriter, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create rejects file: %w", err)
+	}
+
+	w := csv.NewWriter(f)
+	if err := w.Write([]string{"file", "line", "reason", "value"}); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to write rejects file: %w", err)
+	}
+
+	return &RejectWriter{f: f, w: w}, nil
+}
+
+func (r *RejectWriter) Write(file string, line int64, reason RejectReason, value string) error {
+	if r == nil {
+		return nil
+	}
+
+	if len(value) > maxRejectValue {
+		value = value[:maxRejectValue]
+	}
+	// keep the report itself valid UTF-8 when the line was not
+	value = strings.ToValidUTF8(value, "�")
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.w.Write([]string{file, strconv.FormatInt(line, 10), string(reason), value})
+}
+
+func (r *RejectWriter) Close() error {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.w.Flush()
+	if err := r.w.Error(); err != nil {
+		r.f.Close()
+		return fmt.Errorf("failed to write rejects file: %w", err)
+	}
+	return r.f.Close()
+}
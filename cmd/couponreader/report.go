@@ -0,0 +1,93 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+type FileStatus string
+
+const (
+	// FileCompleted has every batch committed.
+	FileCompleted FileStatus = "completed"
+	// FileIncomplete has batches that failed after retrying, rerun to resume.
+	FileIncomplete FileStatus = "incomplete"
+	// FileFailed could not be read or checkpointed at all.
+	FileFailed FileStatus = "failed"
+	// FileSkipped was completed by a previous run.
+	FileSkipped FileStatus = "skipped"
+	// FileParsed was read in a dry run.
+	FileParsed FileStatus = "parsed"
+	// FileSynced had its delta applied.
+	FileSynced FileStatus = "synced"
+)
+
+// FileSummary counts what happened to the rows of a single coupon file.
+type FileSummary struct {
+	File          string                 `json:"file"`
+	FileNumber    int                    `json:"file_number"`
+	Status        FileStatus             `json:"status"`
+	Inserted      int64                  `json:"inserted"`
+	Failed        int64                  `json:"failed"`
+	Skipped       int64                  `json:"skipped"`
+	Invalid       int64                  `json:"invalid"`
+	Rejects       map[RejectReason]int64 `json:"rejects,omitempty"`
+	Added         int64                  `json:"added,omitempty"`
+	Removed       int64                  `json:"removed,omitempty"`
+	Batches       int                    `json:"batches"`
+	FailedBatches int64                  `json:"failed_batches"`
+	Retries       int64                  `json:"retries"`
+	DurationMS    int64                  `json:"duration_ms"`
+	Error         string                 `json:"error,omitempty"`
+}
+
+func (f *FileSummary) failed() bool {
+	return f.Status == FileIncomplete || f.Status == FileFailed
+}
+
+type Report struct {
+	DryRun     bool           `json:"dry_run"`
+	Failed     bool           `json:"failed"`
+	DurationMS int64          `json:"duration_ms"`
+	Files      []*FileSummary `json:"files"`
+}
+
+func (r *Report) add(f *FileSummary) {
+	r.Files = append(r.Files, f)
+	r.Failed = r.Failed || f.failed()
+}
+
+// Print writes the per file summary as a table.
+func (r *Report) Print(w io.Writer) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "FILE\tSTATUS\tINSERTED\tFAILED\tSKIPPED\tINVALID\tRETRIES\tADDED\tREMOVED")
+	for _, f := range r.Files {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%d\t%d\t%d\t%d\t%d\n",
+			f.File, f.Status, f.Inserted, f.Failed, f.Skipped, f.Invalid, f.Retries, f.Added, f.Removed)
+	}
+	tw.Flush()
+}
+
+// WriteReport writes the report as JSON to path, or to stdout when path is "-".
+func WriteReport(path string, r *Report) error {
+	var w io.Writer = os.Stdout
+	if path != "-" {
+		f, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("failed to create report: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", strings.Repeat(" ", 2))
+	if err := enc.Encode(r); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
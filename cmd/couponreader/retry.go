@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// maxBackoff caps the wait between two attempts of a batch.
+const maxBackoff = 30 * time.Second
+
+// insertBatchWithRetry retries transient failures with exponential backoff and
+// jitter. Batches are idempotent upserts so replaying one that did commit before
+// the connection dropped is harmless. It returns the number of retries made.
+func (u *Uploader) insertBatchWithRetry(ctx context.Context, batch []string, fileName string, fileNum, batchNum int) (int, error) {
+	for attempt := 0; ; attempt++ {
+		err := u.insertBatch(ctx, batch, fileName, fileNum, batchNum)
+		if err == nil || !retryable(err) || attempt >= u.opts.MaxRetries {
+			return attempt, err
+		}
+
+		wait := backoff(u.opts.RetryBackoff, attempt)
+		wait += rand.N(wait/2 + 1)
+		u.progress.Printf("retrying batch %d of %s in %v after: %v\n", batchNum, fileName, wait, err)
+
+		select {
+		case <-ctx.Done():
+			return attempt, ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// backoff is the wait before the retry that follows attempt, base doubled per
+// attempt up to maxBackoff. It doubles step by step rather than shifting so a
+// large --max-retries cannot overflow the duration.
+func backoff(base time.Duration, attempt int) time.Duration {
+	wait := base
+	for i := 0; i < attempt && wait < maxBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, maxBackoff)
+}
+
+// retryable reports whether err is worth another attempt: lost connections,
+// deadlocks, serialization failures and the server shedding load.
+func retryable(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "40001", // serialization_failure
+			"40P01", // deadlock_detected
+			"53300", // too_many_connections
+			"57P01", // admin_shutdown
+			"57P02", // crash_shutdown
+			"57P03": // cannot_connect_now
+			return true
+		}
+		// class 08 is connection exceptions
+		return strings.HasPrefix(pgErr.Code, "08")
+	}
+
+	if pgconn.SafeToRetry(err) || pgconn.Timeout(err) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF)
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Retryable(t *testing.T) {
+	t.Parallel()
+	testCases := map[string]struct {
+		err  error
+		want bool
+	}{
+		"retry/serialization_failure": {err: &pgconn.PgError{Code: "40001"}, want: true},
+		"retry/deadlock":              {err: &pgconn.PgError{Code: "40P01"}, want: true},
+		"retry/too_many_connections":  {err: &pgconn.PgError{Code: "53300"}, want: true},
+		"retry/admin_shutdown":        {err: &pgconn.PgError{Code: "57P01"}, want: true},
+		"retry/connection_exception":  {err: &pgconn.PgError{Code: "08006"}, want: true},
+		"retry/wrapped_pg_error":      {err: fmt.Errorf("insert: %w", &pgconn.PgError{Code: "40001"}), want: true},
+		"retry/deadline_exceeded":     {err: context.DeadlineExceeded, want: true},
+		"retry/network_error":         {err: &net.OpError{Op: "read", Err: errors.New("connection reset")}, want: true},
+		"retry/unexpected_eof":        {err: io.ErrUnexpectedEOF, want: true},
+		"no_retry/unique_violation":   {err: &pgconn.PgError{Code: "23505"}, want: false},
+		"no_retry/undefined_table":    {err: &pgconn.PgError{Code: "42P01"}, want: false},
+		"no_retry/canceled":           {err: context.Canceled, want: false},
+		"no_retry/wrapped_canceled":   {err: fmt.Errorf("insert: %w", context.Canceled), want: false},
+		"no_retry/plain_error":        {err: errors.New("bad input"), want: false},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, retryable(tc.err))
+		})
+	}
+}
+
+func Test_Backoff(t *testing.T) {
+	t.Parallel()
+	testCases := map[string]struct {
+		base    time.Duration
+		attempt int
+		want    time.Duration
+	}{
+		"first_attempt":      {base: 500 * time.Millisecond, attempt: 0, want: 500 * time.Millisecond},
+		"doubles":            {base: 500 * time.Millisecond, attempt: 3, want: 4 * time.Second},
+		"capped":             {base: 500 * time.Millisecond, attempt: 7, want: maxBackoff},
+		"shift_would_wrap":   {base: 500 * time.Millisecond, attempt: 64, want: maxBackoff},
+		"many_retries":       {base: time.Nanosecond, attempt: 1 << 20, want: maxBackoff},
+		"base_above_the_cap": {base: time.Minute, attempt: 0, want: maxBackoff},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, backoff(tc.base, tc.attempt))
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// syncAddCoupons sets the file's bit on every staged code that does not have it
+// yet. Only rows that gain the bit count as affected.
+const syncAddCoupons = `
+INSERT INTO %s AS c (code, sources)
+SELECT DISTINCT code, $1::INTEGER
+FROM coupon_sync_staging
+ORDER BY code
+ON CONFLICT (code) DO UPDATE SET sources = c.sources | EXCLUDED.sources
+WHERE c.sources & EXCLUDED.sources = 0`
+
+// syncRemoveCoupons clears the file's bit on every code missing from the new
+// file and keeps what it cleared so codes left in no file can be deleted.
+const syncRemoveCoupons = `
+WITH removed AS (
+    UPDATE %s AS c
+    SET sources = c.sources & ~$1::INTEGER
+    WHERE c.sources & $1::INTEGER <> 0
+      AND NOT EXISTS (SELECT 1 FROM coupon_sync_staging s WHERE s.code = c.code)
+    RETURNING c.code, c.sources
+)
+INSERT INTO coupon_sync_removed (code, sources)
+SELECT code, sources FROM removed`
+
+const syncDeleteEmptyCoupons = `
+DELETE FROM %s AS c
+USING coupon_sync_removed r
+WHERE c.code = r.code AND r.sources = 0 AND c.sources = 0
+  AND NOT c.manual AND c.disabled_at IS NULL`
+
+// maxSyncRejectRatio is the share of rejected lines above which a sync refuses to
+// remove codes, as the file is more likely broken than re-issued.
+const maxSyncRejectRatio = 0.1
+
+var ErrSyncLooksTruncated = errors.New("refusing to remove codes, rerun with --force to sync anyway")
+
+// checkSyncRemovals guards against a truncated, empty or mangled file clearing
+// its source from every stored code.
+func checkSyncRemovals(staged, invalid int64, force bool) error {
+	if force {
+		return nil
+	}
+	if staged == 0 {
+		return fmt.Errorf("no valid codes were read: %w", ErrSyncLooksTruncated)
+	}
+	if ratio := float64(invalid) / float64(staged+invalid); ratio > maxSyncRejectRatio {
+		return fmt.Errorf("%d of %d lines were rejected: %w", invalid, staged+invalid, ErrSyncLooksTruncated)
+	}
+	return nil
+}
+
+// syncFile replaces what is stored for the file's source number with the codes
+// in r. The whole delta is applied in one transaction so readers see either the
+// old or the new set.
+func (u *Uploader) syncFile(ctx context.Context, r io.Reader, fileNum int, fileSize int64, summary *FileSummary, progress *fileProgress) *FileSummary {
+	name := summary.File
+	u.progress.Printf("Syncing file: %s (file number: %d)\n", name, fileNum)
+	start := time.Now()
+
+	// a sync holds a single connection for the whole file
+	u.sem <- struct{}{}
+	defer func() { <-u.sem }()
+
+	err := u.syncSource(ctx, r, fileNum, fileSize, summary, progress)
+	duration := time.Since(start)
+	summary.DurationMS = duration.Milliseconds()
+	if err != nil {
+		summary.Status = FileFailed
+		summary.Error = err.Error()
+		u.progress.Printf("✗ Failed to sync %s, nothing was changed: %v\n", name, err)
+		return summary
+	}
+
+	summary.Status = FileSynced
+	u.progress.Printf("✓ Synced %s: %d codes, %d added, %d removed in %v\n",
+		name, summary.Inserted, summary.Added, summary.Removed, duration)
+
+	return summary
+}
+
+func (u *Uploader) syncSource(ctx context.Context, r io.Reader, fileNum int, fileSize int64, summary *FileSummary, progress *fileProgress) error {
+	if fileNum < 1 || fileNum > maxSources {
+		return fmt.Errorf("file number %d outside of 1-%d", fileNum, maxSources)
+	}
+	table := pgx.Identifier{u.opts.Table}.Sanitize()
+	bit := 1 << (fileNum - 1)
+
+	tx, err := u.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // no-op once committed
+
+	_, err = tx.Exec(ctx, `
+		CREATE TEMP TABLE coupon_sync_staging (code VARCHAR(255)) ON COMMIT DROP;
+		CREATE TEMP TABLE coupon_sync_removed (code VARCHAR(255), sources INTEGER) ON COMMIT DROP`)
+	if err != nil {
+		return fmt.Errorf("failed to create staging tables: %w", err)
+	}
+
+	err = u.scanFile(r, summary.File, summary, func(batch []string, batchNum int) error {
+		rows := make([][]any, len(batch))
+		for i, code := range batch {
+			rows[i] = []any{code}
+		}
+
+		if _, err := tx.CopyFrom(ctx, pgx.Identifier{"coupon_sync_staging"}, []string{"code"}, pgx.CopyFromRows(rows)); err != nil {
+			return fmt.Errorf("failed to stage batch %d: %w", batchNum, err)
+		}
+
+		summary.Batches++
+		summary.Inserted += int64(len(batch))
+		progress.rows.Add(int64(len(batch)))
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+
+	if err := checkSyncRemovals(summary.Inserted, summary.Invalid, u.opts.Force); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `CREATE INDEX ON coupon_sync_staging (code); ANALYZE coupon_sync_staging`); err != nil {
+		return fmt.Errorf("failed to index staging table: %w", err)
+	}
+
+	// deltas for different files touch the same rows so they are applied one at a time
+	u.applyMu.Lock()
+	defer u.applyMu.Unlock()
+
+	added, err := tx.Exec(ctx, fmt.Sprintf(syncAddCoupons, table), bit)
+	if err != nil {
+		return fmt.Errorf("failed to add codes: %w", err)
+	}
+	summary.Added = added.RowsAffected()
+
+	removed, err := tx.Exec(ctx, fmt.Sprintf(syncRemoveCoupons, table), bit)
+	if err != nil {
+		return fmt.Errorf("failed to remove codes: %w", err)
+	}
+	summary.Removed = removed.RowsAffected()
+
+	if _, err := tx.Exec(ctx, fmt.Sprintf(syncDeleteEmptyCoupons, table)); err != nil {
+		return fmt.Errorf("failed to delete codes in no file: %w", err)
+	}
+
+	if err := u.markSynced(ctx, tx, summary.File, fileSize); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit sync: %w", err)
+	}
+
+	return nil
+}
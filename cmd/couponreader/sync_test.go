@@ -0,0 +1,36 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_CheckSyncRemovals(t *testing.T) {
+	t.Parallel()
+	testCases := map[string]struct {
+		staged  int64
+		invalid int64
+		force   bool
+		wantErr bool
+	}{
+		"allowed/clean_file":      {staged: 1000},
+		"allowed/few_rejects":     {staged: 950, invalid: 50},
+		"refused/empty_file":      {wantErr: true},
+		"refused/fully_rejected":  {invalid: 1000, wantErr: true},
+		"refused/mostly_rejected": {staged: 800, invalid: 200, wantErr: true},
+		"forced/empty_file":       {force: true},
+		"forced/fully_rejected":   {invalid: 1000, force: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			err := checkSyncRemovals(tc.staged, tc.invalid, tc.force)
+			if tc.wantErr {
+				assert.ErrorIs(t, err, ErrSyncLooksTruncated)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
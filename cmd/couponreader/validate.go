@@ -0,0 +1,59 @@
+package main
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+type RejectReason string
+
+const (
+	RejectEmpty     RejectReason = "empty"
+	RejectEncoding  RejectReason = "invalid_encoding"
+	RejectTooShort  RejectReason = "too_short"
+	RejectTooLong   RejectReason = "too_long"
+	RejectCharacter RejectReason = "invalid_character"
+)
+
+// Validator checks a trimmed line is a plausible coupon code. Lines are never
+// truncated or otherwise repaired since that can turn garbage into a code.
+type Validator struct {
+	minLength int
+	maxLength int
+	alphabet  string
+}
+
+func NewValidator(opts Options) *Validator {
+	return &Validator{
+		minLength: opts.MinLength,
+		maxLength: opts.MaxLength,
+		alphabet:  opts.Alphabet,
+	}
+}
+
+// Validate returns the reason the line is rejected, ok is true when it is valid.
+func (v *Validator) Validate(line string) (reason RejectReason, ok bool) {
+	if line == "" {
+		return RejectEmpty, false
+	}
+
+	if !utf8.ValidString(line) {
+		return RejectEncoding, false
+	}
+
+	n := utf8.RuneCountInString(line)
+	switch {
+	case n < v.minLength:
+		return RejectTooShort, false
+	case n > v.maxLength:
+		return RejectTooLong, false
+	}
+
+	for _, r := range line {
+		if !strings.ContainsRune(v.alphabet, r) {
+			return RejectCharacter, false
+		}
+	}
+
+	return "", true
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Validator_Validate(t *testing.T) {
+	t.Parallel()
+	v := NewValidator(Options{
+		MinLength: 8,
+		MaxLength: 10,
+		Alphabet:  "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789",
+	})
+
+	testCases := map[string]struct {
+		line       string
+		wantReason RejectReason
+		wantOK     bool
+	}{
+		"success/min_length":        {line: "HAPPYHRS", wantOK: true},
+		"success/max_length":        {line: "FIFTYOFF10", wantOK: true},
+		"error/empty":               {line: "", wantReason: RejectEmpty},
+		"error/too_short":           {line: "SHORT", wantReason: RejectTooShort},
+		"error/too_long":            {line: "FIFTYOFF100", wantReason: RejectTooLong},
+		"error/lower_case":          {line: "happyhrs", wantReason: RejectCharacter},
+		"error/punctuation":         {line: "HAPPY-HRS", wantReason: RejectCharacter},
+		"error/invalid_utf8":        {line: "HAPPY\xffHRS", wantReason: RejectEncoding},
+		"error/multibyte_character": {line: "HAPPYHRSÉ", wantReason: RejectCharacter},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			reason, ok := v.Validate(tc.line)
+			assert.Equal(t, tc.wantOK, ok)
+			assert.Equal(t, tc.wantReason, reason)
+		})
+	}
+}
+
+func Test_ValidationSettings_Matches(t *testing.T) {
+	t.Parallel()
+	opts := Options{
+		MinLength: 8,
+		MaxLength: 10,
+		Alphabet:  "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789",
+	}
+	ptr := func(v int) *int { return &v }
+	alphabet := opts.Alphabet
+	otherAlphabet := "ABCDEF"
+
+	testCases := map[string]struct {
+		stored validationSettings
+		want   bool
+	}{
+		"match/same_settings": {
+			stored: validationSettings{MinLength: ptr(8), MaxLength: ptr(10), Alphabet: &alphabet},
+			want:   true,
+		},
+		"mismatch/min_length": {
+			stored: validationSettings{MinLength: ptr(6), MaxLength: ptr(10), Alphabet: &alphabet},
+		},
+		"mismatch/max_length": {
+			stored: validationSettings{MinLength: ptr(8), MaxLength: ptr(12), Alphabet: &alphabet},
+		},
+		"mismatch/alphabet": {
+			stored: validationSettings{MinLength: ptr(8), MaxLength: ptr(10), Alphabet: &otherAlphabet},
+		},
+		"mismatch/not_recorded": {
+			stored: validationSettings{},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.stored.matches(opts))
+		})
+	}
+}
@@ -8,3 +8,7 @@ INSERT INTO orders (id, created_at) VALUES
 ('10000000-0000-0000-0000-000000000007', 1728830702000), -- 1 hour 33 minutes later
 ('10000000-0000-0000-0000-000000000008', 1728832202000); -- 1 hour 58 minutes later
 
+
+INSERT INTO order_status_transitions (id, order_id, to_status, created_at)
+SELECT gen_random_uuid(), id, status, created_at
+FROM orders;
@@ -0,0 +1,5 @@
+INSERT INTO categories (id, name, parent_id, display_order, created_at) VALUES
+('10000000-0000-0000-0000-000000000001', 'Appetizers', NULL, 1, 1728825102000),
+('10000000-0000-0000-0000-000000000002', 'Main Courses', NULL, 2, 1728825102000),
+('10000000-0000-0000-0000-000000000003', 'Desserts', NULL, 3, 1728825102000),
+('10000000-0000-0000-0000-000000000004', 'Beverages', NULL, 4, 1728825102000);
@@ -22,3 +22,12 @@ INSERT INTO products (id, name, category, price, created_at) VALUES
 ('00000000-0000-0000-0000-000000000018', 'House Wine', 'Beverages', 899, 1728825102000),
 ('00000000-0000-0000-0000-000000000019', 'Fresh Lemonade', 'Beverages', 399, 1728825102000),
 ('00000000-0000-0000-0000-000000000020', 'Coffee', 'Beverages', 299, 1728825102000);
+
+UPDATE products p
+SET category_id = c.id
+FROM categories c
+WHERE c.name = p.category AND c.parent_id IS NULL;
+
+-- stock is only tracked for a few products, the rest never run out
+UPDATE products SET stock = 12 WHERE id = '00000000-0000-0000-0000-000000000010';
+UPDATE products SET stock = 0 WHERE id = '00000000-0000-0000-0000-000000000014';
@@ -0,0 +1,19 @@
+INSERT INTO modifier_groups (id, product_id, name, min_select, max_select, display_order, created_at) VALUES
+('20000000-0000-0000-0000-000000000001', '00000000-0000-0000-0000-000000000006', 'Size', 1, 1, 1, 1728825102000),
+('20000000-0000-0000-0000-000000000002', '00000000-0000-0000-0000-000000000006', 'Extras', 0, 3, 2, 1728825102000),
+('20000000-0000-0000-0000-000000000003', '00000000-0000-0000-0000-000000000001', 'Sauce', 1, 1, 1, 1728825102000),
+('20000000-0000-0000-0000-000000000004', '00000000-0000-0000-0000-000000000020', 'Milk', 0, 1, 1, 1728825102000);
+
+INSERT INTO modifiers (id, group_id, name, price_delta, display_order, created_at) VALUES
+('30000000-0000-0000-0000-000000000001', '20000000-0000-0000-0000-000000000001', 'Regular', 0, 1, 1728825102000),
+('30000000-0000-0000-0000-000000000002', '20000000-0000-0000-0000-000000000001', 'Large with Fries', 399, 2, 1728825102000),
+('30000000-0000-0000-0000-000000000003', '20000000-0000-0000-0000-000000000002', 'Bacon', 249, 1, 1728825102000),
+('30000000-0000-0000-0000-000000000004', '20000000-0000-0000-0000-000000000002', 'Extra Cheese', 149, 2, 1728825102000),
+('30000000-0000-0000-0000-000000000005', '20000000-0000-0000-0000-000000000002', 'Jalapenos', 99, 3, 1728825102000),
+
+('30000000-0000-0000-0000-000000000006', '20000000-0000-0000-0000-000000000003', 'Mild', 0, 1, 1728825102000),
+('30000000-0000-0000-0000-000000000007', '20000000-0000-0000-0000-000000000003', 'Hot', 0, 2, 1728825102000),
+('30000000-0000-0000-0000-000000000008', '20000000-0000-0000-0000-000000000003', 'Honey BBQ', 0, 3, 1728825102000),
+
+('30000000-0000-0000-0000-000000000009', '20000000-0000-0000-0000-000000000004', 'Oat Milk', 50, 1, 1728825102000),
+('30000000-0000-0000-0000-000000000010', '20000000-0000-0000-0000-000000000004', 'Almond Milk', 50, 2, 1728825102000);
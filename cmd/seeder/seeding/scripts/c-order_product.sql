@@ -1,34 +1,48 @@
-INSERT INTO order_product (id, order_id, product_id) VALUES
-('20000000-0000-0000-0000-000000000001', '10000000-0000-0000-0000-000000000001', '00000000-0000-0000-0000-000000000001'),
-('20000000-0000-0000-0000-000000000002', '10000000-0000-0000-0000-000000000001', '00000000-0000-0000-0000-000000000006'),
-('20000000-0000-0000-0000-000000000003', '10000000-0000-0000-0000-000000000001', '00000000-0000-0000-0000-000000000017'),
-
-('20000000-0000-0000-0000-000000000004', '10000000-0000-0000-0000-000000000002', '00000000-0000-0000-0000-000000000007'),
-('20000000-0000-0000-0000-000000000005', '10000000-0000-0000-0000-000000000002', '00000000-0000-0000-0000-000000000018'),
-('20000000-0000-0000-0000-000000000006', '10000000-0000-0000-0000-000000000002', '00000000-0000-0000-0000-000000000014'),
-
-('20000000-0000-0000-0000-000000000007', '10000000-0000-0000-0000-000000000003', '00000000-0000-0000-0000-000000000004'),
-('20000000-0000-0000-0000-000000000008', '10000000-0000-0000-0000-000000000003', '00000000-0000-0000-0000-000000000011'),
-('20000000-0000-0000-0000-000000000009', '10000000-0000-0000-0000-000000000003', '00000000-0000-0000-0000-000000000008'),
-('20000000-0000-0000-0000-000000000010', '10000000-0000-0000-0000-000000000003', '00000000-0000-0000-0000-000000000019'),
-('20000000-0000-0000-0000-000000000011', '10000000-0000-0000-0000-000000000003', '00000000-0000-0000-0000-000000000016'),
-
-('20000000-0000-0000-0000-000000000012', '10000000-0000-0000-0000-000000000004', '00000000-0000-0000-0000-000000000012'),
-('20000000-0000-0000-0000-000000000013', '10000000-0000-0000-0000-000000000004', '00000000-0000-0000-0000-000000000020'),
-
-('20000000-0000-0000-0000-000000000014', '10000000-0000-0000-0000-000000000005', '00000000-0000-0000-0000-000000000003'),
-('20000000-0000-0000-0000-000000000015', '10000000-0000-0000-0000-000000000005', '00000000-0000-0000-0000-000000000010'),
-('20000000-0000-0000-0000-000000000016', '10000000-0000-0000-0000-000000000005', '00000000-0000-0000-0000-000000000009'),
-('20000000-0000-0000-0000-000000000017', '10000000-0000-0000-0000-000000000005', '00000000-0000-0000-0000-000000000013'),
-
-('20000000-0000-0000-0000-000000000018', '10000000-0000-0000-0000-000000000006', '00000000-0000-0000-0000-000000000002'),
-('20000000-0000-0000-0000-000000000019', '10000000-0000-0000-0000-000000000006', '00000000-0000-0000-0000-000000000005'),
-('20000000-0000-0000-0000-000000000020', '10000000-0000-0000-0000-000000000006', '00000000-0000-0000-0000-000000000017'),
-
-('20000000-0000-0000-0000-000000000021', '10000000-0000-0000-0000-000000000007', '00000000-0000-0000-0000-000000000015'),
-('20000000-0000-0000-0000-000000000022', '10000000-0000-0000-0000-000000000007', '00000000-0000-0000-0000-000000000020'),
-
-('20000000-0000-0000-0000-000000000023', '10000000-0000-0000-0000-000000000008', '00000000-0000-0000-0000-000000000001'),
-('20000000-0000-0000-0000-000000000024', '10000000-0000-0000-0000-000000000008', '00000000-0000-0000-0000-000000000006'),
-('20000000-0000-0000-0000-000000000025', '10000000-0000-0000-0000-000000000008', '00000000-0000-0000-0000-000000000013'),
-('20000000-0000-0000-0000-000000000026', '10000000-0000-0000-0000-000000000008', '00000000-0000-0000-0000-000000000017');
+INSERT INTO order_product (id, order_id, product_id, quantity, unit_price)
+SELECT v.id::uuid, v.order_id::uuid, v.product_id::uuid, v.quantity, p.price
+FROM (VALUES
+('20000000-0000-0000-0000-000000000001', '10000000-0000-0000-0000-000000000001', '00000000-0000-0000-0000-000000000001', 1),
+('20000000-0000-0000-0000-000000000002', '10000000-0000-0000-0000-000000000001', '00000000-0000-0000-0000-000000000006', 1),
+('20000000-0000-0000-0000-000000000003', '10000000-0000-0000-0000-000000000001', '00000000-0000-0000-0000-000000000017', 2),
+
+('20000000-0000-0000-0000-000000000004', '10000000-0000-0000-0000-000000000002', '00000000-0000-0000-0000-000000000007', 1),
+('20000000-0000-0000-0000-000000000005', '10000000-0000-0000-0000-000000000002', '00000000-0000-0000-0000-000000000018', 1),
+('20000000-0000-0000-0000-000000000006', '10000000-0000-0000-0000-000000000002', '00000000-0000-0000-0000-000000000014', 1),
+
+('20000000-0000-0000-0000-000000000007', '10000000-0000-0000-0000-000000000003', '00000000-0000-0000-0000-000000000004', 1),
+('20000000-0000-0000-0000-000000000008', '10000000-0000-0000-0000-000000000003', '00000000-0000-0000-0000-000000000011', 1),
+('20000000-0000-0000-0000-000000000009', '10000000-0000-0000-0000-000000000003', '00000000-0000-0000-0000-000000000008', 1),
+('20000000-0000-0000-0000-000000000010', '10000000-0000-0000-0000-000000000003', '00000000-0000-0000-0000-000000000019', 2),
+('20000000-0000-0000-0000-000000000011', '10000000-0000-0000-0000-000000000003', '00000000-0000-0000-0000-000000000016', 1),
+
+('20000000-0000-0000-0000-000000000012', '10000000-0000-0000-0000-000000000004', '00000000-0000-0000-0000-000000000012', 1),
+('20000000-0000-0000-0000-000000000013', '10000000-0000-0000-0000-000000000004', '00000000-0000-0000-0000-000000000020', 1),
+
+('20000000-0000-0000-0000-000000000014', '10000000-0000-0000-0000-000000000005', '00000000-0000-0000-0000-000000000003', 1),
+('20000000-0000-0000-0000-000000000015', '10000000-0000-0000-0000-000000000005', '00000000-0000-0000-0000-000000000010', 1),
+('20000000-0000-0000-0000-000000000016', '10000000-0000-0000-0000-000000000005', '00000000-0000-0000-0000-000000000009', 1),
+('20000000-0000-0000-0000-000000000017', '10000000-0000-0000-0000-000000000005', '00000000-0000-0000-0000-000000000013', 1),
+
+('20000000-0000-0000-0000-000000000018', '10000000-0000-0000-0000-000000000006', '00000000-0000-0000-0000-000000000002', 1),
+('20000000-0000-0000-0000-000000000019', '10000000-0000-0000-0000-000000000006', '00000000-0000-0000-0000-000000000005', 1),
+('20000000-0000-0000-0000-000000000020', '10000000-0000-0000-0000-000000000006', '00000000-0000-0000-0000-000000000017', 2),
+
+('20000000-0000-0000-0000-000000000021', '10000000-0000-0000-0000-000000000007', '00000000-0000-0000-0000-000000000015', 1),
+('20000000-0000-0000-0000-000000000022', '10000000-0000-0000-0000-000000000007', '00000000-0000-0000-0000-000000000020', 1),
+
+('20000000-0000-0000-0000-000000000023', '10000000-0000-0000-0000-000000000008', '00000000-0000-0000-0000-000000000001', 1),
+('20000000-0000-0000-0000-000000000024', '10000000-0000-0000-0000-000000000008', '00000000-0000-0000-0000-000000000006', 1),
+('20000000-0000-0000-0000-000000000025', '10000000-0000-0000-0000-000000000008', '00000000-0000-0000-0000-000000000013', 1),
+('20000000-0000-0000-0000-000000000026', '10000000-0000-0000-0000-000000000008', '00000000-0000-0000-0000-000000000017', 2)
+) AS v(id, order_id, product_id, quantity)
+JOIN products p ON p.id = v.product_id::uuid;
+
+UPDATE orders o
+SET subtotal = t.subtotal,
+    total = t.subtotal
+FROM (
+    SELECT order_id, SUM(quantity * unit_price) AS subtotal
+    FROM order_product
+    GROUP BY order_id
+) t
+WHERE t.order_id = o.id;
@@ -0,0 +1,10 @@
+INSERT INTO coupon_rules (id, coupon_code, kind, value, category, min_basket, created_at) VALUES
+('30000000-0000-0000-0000-000000000001', 'FIFTYOFF', 'percentage', 50, NULL, 0, 1728825102000),
+('30000000-0000-0000-0000-000000000002', 'HAPPYHRS', 'percentage', 18, 'Beverages', 0, 1728825102000),
+('30000000-0000-0000-0000-000000000003', 'SUPER100', 'fixed_amount', 1000, NULL, 5000, 1728825102000),
+('30000000-0000-0000-0000-000000000004', 'DESSERT30', 'free_cheapest_item', 0, 'Desserts', 3000, 1728825102000);
+
+-- DESSERT30 is not in the coupon files, it is valid as a manual coupon
+INSERT INTO coupons (code, sources, manual) VALUES
+('DESSERT30', 0, true)
+ON CONFLICT (code) DO UPDATE SET manual = true;
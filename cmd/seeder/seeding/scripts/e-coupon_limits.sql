@@ -0,0 +1,3 @@
+INSERT INTO coupon_limits (coupon_code, max_redemptions, per_customer_limit, valid_from, valid_until) VALUES
+('FIFTYOFF', 100, 1, '2024-10-01T00:00:00Z', '2027-01-01T00:00:00Z'),
+('HAPPYHRS', NULL, NULL, '2024-10-01T00:00:00Z', NULL);
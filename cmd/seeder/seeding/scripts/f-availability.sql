@@ -0,0 +1,17 @@
+-- Desserts are served 11:00 to 23:00 and Craft Beer from 16:00 to midnight, every day
+INSERT INTO availability_windows (id, product_id, category_id, day_of_week, start_minute, end_minute, created_at) VALUES
+('40000000-0000-0000-0000-000000000001', NULL, '10000000-0000-0000-0000-000000000003', 0, 660, 1380, 1728825102000),
+('40000000-0000-0000-0000-000000000002', NULL, '10000000-0000-0000-0000-000000000003', 1, 660, 1380, 1728825102000),
+('40000000-0000-0000-0000-000000000003', NULL, '10000000-0000-0000-0000-000000000003', 2, 660, 1380, 1728825102000),
+('40000000-0000-0000-0000-000000000004', NULL, '10000000-0000-0000-0000-000000000003', 3, 660, 1380, 1728825102000),
+('40000000-0000-0000-0000-000000000005', NULL, '10000000-0000-0000-0000-000000000003', 4, 660, 1380, 1728825102000),
+('40000000-0000-0000-0000-000000000006', NULL, '10000000-0000-0000-0000-000000000003', 5, 660, 1380, 1728825102000),
+('40000000-0000-0000-0000-000000000007', NULL, '10000000-0000-0000-0000-000000000003', 6, 660, 1380, 1728825102000),
+
+('40000000-0000-0000-0000-000000000008', '00000000-0000-0000-0000-000000000017', NULL, 0, 960, 1440, 1728825102000),
+('40000000-0000-0000-0000-000000000009', '00000000-0000-0000-0000-000000000017', NULL, 1, 960, 1440, 1728825102000),
+('40000000-0000-0000-0000-000000000010', '00000000-0000-0000-0000-000000000017', NULL, 2, 960, 1440, 1728825102000),
+('40000000-0000-0000-0000-000000000011', '00000000-0000-0000-0000-000000000017', NULL, 3, 960, 1440, 1728825102000),
+('40000000-0000-0000-0000-000000000012', '00000000-0000-0000-0000-000000000017', NULL, 4, 960, 1440, 1728825102000),
+('40000000-0000-0000-0000-000000000013', '00000000-0000-0000-0000-000000000017', NULL, 5, 960, 1440, 1728825102000),
+('40000000-0000-0000-0000-000000000014', '00000000-0000-0000-0000-000000000017', NULL, 6, 960, 1440, 1728825102000);
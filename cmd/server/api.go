@@ -11,15 +11,20 @@ import (
 	chimiddleware "github.com/go-chi/chi/v5/middleware"
 	"github.com/jmoiron/sqlx"
 
+	"github.com/sgrumley/kart-challenge/internal/couponindex"
+	categoryservicev1 "github.com/sgrumley/kart-challenge/internal/services/category/v1"
+	couponservicev1 "github.com/sgrumley/kart-challenge/internal/services/coupon/v1"
 	orderservicev1 "github.com/sgrumley/kart-challenge/internal/services/order/v1"
 	productservicev1 "github.com/sgrumley/kart-challenge/internal/services/product/v1"
+	productservicev2 "github.com/sgrumley/kart-challenge/internal/services/product/v2"
 	"github.com/sgrumley/kart-challenge/internal/store"
 	"github.com/sgrumley/kart-challenge/pkg/idempotency"
+	"github.com/sgrumley/kart-challenge/pkg/logger"
 	"github.com/sgrumley/kart-challenge/pkg/middleware"
 	"github.com/sgrumley/kart-challenge/pkg/web"
 )
 
-func NewHandler(ctx context.Context, log slog.Logger, store *sqlx.DB) http.Handler {
+func NewHandler(ctx context.Context, log slog.Logger, store *sqlx.DB, indexCfg *CouponIndexConfig, adminAPIKey string, menuLocation *time.Location) http.Handler {
 	router := chi.NewRouter()
 
 	router.Use(chimiddleware.Recoverer)
@@ -27,26 +32,52 @@ func NewHandler(ctx context.Context, log slog.Logger, store *sqlx.DB) http.Handl
 
 	router.Use(middleware.AddLogger(log))
 
-	registerRoutes(router, store)
+	registerRoutes(ctx, router, store, indexCfg, adminAPIKey, menuLocation)
 
 	return router
 }
 
-func registerRoutes(router *chi.Mux, client *sqlx.DB) {
+func registerRoutes(ctx context.Context, router *chi.Mux, client *sqlx.DB, indexCfg *CouponIndexConfig, adminAPIKey string, menuLocation *time.Location) {
 	dbstore := store.New(client)
+	dbstore.Location = menuLocation
 	idempotencyStore := idempotency.NewStore()
 
 	routerv1 := chi.NewRouter()
+	routerv2 := chi.NewRouter()
+
+	/*************************** COUPON ENDPOINTS ***************************/
+	// left as a nil interface when disabled so the index routes are not served
+	var index couponservicev1.CouponIndex
+	if indexCfg != nil && indexCfg.Enabled {
+		// coupon checks fall back to the database until the first load completes
+		dbstore.CouponIndex = couponindex.New(dbstore, couponindex.WithBloomFilter(indexCfg.BloomFPRate))
+		if err := dbstore.CouponIndex.LoadAsync(ctx); err != nil {
+			logger.Error(ctx, "failed to start coupon index load", err)
+		}
+		index = dbstore.CouponIndex
+	}
+
+	couponService := couponservicev1.NewService(dbstore, index, adminAPIKey)
+	couponService.GetRoutes(routerv1)
 
 	/*************************** PRODUCT ENDPOINTS ***************************/
-	productService := productservicev1.NewService(dbstore)
+	productService := productservicev1.NewService(dbstore, adminAPIKey)
 	productService.GetRoutes(routerv1)
 
+	// v2 spells category correctly and returns it as an object
+	productServiceV2 := productservicev2.NewService(dbstore, adminAPIKey)
+	productServiceV2.GetRoutes(routerv2)
+
+	/*************************** CATEGORY ENDPOINTS ***************************/
+	categoryService := categoryservicev1.NewService(dbstore)
+	categoryService.GetRoutes(routerv1)
+
 	/*************************** ORDER ENDPOINTS ***************************/
-	orderService := orderservicev1.NewService(dbstore, idempotencyStore)
+	orderService := orderservicev1.NewService(dbstore, idempotencyStore, adminAPIKey)
 	orderService.GetRoutes(routerv1)
 
 	router.Mount("/api/v1", routerv1)
+	router.Mount("/api/v2", routerv2)
 
 	/*************************** HEALTHCHECK  ***************************/
 	router.Post("/health", healthCheck)
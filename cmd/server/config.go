@@ -11,6 +11,15 @@ type EnvVar struct {
 	Port           string `envconfig:"APP_PORT" default:"8080"`
 	Host           string `envconfig:"APP_HOST" default:""`
 	Environment    string `envconfig:"ENVIRONMENT" default:"prod"`
+
+	CouponIndexEnabled     bool    `envconfig:"COUPON_INDEX_ENABLED" default:"true"`
+	CouponIndexBloomFPRate float64 `envconfig:"COUPON_INDEX_BLOOM_FP_RATE" default:"0.01"`
+
+	// AdminAPIKey guards the admin endpoints, they reject every request when unset
+	AdminAPIKey string `envconfig:"ADMIN_API_KEY" default:""`
+
+	// MenuTimezone is the IANA zone availability windows are evaluated in
+	MenuTimezone string `envconfig:"MENU_TIMEZONE" default:"UTC"`
 }
 
 func LoadEnvVar() (EnvVar, error) {
@@ -26,3 +35,10 @@ type Config struct {
 type DataConfig struct {
 	PostgreSQL *db.DBConfig `yaml:"postgres"`
 }
+
+// CouponIndexConfig controls the in-memory coupon index. When disabled every
+// coupon check goes to the database.
+type CouponIndexConfig struct {
+	Enabled     bool
+	BloomFPRate float64
+}
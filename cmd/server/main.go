@@ -40,13 +40,28 @@ func main() {
 	}
 
 	log.Info("started")
-	if err := run(ctx, log, cfg); err != nil {
+	indexCfg := &CouponIndexConfig{
+		Enabled:     env.CouponIndexEnabled,
+		BloomFPRate: env.CouponIndexBloomFPRate,
+	}
+
+	if env.AdminAPIKey == "" {
+		log.Warn("ADMIN_API_KEY is not set, admin endpoints will reject every request")
+	}
+
+	menuLocation, err := time.LoadLocation(env.MenuTimezone)
+	if err != nil {
+		log.Error("failed to load menu timezone", slog.String("error", err.Error()))
+		return
+	}
+
+	if err := run(ctx, log, cfg, indexCfg, env.AdminAPIKey, menuLocation); err != nil {
 		log.Error("service terminated", slog.String("error", err.Error()))
 		return
 	}
 }
 
-func run(ctx context.Context, log *slog.Logger, cfg *Config) error {
+func run(ctx context.Context, log *slog.Logger, cfg *Config, indexCfg *CouponIndexConfig, adminAPIKey string, menuLocation *time.Location) error {
 	// configure database
 	db, err := db.InitDBConnForApp(log, &cfg.Database.PostgreSQL.CC, &cfg.Database.PostgreSQL.SS)
 	if err != nil {
@@ -58,7 +73,7 @@ func run(ctx context.Context, log *slog.Logger, cfg *Config) error {
 		return fmt.Errorf("could not initialize database: %w", err)
 	}
 
-	newAPI := NewHandler(ctx, *log, sqlxDB)
+	newAPI := NewHandler(ctx, *log, sqlxDB, indexCfg, adminAPIKey, menuLocation)
 
 	svr := &http.Server{
 		ReadHeaderTimeout: 30 * time.Second,
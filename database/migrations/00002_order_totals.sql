@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE orders
+    ADD COLUMN subtotal    FLOAT NOT NULL DEFAULT 0,
+    ADD COLUMN discount    FLOAT NOT NULL DEFAULT 0,
+    ADD COLUMN total       FLOAT NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE orders
+    DROP COLUMN subtotal,
+    DROP COLUMN discount,
+    DROP COLUMN total;
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE order_product
+    ADD COLUMN quantity    INTEGER NOT NULL DEFAULT 1 CHECK (quantity > 0),
+    ADD COLUMN unit_price  FLOAT;
+
+-- historic rows predate price snapshots so fall back to the current product price
+UPDATE order_product op
+SET unit_price = p.price
+FROM products p
+WHERE p.id = op.product_id;
+
+ALTER TABLE order_product
+    ALTER COLUMN unit_price SET NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE order_product
+    DROP COLUMN quantity,
+    DROP COLUMN unit_price;
+-- +goose StatementEnd
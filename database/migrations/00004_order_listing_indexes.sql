@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE INDEX idx_orders_created_at_id ON orders (created_at, id);
+CREATE INDEX idx_orders_coupon_code ON orders (coupon_code);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX idx_orders_coupon_code;
+DROP INDEX idx_orders_created_at_id;
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE orders
+    ADD COLUMN status      VARCHAR(32) NOT NULL DEFAULT 'placed'
+        CHECK (status IN ('placed', 'accepted', 'preparing', 'ready', 'completed', 'cancelled', 'rejected'));
+
+CREATE TABLE order_status_transitions (
+    id                     UUID PRIMARY KEY,
+    order_id               UUID NOT NULL,
+    from_status            VARCHAR(32),
+    to_status              VARCHAR(32) NOT NULL,
+    reason                 TEXT,
+    created_at             BIGINT NOT NULL,
+    FOREIGN KEY (order_id) REFERENCES orders(id)
+);
+
+CREATE INDEX idx_order_status_transitions_order_id ON order_status_transitions (order_id, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE order_status_transitions;
+ALTER TABLE orders
+    DROP COLUMN status;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE coupon_redemptions (
+    id                     UUID PRIMARY KEY,
+    coupon_code            VARCHAR(255) NOT NULL,
+    order_id               UUID NOT NULL UNIQUE,
+    created_at             BIGINT NOT NULL,
+    released_at            BIGINT,
+    FOREIGN KEY (order_id) REFERENCES orders(id)
+);
+
+CREATE INDEX idx_coupon_redemptions_active ON coupon_redemptions (coupon_code) WHERE released_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE coupon_redemptions;
+-- +goose StatementEnd
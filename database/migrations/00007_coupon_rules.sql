@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE coupon_rules (
+    id                     UUID PRIMARY KEY,
+    coupon_code            VARCHAR(255) NOT NULL,
+    kind                   VARCHAR(32) NOT NULL
+        CHECK (kind IN ('percentage', 'fixed_amount', 'free_cheapest_item')),
+    value                  FLOAT NOT NULL DEFAULT 0 CHECK (value >= 0),
+    category               VARCHAR(255),
+    min_basket             FLOAT NOT NULL DEFAULT 0 CHECK (min_basket >= 0),
+    created_at             BIGINT NOT NULL
+);
+
+CREATE INDEX idx_coupon_rules_coupon_code ON coupon_rules (coupon_code);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE coupon_rules;
+-- +goose StatementEnd
@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE coupon_limits (
+    coupon_code            VARCHAR(255) PRIMARY KEY,
+    max_redemptions        INTEGER CHECK (max_redemptions >= 0),
+    per_customer_limit     INTEGER CHECK (per_customer_limit >= 0),
+    valid_from             TIMESTAMPTZ,
+    valid_until            TIMESTAMPTZ,
+    CHECK (valid_from IS NULL OR valid_until IS NULL OR valid_from < valid_until)
+);
+
+ALTER TABLE orders
+    ADD COLUMN customer_id VARCHAR(255);
+
+ALTER TABLE coupon_redemptions
+    ADD COLUMN customer_id VARCHAR(255);
+
+CREATE INDEX idx_coupon_redemptions_customer ON coupon_redemptions (coupon_code, customer_id) WHERE released_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX idx_coupon_redemptions_customer;
+ALTER TABLE coupon_redemptions
+    DROP COLUMN customer_id;
+ALTER TABLE orders
+    DROP COLUMN customer_id;
+DROP TABLE coupon_limits;
+-- +goose StatementEnd
@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+-- each code is stored once, bit n-1 of sources is set when it appears in coupon file n
+CREATE TABLE coupon_codes (
+    code                   VARCHAR(255) PRIMARY KEY,
+    sources                INTEGER NOT NULL DEFAULT 0
+);
+
+INSERT INTO coupon_codes (code, sources)
+SELECT substring(id FROM '^(.*)-[0-9]+$'),
+       bit_or(1 << (substring(id FROM '-([0-9]+)$')::INTEGER - 1))
+FROM coupons
+WHERE id ~ '-[0-9]+$'
+GROUP BY 1;
+
+DROP TABLE coupons;
+ALTER TABLE coupon_codes RENAME TO coupons;
+ALTER TABLE coupons RENAME CONSTRAINT coupon_codes_pkey TO coupons_pkey;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE TABLE coupon_ids (
+    id                     VARCHAR(255) PRIMARY KEY
+);
+
+INSERT INTO coupon_ids (id)
+SELECT code || '-' || n
+FROM coupons, generate_series(1, 31) AS n
+WHERE sources & (1 << (n - 1)) <> 0;
+
+DROP TABLE coupons;
+ALTER TABLE coupon_ids RENAME TO coupons;
+ALTER TABLE coupons RENAME CONSTRAINT coupon_ids_pkey TO coupons_pkey;
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE coupon_imports (
+    file_name              VARCHAR(255) PRIMARY KEY,
+    file_size              BIGINT NOT NULL,
+    batch_size             INTEGER NOT NULL,
+    started_at             BIGINT NOT NULL,
+    completed_at           BIGINT
+);
+
+-- a row is written in the same transaction as the batch it records
+CREATE TABLE coupon_import_batches (
+    file_name              VARCHAR(255) NOT NULL REFERENCES coupon_imports(file_name) ON DELETE CASCADE,
+    batch_num              INTEGER NOT NULL,
+    row_count              INTEGER NOT NULL,
+    committed_at           BIGINT NOT NULL,
+    PRIMARY KEY (file_name, batch_num)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE coupon_import_batches;
+DROP TABLE coupon_imports;
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+-- manual codes were created through the admin API and are valid without appearing in any file
+ALTER TABLE coupons
+    ADD COLUMN manual BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN disabled_at BIGINT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE coupons
+    DROP COLUMN disabled_at,
+    DROP COLUMN manual;
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+-- deleted products are kept so past orders can still show their lines
+ALTER TABLE products
+    ADD COLUMN updated_at BIGINT,
+    ADD COLUMN deleted_at BIGINT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE products
+    DROP COLUMN deleted_at,
+    DROP COLUMN updated_at;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE INDEX idx_products_category ON products (category) WHERE deleted_at IS NULL;
+CREATE INDEX idx_products_price_id ON products (price, id) WHERE deleted_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX idx_products_price_id;
+DROP INDEX idx_products_category;
+-- +goose StatementEnd
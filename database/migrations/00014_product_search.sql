@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- SearchProducts filters on this exact expression, any difference stops the planner using the index
+CREATE INDEX idx_products_search ON products USING GIN ((
+    setweight(to_tsvector('english', name), 'A') ||
+    setweight(to_tsvector('english', coalesce(category, '')), 'B')
+)) WHERE deleted_at IS NULL;
+
+CREATE INDEX idx_products_name_trgm ON products USING GIN (name gin_trgm_ops) WHERE deleted_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX idx_products_name_trgm;
+DROP INDEX idx_products_search;
+-- the extension is left installed as other database objects may rely on it
+-- +goose StatementEnd
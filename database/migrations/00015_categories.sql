@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE categories (
+    id                     UUID PRIMARY KEY,
+    name                   VARCHAR(255) NOT NULL,
+    parent_id              UUID REFERENCES categories(id),
+    display_order          INTEGER NOT NULL DEFAULT 0,
+    created_at             BIGINT NOT NULL,
+    UNIQUE NULLS NOT DISTINCT (parent_id, name)
+);
+
+CREATE INDEX idx_categories_parent_id ON categories (parent_id);
+
+-- the free text categories already in use become top level categories
+INSERT INTO categories (id, name, display_order, created_at)
+SELECT gen_random_uuid(), category, row_number() OVER (ORDER BY category),
+       (extract(epoch FROM now()) * 1000000000)::BIGINT
+FROM (SELECT DISTINCT category FROM products WHERE category IS NOT NULL) c;
+
+-- products.category is kept as a copy of the category name for v1 clients and
+-- coupon rules, the store keeps it in step with category_id
+ALTER TABLE products ADD COLUMN category_id UUID REFERENCES categories(id);
+
+UPDATE products p
+SET category_id = c.id
+FROM categories c
+WHERE c.name = p.category AND c.parent_id IS NULL;
+
+CREATE INDEX idx_products_category_id ON products (category_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE products DROP COLUMN category_id;
+DROP TABLE categories;
+-- +goose StatementEnd
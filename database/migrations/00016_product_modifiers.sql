@@ -0,0 +1,46 @@
+-- +goose Up
+-- +goose StatementBegin
+-- a group is the choice a customer makes for a product, e.g. "Size" or "Extras",
+-- picking between min_select and max_select of its modifiers
+CREATE TABLE modifier_groups (
+    id                     UUID PRIMARY KEY,
+    product_id             UUID NOT NULL REFERENCES products(id),
+    name                   VARCHAR(255) NOT NULL,
+    min_select             INTEGER NOT NULL DEFAULT 0 CHECK (min_select >= 0),
+    max_select             INTEGER NOT NULL CHECK (max_select >= 1 AND max_select >= min_select),
+    display_order          INTEGER NOT NULL DEFAULT 0,
+    created_at             BIGINT NOT NULL,
+    UNIQUE (product_id, name)
+);
+
+CREATE TABLE modifiers (
+    id                     UUID PRIMARY KEY,
+    group_id               UUID NOT NULL REFERENCES modifier_groups(id),
+    name                   VARCHAR(255) NOT NULL,
+    price_delta            FLOAT NOT NULL DEFAULT 0,
+    display_order          INTEGER NOT NULL DEFAULT 0,
+    created_at             BIGINT NOT NULL,
+    UNIQUE (group_id, name)
+);
+
+CREATE INDEX idx_modifier_groups_product_id ON modifier_groups (product_id);
+CREATE INDEX idx_modifiers_group_id ON modifiers (group_id);
+
+-- name and price_delta are snapshotted like order_product.unit_price
+CREATE TABLE order_product_modifiers (
+    id                     UUID PRIMARY KEY,
+    order_product_id       UUID NOT NULL REFERENCES order_product(id),
+    modifier_id            UUID NOT NULL REFERENCES modifiers(id),
+    name                   VARCHAR(255) NOT NULL,
+    price_delta            FLOAT NOT NULL
+);
+
+CREATE INDEX idx_order_product_modifiers_order_product_id ON order_product_modifiers (order_product_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE order_product_modifiers;
+DROP TABLE modifiers;
+DROP TABLE modifier_groups;
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- a null stock is not tracked and never runs out
+ALTER TABLE products
+    ADD COLUMN stock INTEGER CHECK (stock >= 0);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE products
+    DROP COLUMN stock;
+-- +goose StatementEnd
@@ -0,0 +1,44 @@
+-- +goose Up
+-- +goose StatementBegin
+-- a window makes a product, or every product in a category, orderable on one day
+-- of the week between two times of day. Times are minutes since midnight in the
+-- menu timezone, windows past midnight are split in two.
+CREATE TABLE availability_windows (
+    id                     UUID PRIMARY KEY,
+    product_id             UUID REFERENCES products(id),
+    category_id            UUID REFERENCES categories(id),
+    day_of_week            INTEGER NOT NULL CHECK (day_of_week BETWEEN 0 AND 6),
+    start_minute           INTEGER NOT NULL CHECK (start_minute >= 0 AND start_minute < 1440),
+    end_minute             INTEGER NOT NULL CHECK (end_minute > start_minute AND end_minute <= 1440),
+    created_at             BIGINT NOT NULL,
+    CHECK (num_nonnulls(product_id, category_id) = 1)
+);
+
+CREATE INDEX idx_availability_windows_product_id ON availability_windows (product_id);
+CREATE INDEX idx_availability_windows_category_id ON availability_windows (category_id);
+
+-- windows on the product win over those on its category, without any the product
+-- is always available. p_day is 0 for Sunday.
+CREATE FUNCTION product_available_at(p_product_id UUID, p_category_id UUID, p_day INTEGER, p_minute INTEGER)
+RETURNS BOOLEAN
+LANGUAGE sql STABLE
+AS $$
+    SELECT CASE
+        WHEN EXISTS (SELECT 1 FROM availability_windows WHERE product_id = p_product_id) THEN
+            EXISTS (SELECT 1 FROM availability_windows
+                    WHERE product_id = p_product_id AND day_of_week = p_day
+                      AND p_minute >= start_minute AND p_minute < end_minute)
+        WHEN EXISTS (SELECT 1 FROM availability_windows WHERE category_id = p_category_id) THEN
+            EXISTS (SELECT 1 FROM availability_windows
+                    WHERE category_id = p_category_id AND day_of_week = p_day
+                      AND p_minute >= start_minute AND p_minute < end_minute)
+        ELSE true
+    END
+$$;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP FUNCTION product_available_at(UUID, UUID, INTEGER, INTEGER);
+DROP TABLE availability_windows;
+-- +goose StatementEnd
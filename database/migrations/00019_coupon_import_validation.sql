@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+-- batches are cut by valid codes, so a checkpoint only lines up with the file
+-- when it is resumed with the validation settings it was started with. Rows from
+-- before these columns are left null and cannot be resumed.
+ALTER TABLE coupon_imports
+    ADD COLUMN min_length INTEGER,
+    ADD COLUMN max_length INTEGER,
+    ADD COLUMN alphabet   TEXT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE coupon_imports
+    DROP COLUMN alphabet,
+    DROP COLUMN max_length,
+    DROP COLUMN min_length;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- checkpoints were written in milliseconds, every other timestamp is in nanoseconds
+UPDATE coupon_imports SET
+    started_at = started_at * 1000000,
+    completed_at = completed_at * 1000000;
+UPDATE coupon_import_batches SET committed_at = committed_at * 1000000;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+UPDATE coupon_imports SET
+    started_at = started_at / 1000000,
+    completed_at = completed_at / 1000000;
+UPDATE coupon_import_batches SET committed_at = committed_at / 1000000;
+-- +goose StatementEnd
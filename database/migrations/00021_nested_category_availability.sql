@@ -0,0 +1,56 @@
+-- +goose Up
+-- +goose StatementBegin
+-- windows on the product win over those on its category, then over those on the
+-- nearest parent category that has any, without any the product is always
+-- available. p_day is 0 for Sunday.
+CREATE OR REPLACE FUNCTION product_available_at(p_product_id UUID, p_category_id UUID, p_day INTEGER, p_minute INTEGER)
+RETURNS BOOLEAN
+LANGUAGE sql STABLE
+AS $$
+    WITH RECURSIVE chain AS (
+        SELECT id, parent_id, 0 AS depth FROM categories WHERE id = p_category_id
+        UNION ALL
+        -- the depth guard stops a parent_id cycle from recursing forever
+        SELECT c.id, c.parent_id, chain.depth + 1
+        FROM categories c
+        JOIN chain ON c.id = chain.parent_id
+        WHERE chain.depth < 32
+    ), nearest AS (
+        SELECT chain.id FROM chain
+        WHERE EXISTS (SELECT 1 FROM availability_windows WHERE category_id = chain.id)
+        ORDER BY chain.depth
+        LIMIT 1
+    )
+    SELECT CASE
+        WHEN EXISTS (SELECT 1 FROM availability_windows WHERE product_id = p_product_id) THEN
+            EXISTS (SELECT 1 FROM availability_windows
+                    WHERE product_id = p_product_id AND day_of_week = p_day
+                      AND p_minute >= start_minute AND p_minute < end_minute)
+        WHEN EXISTS (SELECT 1 FROM nearest) THEN
+            EXISTS (SELECT 1 FROM availability_windows
+                    WHERE category_id = (SELECT id FROM nearest) AND day_of_week = p_day
+                      AND p_minute >= start_minute AND p_minute < end_minute)
+        ELSE true
+    END
+$$;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION product_available_at(p_product_id UUID, p_category_id UUID, p_day INTEGER, p_minute INTEGER)
+RETURNS BOOLEAN
+LANGUAGE sql STABLE
+AS $$
+    SELECT CASE
+        WHEN EXISTS (SELECT 1 FROM availability_windows WHERE product_id = p_product_id) THEN
+            EXISTS (SELECT 1 FROM availability_windows
+                    WHERE product_id = p_product_id AND day_of_week = p_day
+                      AND p_minute >= start_minute AND p_minute < end_minute)
+        WHEN EXISTS (SELECT 1 FROM availability_windows WHERE category_id = p_category_id) THEN
+            EXISTS (SELECT 1 FROM availability_windows
+                    WHERE category_id = p_category_id AND day_of_week = p_day
+                      AND p_minute >= start_minute AND p_minute < end_minute)
+        ELSE true
+    END
+$$;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- Get Category by ID
+-- name: GetCategory :one
+SELECT id, name, parent_id, display_order, created_at
+FROM categories
+WHERE id = $1;
+
+
+-- Get a Category by name, top level categories win over nested ones of the same name
+-- name: GetCategoryByName :one
+SELECT id, name, parent_id, display_order, created_at
+FROM categories
+WHERE name = $1
+ORDER BY parent_id NULLS FIRST, id
+LIMIT 1;
+
+
+-- List every Category in display order, the tree is built by the caller
+-- name: ListCategories :many
+SELECT id, name, parent_id, display_order, created_at
+FROM categories
+ORDER BY display_order, name;
@@ -1,6 +1,114 @@
--- Get Coupon by ID
--- name: GetCouponByID :one
-SELECT id 
+-- Check a coupon code is enabled and either manual or in at least min_sources of the coupon files
+-- name: CheckCouponSources :one
+SELECT EXISTS (
+    SELECT 1
+    FROM coupons
+    WHERE code = sqlc.arg(code)
+      AND disabled_at IS NULL
+      AND (manual OR bit_count(sources::bit(32)) >= sqlc.arg(min_sources)::INTEGER)
+);
+
+-- name: GetCoupon :one
+SELECT code, sources, manual, disabled_at
 FROM coupons
-WHERE id = $1;
+WHERE code = $1;
+
+-- Create a manual coupon, returns no rows when the code already exists
+-- name: CreateCoupon :one
+INSERT INTO coupons (
+    code,
+    sources,
+    manual
+) VALUES (
+    $1,
+    0,
+    true
+) ON CONFLICT (code) DO NOTHING
+RETURNING *;
+
+-- name: SetCouponDisabledAt :one
+UPDATE coupons
+SET disabled_at = sqlc.narg(disabled_at)
+WHERE code = sqlc.arg(code)
+RETURNING *;
+
+
+-- name: AddCouponRedemption :one
+INSERT INTO coupon_redemptions (
+    id,
+    coupon_code,
+    order_id,
+    created_at,
+    customer_id
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+) RETURNING *;
+
+-- Release the coupon redemptions held by an Order
+-- name: ReleaseCouponRedemptions :execrows
+UPDATE coupon_redemptions
+SET released_at = sqlc.arg(released_at)
+WHERE order_id = sqlc.arg(order_id) AND released_at IS NULL;
+
+-- List the discount rules attached to a coupon code
+-- name: ListCouponRules :many
+SELECT id, coupon_code, kind, value, category, min_basket, created_at
+FROM coupon_rules
+WHERE coupon_code = $1
+ORDER BY created_at, id;
+
+-- Lock the limits of a coupon until the surrounding transaction ends
+-- name: GetCouponLimitsForUpdate :one
+SELECT coupon_code, max_redemptions, per_customer_limit, valid_from, valid_until
+FROM coupon_limits
+WHERE coupon_code = $1
+FOR UPDATE;
+
+-- name: CountActiveCouponRedemptions :one
+SELECT COUNT(*)
+FROM coupon_redemptions
+WHERE coupon_code = $1 AND released_at IS NULL;
+
+-- name: CountActiveCustomerCouponRedemptions :one
+SELECT COUNT(*)
+FROM coupon_redemptions
+WHERE coupon_code = $1 AND customer_id = $2 AND released_at IS NULL;
+
+-- Page through coupon codes in key order, used to build the in-memory coupon index
+-- name: ListCouponsAfter :many
+SELECT code, sources, manual, disabled_at
+FROM coupons
+WHERE code > sqlc.arg(after)
+ORDER BY code
+LIMIT sqlc.arg(row_limit);
+
+-- name: AddCouponRule :one
+INSERT INTO coupon_rules (
+    id,
+    coupon_code,
+    kind,
+    value,
+    category,
+    min_basket,
+    created_at
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7
+) RETURNING *;
 
+-- List the most recent redemptions of a coupon code, released ones included
+-- name: ListCouponRedemptions :many
+SELECT id, coupon_code, order_id, created_at, released_at, customer_id
+FROM coupon_redemptions
+WHERE coupon_code = sqlc.arg(coupon_code)
+ORDER BY created_at DESC, id
+LIMIT sqlc.arg(row_limit);
@@ -0,0 +1,9 @@
+-- List the Modifiers of every group belonging to the given Products, groups
+-- without any Modifier are left out
+-- name: ListModifiersByProductIDs :many
+SELECT g.id AS group_id, g.product_id, g.name AS group_name, g.min_select, g.max_select,
+       m.id, m.name, m.price_delta
+FROM modifier_groups g
+JOIN modifiers m ON m.group_id = g.id
+WHERE g.product_id = ANY(sqlc.arg(product_ids)::uuid[])
+ORDER BY g.product_id, g.display_order, g.name, m.display_order, m.name;
@@ -2,20 +2,123 @@
 INSERT INTO orders (
     id,
     coupon_code,
-    created_at
+    created_at,
+    subtotal,
+    discount,
+    total,
+    status,
+    customer_id
 ) VALUES (
     $1,
     $2,
-    $3
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8
 ) RETURNING *;
 
 -- name: AddProductToOrder :one
 INSERT INTO order_product (
     id,
     order_id,
-    product_id
+    product_id,
+    quantity,
+    unit_price
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+) RETURNING *;
+
+-- name: AddModifierToOrderLine :one
+INSERT INTO order_product_modifiers (
+    id,
+    order_product_id,
+    modifier_id,
+    name,
+    price_delta
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+) RETURNING *;
+
+-- Get Order by ID
+-- name: GetOrderByID :one
+SELECT id, coupon_code, created_at, subtotal, discount, total, status, customer_id
+FROM orders
+WHERE id = $1;
+
+-- List the lines of an Order joined with their Products
+-- name: ListOrderLines :many
+SELECT op.id, op.product_id, op.quantity, op.unit_price, p.name, p.category, p.price
+FROM order_product op
+JOIN products p ON p.id = op.product_id
+WHERE op.order_id = $1
+ORDER BY op.id;
+
+-- List the Modifiers chosen for every line of an Order
+-- name: ListOrderLineModifiers :many
+SELECT opm.order_product_id, opm.modifier_id, opm.name, opm.price_delta
+FROM order_product_modifiers opm
+JOIN order_product op ON op.id = opm.order_product_id
+WHERE op.order_id = $1
+ORDER BY opm.order_product_id, opm.name;
+
+-- List Orders oldest first using keyset pagination on (created_at, id)
+-- name: ListOrdersAsc :many
+SELECT id, coupon_code, created_at, subtotal, discount, total, status, customer_id
+FROM orders
+WHERE (sqlc.narg(created_from)::bigint IS NULL OR created_at >= sqlc.narg(created_from))
+  AND (sqlc.narg(created_to)::bigint IS NULL OR created_at <= sqlc.narg(created_to))
+  AND (sqlc.narg(coupon_code)::text IS NULL OR coupon_code = sqlc.narg(coupon_code))
+  AND (sqlc.narg(cursor_created_at)::bigint IS NULL OR (created_at, id) > (sqlc.narg(cursor_created_at), sqlc.narg(cursor_id)::uuid))
+ORDER BY created_at ASC, id ASC
+LIMIT sqlc.arg(row_limit);
+
+-- List Orders newest first using keyset pagination on (created_at, id)
+-- name: ListOrdersDesc :many
+SELECT id, coupon_code, created_at, subtotal, discount, total, status, customer_id
+FROM orders
+WHERE (sqlc.narg(created_from)::bigint IS NULL OR created_at >= sqlc.narg(created_from))
+  AND (sqlc.narg(created_to)::bigint IS NULL OR created_at <= sqlc.narg(created_to))
+  AND (sqlc.narg(coupon_code)::text IS NULL OR coupon_code = sqlc.narg(coupon_code))
+  AND (sqlc.narg(cursor_created_at)::bigint IS NULL OR (created_at, id) < (sqlc.narg(cursor_created_at), sqlc.narg(cursor_id)::uuid))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(row_limit);
+
+-- Move an Order to a new status only if it is still in the expected status
+-- name: UpdateOrderStatus :execrows
+UPDATE orders
+SET status = sqlc.arg(to_status)
+WHERE id = sqlc.arg(id) AND status = sqlc.arg(from_status);
+
+-- name: AddOrderStatusTransition :one
+INSERT INTO order_status_transitions (
+    id,
+    order_id,
+    from_status,
+    to_status,
+    reason,
+    created_at
 ) VALUES (
     $1,
     $2,
-    $3
+    $3,
+    $4,
+    $5,
+    $6
 ) RETURNING *;
+
+-- List the status history of an Order
+-- name: ListOrderStatusTransitions :many
+SELECT id, order_id, from_status, to_status, reason, created_at
+FROM order_status_transitions
+WHERE order_id = $1
+ORDER BY created_at, id;
@@ -1,12 +1,174 @@
+-- Add delta to the stock of a Product, untracked stock counts as zero. No row is returned when the stock would go below zero
+-- name: AdjustProductStock :one
+UPDATE products
+SET stock = COALESCE(stock, 0) + sqlc.arg(delta)::integer,
+    updated_at = sqlc.arg(updated_at)
+WHERE id = sqlc.arg(id) AND deleted_at IS NULL
+  AND COALESCE(stock, 0) + sqlc.arg(delta)::integer >= 0
+RETURNING *;
+
+
+-- Create a Product
+-- name: CreateProduct :one
+INSERT INTO products (
+    id,
+    name,
+    category,
+    category_id,
+    price,
+    created_at
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6
+) RETURNING *;
+
+
+-- Soft delete a Product, it stays referenced by past orders
+-- name: DeleteProduct :execrows
+UPDATE products
+SET deleted_at = $1
+WHERE id = $2 AND deleted_at IS NULL;
+
+
 -- Get Product by ID
 -- name: GetProductByID :one
-SELECT id, name, category, price, created_at
+SELECT id, name, category, price, created_at, updated_at, deleted_at, category_id, stock
 FROM products
-WHERE id = $1;
+WHERE id = $1 AND deleted_at IS NULL;
 
 
--- List all Products
+-- List all Products, only those available at the given day and minute when one is given
 -- name: ListProducts :many
-SELECT id, name, category, price, created_at
+SELECT id, name, category, price, created_at, updated_at, deleted_at, category_id, stock
+FROM products
+WHERE deleted_at IS NULL
+  AND (sqlc.narg(available_day)::integer IS NULL
+    OR product_available_at(id, category_id, sqlc.narg(available_day), sqlc.narg(available_minute)::integer))
+ORDER BY name;
+
+
+-- List Products matching any of the given IDs
+-- name: ListProductsByIDs :many
+SELECT id, name, category, price, created_at, updated_at, deleted_at, category_id, stock
 FROM products
+WHERE id = ANY(sqlc.arg(ids)::uuid[]) AND deleted_at IS NULL
 ORDER BY name;
+
+
+-- List a page of Products matching the filters using keyset pagination on the sort key and id
+-- name: ListProductsPage :many
+SELECT id, name, category, price, created_at, updated_at, deleted_at, category_id, stock
+FROM products
+WHERE deleted_at IS NULL
+  AND (sqlc.narg(category)::text IS NULL OR category = sqlc.narg(category))
+  AND (sqlc.narg(min_price)::float IS NULL OR price >= sqlc.narg(min_price))
+  AND (sqlc.narg(max_price)::float IS NULL OR price <= sqlc.narg(max_price))
+  AND (sqlc.narg(q)::text IS NULL OR strpos(lower(name), lower(sqlc.narg(q))) > 0)
+  AND (sqlc.narg(cursor_id)::uuid IS NULL
+    OR (sqlc.arg(sort)::text = 'name' AND (name, id) > (sqlc.narg(cursor_name)::text, sqlc.narg(cursor_id)))
+    OR (sqlc.arg(sort) = '-name' AND (name, id) < (sqlc.narg(cursor_name), sqlc.narg(cursor_id)))
+    OR (sqlc.arg(sort) = 'price' AND (price, id) > (sqlc.narg(cursor_price)::float, sqlc.narg(cursor_id)))
+    OR (sqlc.arg(sort) = '-price' AND (price, id) < (sqlc.narg(cursor_price), sqlc.narg(cursor_id)))
+    OR (sqlc.arg(sort) = 'created_at' AND (created_at, id) > (sqlc.narg(cursor_created_at)::bigint, sqlc.narg(cursor_id)))
+    OR (sqlc.arg(sort) = '-created_at' AND (created_at, id) < (sqlc.narg(cursor_created_at), sqlc.narg(cursor_id))))
+  AND (sqlc.narg(available_day)::integer IS NULL
+    OR product_available_at(id, category_id, sqlc.narg(available_day), sqlc.narg(available_minute)::integer))
+ORDER BY
+  CASE WHEN sqlc.arg(sort) = 'name' THEN name END ASC,
+  CASE WHEN sqlc.arg(sort) = '-name' THEN name END DESC,
+  CASE WHEN sqlc.arg(sort) = 'price' THEN price END ASC,
+  CASE WHEN sqlc.arg(sort) = '-price' THEN price END DESC,
+  CASE WHEN sqlc.arg(sort) = 'created_at' THEN created_at END ASC,
+  CASE WHEN sqlc.arg(sort) = '-created_at' THEN created_at END DESC,
+  CASE WHEN left(sqlc.arg(sort), 1) = '-' THEN id END DESC,
+  id ASC
+LIMIT sqlc.arg(row_limit);
+
+
+-- List the given Products that are outside their availability windows
+-- name: ListUnavailableProductIDs :many
+SELECT id
+FROM products
+WHERE id = ANY(sqlc.arg(ids)::uuid[])
+  AND NOT product_available_at(id, category_id, sqlc.arg(available_day)::integer, sqlc.arg(available_minute)::integer)
+ORDER BY id;
+
+
+-- Update only the fields of a Product that are given
+-- name: PatchProduct :one
+UPDATE products
+SET name = COALESCE(sqlc.narg(name), name),
+    category = COALESCE(sqlc.narg(category), category),
+    category_id = COALESCE(sqlc.narg(category_id), category_id),
+    price = COALESCE(sqlc.narg(price), price),
+    updated_at = sqlc.arg(updated_at)
+WHERE id = sqlc.arg(id) AND deleted_at IS NULL
+RETURNING *;
+
+
+-- Take quantity off the stock of a Product, untracked stock is left as is. No row is updated when there is not enough stock
+-- name: ReserveProductStock :execrows
+UPDATE products
+SET stock = stock - sqlc.arg(quantity)::integer
+WHERE id = sqlc.arg(id)
+  AND (stock IS NULL OR stock >= sqlc.arg(quantity)::integer);
+
+
+-- Give back the stock held by the lines of an Order, summed per Product as a Product can be on several lines
+-- name: ReleaseOrderStock :execrows
+UPDATE products p
+SET stock = p.stock + op.quantity
+FROM (
+    SELECT product_id, SUM(quantity)::integer AS quantity
+    FROM order_product
+    WHERE order_id = $1
+    GROUP BY product_id
+) op
+WHERE p.id = op.product_id AND p.stock IS NOT NULL;
+
+
+-- Set the stock of a Product, null stops tracking it
+-- name: SetProductStock :one
+UPDATE products
+SET stock = sqlc.narg(stock),
+    updated_at = sqlc.arg(updated_at)
+WHERE id = sqlc.arg(id) AND deleted_at IS NULL
+RETURNING *;
+
+
+-- Replace every field of a Product
+-- name: UpdateProduct :one
+UPDATE products
+SET name = $1,
+    category = $2,
+    category_id = $3,
+    price = $4,
+    updated_at = $5
+WHERE id = $6 AND deleted_at IS NULL
+RETURNING *;
+
+
+-- Search Products by full-text match on name and category, falling back to trigram word similarity on the name to catch typos
+-- name: SearchProducts :many
+SELECT p.id, p.name, p.category, p.price, p.created_at, p.category_id, p.stock,
+    (s.document @@ s.query)::boolean AS full_text,
+    (CASE WHEN s.document @@ s.query THEN ts_rank(s.document, s.query)
+          ELSE word_similarity(sqlc.arg(q), p.name) END)::real AS score,
+    ts_headline('english', p.name, s.query, 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true')::text AS headline
+FROM products p
+CROSS JOIN LATERAL (
+    SELECT setweight(to_tsvector('english', p.name), 'A') ||
+           setweight(to_tsvector('english', coalesce(p.category, '')), 'B') AS document,
+           websearch_to_tsquery('english', sqlc.arg(q)) AS query
+) s
+-- the filter repeats the indexed expressions verbatim, a LATERAL column would hide them from the planner
+WHERE p.deleted_at IS NULL
+  AND ((setweight(to_tsvector('english', p.name), 'A') ||
+        setweight(to_tsvector('english', coalesce(p.category, '')), 'B')) @@ websearch_to_tsquery('english', sqlc.arg(q))
+       OR sqlc.arg(q) <% p.name)
+ORDER BY full_text DESC, score DESC, p.name, p.id
+LIMIT sqlc.arg(row_limit);
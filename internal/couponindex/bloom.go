@@ -0,0 +1,60 @@
+package couponindex
+
+import "math"
+
+// bloom is a fixed size Bloom filter over packed codes. It never reports a false
+// negative which lets the index turn most unknown codes away without a search.
+type bloom struct {
+	bits []uint64
+	m    uint64
+	k    uint64
+}
+
+func newBloom(n int, fpRate float64) *bloom {
+	if n < 1 {
+		n = 1
+	}
+
+	m := uint64(math.Ceil(-float64(n) * math.Log(fpRate) / (math.Ln2 * math.Ln2)))
+	m = max(m, 64)
+	k := uint64(math.Round(float64(m) / float64(n) * math.Ln2))
+	k = max(k, 1)
+
+	return &bloom{
+		bits: make([]uint64, (m+63)/64),
+		m:    m,
+		k:    k,
+	}
+}
+
+func (b *bloom) add(v uint64) {
+	h1, h2 := hashes(v)
+	for i := uint64(0); i < b.k; i++ {
+		bit := (h1 + i*h2) % b.m
+		b.bits[bit/64] |= 1 << (bit % 64)
+	}
+}
+
+func (b *bloom) mayContain(v uint64) bool {
+	h1, h2 := hashes(v)
+	for i := uint64(0); i < b.k; i++ {
+		bit := (h1 + i*h2) % b.m
+		if b.bits[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// hashes derives the two hashes used for double hashing from splitmix64.
+func hashes(v uint64) (uint64, uint64) {
+	h1 := mix(v)
+	h2 := mix(v^0x9e3779b97f4a7c15) | 1
+	return h1, h2
+}
+
+func mix(z uint64) uint64 {
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
@@ -0,0 +1,291 @@
+package couponindex
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/bits"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sgrumley/kart-challenge/pkg/logger"
+)
+
+// MinSources is the number of coupon files a code must appear in to be valid.
+const MinSources = 2
+
+var ErrLoadInProgress = errors.New("coupon index load already in progress")
+
+// Source streams every enabled coupon code.
+type Source interface {
+	ScanCoupons(ctx context.Context, fn func(Entry) error) error
+}
+
+// Entry is a coupon code with a bitmask of the coupon files it appears in, bit
+// n-1 standing for file n. Manual codes are valid whatever their sources.
+type Entry struct {
+	Code    string
+	Sources uint32
+	Manual  bool
+}
+
+type (
+	Option func(*Index)
+
+	// Stats describes the currently loaded index.
+	Stats struct {
+		Loaded   bool
+		Loading  bool
+		LoadedAt time.Time
+		Duration time.Duration
+		Codes    map[int]int
+		Skipped  int
+		Bloom    bool
+		LastErr  string
+	}
+)
+
+// WithBloomFilter puts a Bloom filter with the given false positive rate in
+// front of the sorted arrays. A rate outside (0, 1) disables the filter.
+func WithBloomFilter(fpRate float64) Option {
+	return func(i *Index) {
+		i.bloomFPRate = fpRate
+	}
+}
+
+// Index answers whether a coupon code appears in at least two coupon files from
+// memory. Each file is held as a sorted array of packed codes. The whole set is
+// swapped in once a load completes so lookups never see a partial index.
+type Index struct {
+	source      Source
+	bloomFPRate float64
+
+	set     atomic.Pointer[set]
+	loading atomic.Bool
+
+	mu    sync.RWMutex
+	stats Stats
+
+	// overrides hold codes changed since the load started, keyed by code, so
+	// admin changes apply straight away instead of at the next reload
+	overrideMu  sync.RWMutex
+	overrides   map[string]override
+	overrideSeq uint64
+}
+
+type set struct {
+	sources map[int][]uint64
+	manual  []uint64
+	bloom   *bloom
+}
+
+type override struct {
+	valid bool
+	seq   uint64
+}
+
+func New(source Source, opts ...Option) *Index {
+	idx := &Index{
+		source: source,
+	}
+
+	for _, opt := range opts {
+		opt(idx)
+	}
+
+	return idx
+}
+
+// Contains reports whether the code is a valid coupon. ok is false when the
+// index cannot answer, either because it has not been loaded yet or because the
+// code cannot be packed, in which case the caller should ask the database.
+func (i *Index) Contains(code string) (valid bool, ok bool) {
+	s := i.set.Load()
+	if s == nil {
+		return false, false
+	}
+
+	i.overrideMu.RLock()
+	o, found := i.overrides[code]
+	i.overrideMu.RUnlock()
+	if found {
+		return o.valid, true
+	}
+
+	packed, ok := Pack(code)
+	if !ok {
+		return false, false
+	}
+
+	if s.bloom != nil && !s.bloom.mayContain(packed) {
+		return false, true
+	}
+
+	if _, found := slices.BinarySearch(s.manual, packed); found {
+		return true, true
+	}
+
+	matches := 0
+	for _, codes := range s.sources {
+		if _, found := slices.BinarySearch(codes, packed); found {
+			matches++
+			if matches >= MinSources {
+				return true, true
+			}
+		}
+	}
+
+	return false, true
+}
+
+// Override records whether a code is valid after it was changed in the store.
+// It is kept until a load that started after the change completes.
+func (i *Index) Override(code string, valid bool) {
+	i.overrideMu.Lock()
+	defer i.overrideMu.Unlock()
+
+	if i.overrides == nil {
+		i.overrides = make(map[string]override)
+	}
+	i.overrideSeq++
+	i.overrides[code] = override{valid: valid, seq: i.overrideSeq}
+}
+
+func (i *Index) Loaded() bool {
+	return i.set.Load() != nil
+}
+
+func (i *Index) Loading() bool {
+	return i.loading.Load()
+}
+
+func (i *Index) Stats() Stats {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	stats := i.stats
+	stats.Loading = i.Loading()
+	return stats
+}
+
+// Load builds a fresh index from the source and swaps it in. Lookups keep using
+// the previous index, or the database, until the load finishes.
+func (i *Index) Load(ctx context.Context) error {
+	if !i.loading.CompareAndSwap(false, true) {
+		return ErrLoadInProgress
+	}
+	defer i.loading.Store(false)
+
+	return i.load(ctx)
+}
+
+// LoadAsync starts Load in the background and returns straight away.
+func (i *Index) LoadAsync(ctx context.Context) error {
+	if !i.loading.CompareAndSwap(false, true) {
+		return ErrLoadInProgress
+	}
+
+	go func() {
+		defer i.loading.Store(false)
+		if err := i.load(ctx); err != nil {
+			logger.Error(ctx, "failed loading coupon index", err)
+		}
+	}()
+
+	return nil
+}
+
+func (i *Index) load(ctx context.Context) error {
+	start := time.Now()
+	logger.Info(ctx, "loading coupon index")
+
+	i.overrideMu.RLock()
+	startSeq := i.overrideSeq
+	i.overrideMu.RUnlock()
+
+	sources := make(map[int][]uint64)
+	var manual []uint64
+	skipped := 0
+	err := i.source.ScanCoupons(ctx, func(e Entry) error {
+		packed, ok := Pack(e.Code)
+		if !ok {
+			skipped++
+			return nil
+		}
+		if e.Manual {
+			manual = append(manual, packed)
+		}
+		for mask := e.Sources; mask != 0; {
+			source := bits.TrailingZeros32(mask) + 1
+			sources[source] = append(sources[source], packed)
+			mask &= mask - 1
+		}
+		return nil
+	})
+	if err != nil {
+		i.mu.Lock()
+		i.stats.LastErr = err.Error()
+		i.mu.Unlock()
+		return err
+	}
+
+	slices.Sort(manual)
+	s := &set{
+		sources: sources,
+		manual:  slices.Clip(slices.Compact(manual)),
+	}
+	counts := make(map[int]int, len(sources))
+	total := 0
+	for source, codes := range sources {
+		slices.Sort(codes)
+		codes = slices.Clip(slices.Compact(codes))
+		sources[source] = codes
+		counts[source] = len(codes)
+		total += len(codes)
+	}
+
+	if i.bloomFPRate > 0 && i.bloomFPRate < 1 {
+		s.bloom = newBloom(total+len(s.manual), i.bloomFPRate)
+		for _, codes := range sources {
+			for _, c := range codes {
+				s.bloom.add(c)
+			}
+		}
+		for _, c := range s.manual {
+			s.bloom.add(c)
+		}
+	}
+
+	i.set.Store(s)
+
+	// the new set reflects every change made before the load started
+	i.overrideMu.Lock()
+	for code, o := range i.overrides {
+		if o.seq <= startSeq {
+			delete(i.overrides, code)
+		}
+	}
+	i.overrideMu.Unlock()
+
+	duration := time.Since(start)
+	i.mu.Lock()
+	i.stats = Stats{
+		Loaded:   true,
+		LoadedAt: start.Add(duration),
+		Duration: duration,
+		Codes:    counts,
+		Skipped:  skipped,
+		Bloom:    s.bloom != nil,
+	}
+	i.mu.Unlock()
+
+	logger.Info(ctx, "coupon index loaded",
+		slog.Int("codes", total),
+		slog.Int("skipped", skipped),
+		slog.Duration("duration", duration),
+	)
+
+	return nil
+}
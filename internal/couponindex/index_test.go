@@ -0,0 +1,172 @@
+package couponindex
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sourceFunc func(ctx context.Context, fn func(Entry) error) error
+
+func (f sourceFunc) ScanCoupons(ctx context.Context, fn func(Entry) error) error {
+	return f(ctx, fn)
+}
+
+func staticSource(coupons map[int][]string, manual ...string) Source {
+	return sourceFunc(func(ctx context.Context, fn func(Entry) error) error {
+		masks := map[string]uint32{}
+		for source, codes := range coupons {
+			for _, code := range codes {
+				masks[code] |= 1 << (source - 1)
+			}
+		}
+		for code, mask := range masks {
+			if err := fn(Entry{Code: code, Sources: mask}); err != nil {
+				return err
+			}
+		}
+		for _, code := range manual {
+			if err := fn(Entry{Code: code, Manual: true}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func Test_Pack(t *testing.T) {
+	t.Parallel()
+	testCases := map[string]struct {
+		code   string
+		wantOK bool
+	}{
+		"success/letters":         {code: "HAPPYHRS", wantOK: true},
+		"success/digits":          {code: "SUPER100", wantOK: true},
+		"success/max_length":      {code: "ABCDEFGHIJKL", wantOK: true},
+		"error/empty":             {code: "", wantOK: false},
+		"error/too_long":          {code: "ABCDEFGHIJKLM", wantOK: false},
+		"error/lower_case":        {code: "happyhrs", wantOK: false},
+		"error/invalid_character": {code: "HAPPY-HRS", wantOK: false},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, ok := Pack(tc.code)
+			assert.Equal(t, tc.wantOK, ok)
+		})
+	}
+
+	// codes that differ only by length or a trailing zero must not collide
+	seen := map[uint64]string{}
+	for _, code := range []string{"A", "AA", "A0", "0", "00", "0A", "ZZZZZZZZZZZZ", "000000000000"} {
+		packed, ok := Pack(code)
+		require.True(t, ok)
+		require.NotContains(t, seen, packed, "%s collides with %s", code, seen[packed])
+		seen[packed] = code
+	}
+}
+
+func Test_Index_Contains(t *testing.T) {
+	t.Parallel()
+	coupons := map[int][]string{
+		1: {"HAPPYHRS", "FIFTYOFF", "ONLYONE1"},
+		2: {"HAPPYHRS", "FIFTYOFF", "SUPER100"},
+		3: {"FIFTYOFF", "SUPER100", "BADCODE!"},
+	}
+
+	for _, opts := range map[string][]Option{
+		"sorted_arrays": nil,
+		"bloom_filter":  {WithBloomFilter(0.01)},
+	} {
+		idx := New(staticSource(coupons, "MANUAL01"), opts...)
+
+		valid, ok := idx.Contains("HAPPYHRS")
+		assert.False(t, valid)
+		assert.False(t, ok, "index should not answer before it is loaded")
+
+		require.NoError(t, idx.Load(context.Background()))
+
+		testCases := map[string]struct {
+			code      string
+			wantValid bool
+			wantOK    bool
+		}{
+			"success/two_sources":   {code: "HAPPYHRS", wantValid: true, wantOK: true},
+			"success/three_sources": {code: "FIFTYOFF", wantValid: true, wantOK: true},
+			"success/last_sources":  {code: "SUPER100", wantValid: true, wantOK: true},
+			"success/manual":        {code: "MANUAL01", wantValid: true, wantOK: true},
+			"error/one_source":      {code: "ONLYONE1", wantValid: false, wantOK: true},
+			"error/unknown":         {code: "MISSING1", wantValid: false, wantOK: true},
+			"error/not_packable":    {code: "BADCODE!", wantValid: false, wantOK: false},
+		}
+
+		for name, tc := range testCases {
+			valid, ok := idx.Contains(tc.code)
+			assert.Equal(t, tc.wantValid, valid, name)
+			assert.Equal(t, tc.wantOK, ok, name)
+		}
+
+		stats := idx.Stats()
+		assert.True(t, stats.Loaded)
+		assert.Equal(t, map[int]int{1: 3, 2: 3, 3: 2}, stats.Codes)
+		assert.Equal(t, 1, stats.Skipped)
+	}
+}
+
+func Test_Index_LoadError(t *testing.T) {
+	t.Parallel()
+	idx := New(sourceFunc(func(ctx context.Context, fn func(Entry) error) error {
+		return fmt.Errorf("connection refused")
+	}))
+
+	require.Error(t, idx.Load(context.Background()))
+	assert.False(t, idx.Loaded())
+	assert.Equal(t, "connection refused", idx.Stats().LastErr)
+}
+
+func Test_Index_Override(t *testing.T) {
+	t.Parallel()
+	idx := New(staticSource(map[int][]string{
+		1: {"HAPPYHRS"},
+		2: {"HAPPYHRS"},
+	}))
+	require.NoError(t, idx.Load(context.Background()))
+
+	idx.Override("HAPPYHRS", false)
+	idx.Override("CREATED1", true)
+
+	valid, ok := idx.Contains("HAPPYHRS")
+	assert.True(t, ok)
+	assert.False(t, valid, "disabled code should be invalid before the next load")
+
+	valid, ok = idx.Contains("CREATED1")
+	assert.True(t, ok)
+	assert.True(t, valid, "created code should be valid before the next load")
+
+	// a load started after the changes replaces the overrides
+	require.NoError(t, idx.Load(context.Background()))
+	valid, _ = idx.Contains("HAPPYHRS")
+	assert.True(t, valid)
+	valid, _ = idx.Contains("CREATED1")
+	assert.False(t, valid)
+}
+
+func Test_Bloom_NoFalseNegatives(t *testing.T) {
+	t.Parallel()
+	b := newBloom(10_000, 0.01)
+	for i := uint64(0); i < 10_000; i++ {
+		b.add(i * 7919)
+	}
+
+	falsePositives := 0
+	for i := uint64(0); i < 10_000; i++ {
+		require.True(t, b.mayContain(i*7919))
+		if b.mayContain(i*7919 + 1) {
+			falsePositives++
+		}
+	}
+	assert.Less(t, falsePositives, 500)
+}
@@ -0,0 +1,34 @@
+package couponindex
+
+// maxCodeLen is the longest code that still fits in a uint64 once packed.
+const maxCodeLen = 12
+
+// Pack encodes a code made of upper case letters and digits into a uint64 using
+// bijective base 37, so every distinct code maps to a distinct value. Codes that
+// are empty, too long or contain other characters cannot be packed.
+func Pack(code string) (uint64, bool) {
+	if len(code) == 0 || len(code) > maxCodeLen {
+		return 0, false
+	}
+
+	var packed uint64
+	for i := 0; i < len(code); i++ {
+		d, ok := digit(code[i])
+		if !ok {
+			return 0, false
+		}
+		packed = packed*37 + d
+	}
+
+	return packed, true
+}
+
+func digit(c byte) (uint64, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return uint64(c-'0') + 1, true
+	case c >= 'A' && c <= 'Z':
+		return uint64(c-'A') + 11, true
+	}
+	return 0, false
+}
@@ -0,0 +1,11 @@
+package v1
+
+import (
+	"github.com/go-chi/chi/v5"
+)
+
+func (s *CategoryService) GetRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Get("/category", s.ListCategories)
+	})
+}
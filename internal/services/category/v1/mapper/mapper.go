@@ -0,0 +1,37 @@
+package mapper
+
+import "github.com/sgrumley/kart-challenge/pkg/models"
+
+type Category struct {
+	ID           string     `json:"id"`
+	Name         string     `json:"name"`
+	DisplayOrder int        `json:"display_order"`
+	Children     []Category `json:"children"`
+}
+
+type ListCategoriesResponse []Category
+
+// CategoryTreeToResponse nests the categories under their parent. The input
+// order is kept between siblings so it must already be in display order.
+func CategoryTreeToResponse(categories []models.Category) ListCategoriesResponse {
+	children := make(map[string][]models.Category, len(categories))
+	for _, c := range categories {
+		children[c.ParentID] = append(children[c.ParentID], c)
+	}
+
+	var build func(parentID string) []Category
+	build = func(parentID string) []Category {
+		nodes := make([]Category, len(children[parentID]))
+		for i, c := range children[parentID] {
+			nodes[i] = Category{
+				ID:           c.ID,
+				Name:         c.Name,
+				DisplayOrder: c.DisplayOrder,
+				Children:     build(c.ID),
+			}
+		}
+		return nodes
+	}
+
+	return build("")
+}
@@ -0,0 +1,45 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/sgrumley/kart-challenge/internal/services/category/v1/mapper"
+	"github.com/sgrumley/kart-challenge/internal/store"
+	"github.com/sgrumley/kart-challenge/pkg/logger"
+	"github.com/sgrumley/kart-challenge/pkg/models"
+	"github.com/sgrumley/kart-challenge/pkg/web"
+)
+
+//go:generate moq -out ./mocks_test.go . CategoryStorable
+
+var _ CategoryStorable = (*store.Store)(nil)
+
+type CategoryStorable interface {
+	ListCategories(ctx context.Context) ([]models.Category, error)
+}
+
+func NewService(store CategoryStorable) *CategoryService {
+	return &CategoryService{
+		store: store,
+	}
+}
+
+type CategoryService struct {
+	store CategoryStorable
+}
+
+// ListCategories returns the category tree, siblings are in display order.
+func (s *CategoryService) ListCategories(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	categories, err := s.store.ListCategories(ctx)
+	if err != nil {
+		logger.Error(ctx, "failed listing categories in store", err)
+		web.RespondJSONError(w, fmt.Errorf("failed listing categories in store: %w", err))
+		return
+	}
+
+	web.Respond(w, http.StatusOK, mapper.CategoryTreeToResponse(categories))
+}
@@ -0,0 +1,100 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	"github.com/sgrumley/kart-challenge/internal/services/category/v1/mapper"
+	"github.com/sgrumley/kart-challenge/pkg/logger"
+	"github.com/sgrumley/kart-challenge/pkg/models"
+	"github.com/sgrumley/kart-challenge/pkg/testhelper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_API_Service_ListCategories(t *testing.T) {
+	t.Parallel()
+	testCases := map[string]struct {
+		storeMock     *CategoryStorableMock
+		wantAssertion func(t *testing.T, got *http.Response, storeMock *CategoryStorableMock)
+	}{
+		"success/nested": {
+			storeMock: &CategoryStorableMock{
+				ListCategoriesFunc: func(ctx context.Context) ([]models.Category, error) {
+					return []models.Category{
+						{ID: "drinks", Name: "Beverages", DisplayOrder: 1},
+						{ID: "hot", Name: "Hot Drinks", ParentID: "drinks", DisplayOrder: 1},
+						{ID: "desserts", Name: "Desserts", DisplayOrder: 2},
+						{ID: "cold", Name: "Cold Drinks", ParentID: "drinks", DisplayOrder: 2},
+					}, nil
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *CategoryStorableMock) {
+				require.Equal(t, http.StatusOK, got.StatusCode)
+				require.Len(t, storeMock.ListCategoriesCalls(), 1)
+
+				want := mapper.ListCategoriesResponse{
+					{
+						ID:           "drinks",
+						Name:         "Beverages",
+						DisplayOrder: 1,
+						Children: []mapper.Category{
+							{ID: "hot", Name: "Hot Drinks", DisplayOrder: 1, Children: []mapper.Category{}},
+							{ID: "cold", Name: "Cold Drinks", DisplayOrder: 2, Children: []mapper.Category{}},
+						},
+					},
+					{ID: "desserts", Name: "Desserts", DisplayOrder: 2, Children: []mapper.Category{}},
+				}
+
+				actual := testhelper.PayloadAsType[mapper.ListCategoriesResponse](t, got.Body)
+				assert.Equal(t, want, actual)
+			},
+		},
+		"success/empty": {
+			storeMock: &CategoryStorableMock{
+				ListCategoriesFunc: func(ctx context.Context) ([]models.Category, error) {
+					return []models.Category{}, nil
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *CategoryStorableMock) {
+				require.Equal(t, http.StatusOK, got.StatusCode)
+				assert.JSONEq(t, "[]", testhelper.PayloadAsString(t, got.Body))
+			},
+		},
+		"error/store_failure": {
+			storeMock: &CategoryStorableMock{
+				ListCategoriesFunc: func(ctx context.Context) ([]models.Category, error) {
+					return nil, fmt.Errorf("error")
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *CategoryStorableMock) {
+				require.Equal(t, http.StatusInternalServerError, got.StatusCode)
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			log := logger.NewLogger(
+				logger.WithLevel(slog.LevelDebug),
+				logger.WithFormat(logger.HandlerJSON),
+			)
+
+			svc := NewService(tc.storeMock)
+			testServer := testhelper.SetupServer(svc, *log)
+
+			url := fmt.Sprintf("%s/api/v1/category", testServer.URL)
+			res := testhelper.SendRequest[any](t, "GET", url, nil, nil)
+			t.Cleanup(func() {
+				if res.Body != nil {
+					require.NoError(t, res.Body.Close())
+				}
+			})
+			tc.wantAssertion(t, res, tc.storeMock)
+			testServer.Close()
+		})
+	}
+}
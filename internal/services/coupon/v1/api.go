@@ -0,0 +1,25 @@
+package v1
+
+import (
+	"github.com/go-chi/chi/v5"
+
+	"github.com/sgrumley/kart-challenge/pkg/middleware"
+)
+
+func (s *CouponService) GetRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.RequireAPIKey(s.apiKey))
+
+		if s.index != nil {
+			r.Get("/admin/coupons/index", s.GetIndexStatus)
+			r.Post("/admin/coupons/index/reload", s.ReloadIndex)
+		}
+
+		r.Post("/admin/coupons", s.CreateCoupon)
+		r.Get("/admin/coupons/{code}", s.GetCoupon)
+		r.Post("/admin/coupons/{code}/disable", s.DisableCoupon)
+		r.Post("/admin/coupons/{code}/enable", s.EnableCoupon)
+		r.Post("/admin/coupons/{code}/rules", s.AddCouponRule)
+		r.Get("/admin/coupons/{code}/redemptions", s.ListRedemptions)
+	})
+}
@@ -0,0 +1,179 @@
+package mapper
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/sgrumley/kart-challenge/internal/couponindex"
+	"github.com/sgrumley/kart-challenge/pkg/models"
+)
+
+const DefaultRedemptionLimit = 50
+
+type IndexStatusResponse struct {
+	Loaded     bool          `json:"loaded"`
+	Loading    bool          `json:"loading"`
+	LoadedAt   *time.Time    `json:"loaded_at,omitempty"`
+	DurationMS int64         `json:"duration_ms"`
+	Sources    []IndexSource `json:"sources"`
+	Skipped    int           `json:"skipped"`
+	Bloom      bool          `json:"bloom_filter"`
+	LastError  string        `json:"last_error,omitempty"`
+}
+
+type IndexSource struct {
+	Source int `json:"source"`
+	Codes  int `json:"codes"`
+}
+
+func IndexStatusToResponse(stats couponindex.Stats) *IndexStatusResponse {
+	res := &IndexStatusResponse{
+		Loaded:     stats.Loaded,
+		Loading:    stats.Loading,
+		DurationMS: stats.Duration.Milliseconds(),
+		Sources:    make([]IndexSource, 0, len(stats.Codes)),
+		Skipped:    stats.Skipped,
+		Bloom:      stats.Bloom,
+		LastError:  stats.LastErr,
+	}
+
+	if !stats.LoadedAt.IsZero() {
+		res.LoadedAt = &stats.LoadedAt
+	}
+
+	for source, codes := range stats.Codes {
+		res.Sources = append(res.Sources, IndexSource{
+			Source: source,
+			Codes:  codes,
+		})
+	}
+	sort.Slice(res.Sources, func(i, j int) bool {
+		return res.Sources[i].Source < res.Sources[j].Source
+	})
+
+	return res
+}
+
+type CreateCouponRequest struct {
+	Code string `json:"code" validate:"required,min=8,max=10,alphanum"`
+}
+
+type AddCouponRuleRequest struct {
+	Kind      string  `json:"kind" validate:"required,oneof=percentage fixed_amount free_cheapest_item"`
+	Value     float32 `json:"value" validate:"gt=0"`
+	Category  string  `json:"category" validate:"omitempty,max=255"`
+	MinBasket float32 `json:"min_basket" validate:"min=0"`
+}
+
+type ListRedemptionsRequest struct {
+	Limit int `validate:"min=1,max=500"`
+}
+
+type CouponRule struct {
+	ID        string  `json:"id"`
+	Kind      string  `json:"kind"`
+	Value     float32 `json:"value"`
+	Category  string  `json:"category,omitempty"`
+	MinBasket float32 `json:"min_basket"`
+}
+
+type CouponResponse struct {
+	Code       string       `json:"code"`
+	Sources    []int        `json:"sources"`
+	Manual     bool         `json:"manual"`
+	Disabled   bool         `json:"disabled"`
+	DisabledAt int64        `json:"disabled_at,omitempty"`
+	Valid      bool         `json:"valid"`
+	Rules      []CouponRule `json:"rules"`
+}
+
+type CouponRedemption struct {
+	ID         string `json:"id"`
+	OrderID    string `json:"order_id"`
+	CustomerID string `json:"customer_id,omitempty"`
+	CreatedAt  int64  `json:"created_at"`
+	ReleasedAt int64  `json:"released_at,omitempty"`
+}
+
+type ListRedemptionsResponse struct {
+	Code        string             `json:"code"`
+	Redemptions []CouponRedemption `json:"redemptions"`
+}
+
+func CouponRuleFromRequest(code string, req AddCouponRuleRequest) models.CouponRule {
+	return models.CouponRule{
+		CouponCode: code,
+		Kind:       models.DiscountKind(req.Kind),
+		Value:      req.Value,
+		Category:   req.Category,
+		MinBasket:  req.MinBasket,
+	}
+}
+
+func CouponRuleToResponse(rule models.CouponRule) CouponRule {
+	return CouponRule{
+		ID:        rule.ID,
+		Kind:      string(rule.Kind),
+		Value:     rule.Value,
+		Category:  rule.Category,
+		MinBasket: rule.MinBasket,
+	}
+}
+
+func CouponToResponse(coupon models.Coupon) *CouponResponse {
+	res := &CouponResponse{
+		Code:       coupon.Code,
+		Sources:    coupon.Sources,
+		Manual:     coupon.Manual,
+		Disabled:   coupon.Disabled,
+		DisabledAt: coupon.DisabledAt,
+		Valid:      coupon.Valid,
+		Rules:      make([]CouponRule, len(coupon.Rules)),
+	}
+	if res.Sources == nil {
+		res.Sources = []int{}
+	}
+
+	for i, rule := range coupon.Rules {
+		res.Rules[i] = CouponRuleToResponse(rule)
+	}
+
+	return res
+}
+
+func ListRedemptionsRequestFromQuery(query url.Values) (ListRedemptionsRequest, error) {
+	req := ListRedemptionsRequest{
+		Limit: DefaultRedemptionLimit,
+	}
+
+	if v := query.Get("limit"); v != "" {
+		var err error
+		if req.Limit, err = strconv.Atoi(v); err != nil {
+			return ListRedemptionsRequest{}, fmt.Errorf("limit: %w", err)
+		}
+	}
+
+	return req, nil
+}
+
+func ListRedemptionsToResponse(code string, redemptions []models.CouponRedemption) *ListRedemptionsResponse {
+	res := &ListRedemptionsResponse{
+		Code:        code,
+		Redemptions: make([]CouponRedemption, len(redemptions)),
+	}
+
+	for i, r := range redemptions {
+		res.Redemptions[i] = CouponRedemption{
+			ID:         r.ID,
+			OrderID:    r.OrderID,
+			CustomerID: r.CustomerID,
+			CreatedAt:  r.CreatedAt,
+			ReleasedAt: r.ReleasedAt,
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,253 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/sgrumley/kart-challenge/internal/couponindex"
+	"github.com/sgrumley/kart-challenge/internal/services/coupon/v1/mapper"
+	"github.com/sgrumley/kart-challenge/internal/store"
+	"github.com/sgrumley/kart-challenge/pkg/logger"
+	"github.com/sgrumley/kart-challenge/pkg/models"
+	"github.com/sgrumley/kart-challenge/pkg/web"
+)
+
+//go:generate moq -out ./mocks_test.go . CouponStorable CouponIndex
+
+var (
+	_ CouponStorable = (*store.Store)(nil)
+	_ CouponIndex    = (*couponindex.Index)(nil)
+)
+
+type CouponStorable interface {
+	GetCoupon(ctx context.Context, code string) (models.Coupon, error)
+	CreateCoupon(ctx context.Context, code string) (models.Coupon, error)
+	SetCouponDisabled(ctx context.Context, code string, disabled bool) (models.Coupon, error)
+	AddCouponRule(ctx context.Context, rule models.CouponRule) (models.CouponRule, error)
+	ListCouponRedemptions(ctx context.Context, code string, limit int) ([]models.CouponRedemption, error)
+}
+
+type CouponIndex interface {
+	LoadAsync(ctx context.Context) error
+	Stats() couponindex.Stats
+}
+
+// NewService builds the coupon admin service. index may be nil when the
+// in-memory index is disabled, in which case its routes are not registered.
+// Every route requires the apiKey in the api_key header.
+func NewService(store CouponStorable, index CouponIndex, apiKey string) *CouponService {
+	return &CouponService{
+		store:    store,
+		index:    index,
+		apiKey:   apiKey,
+		validate: validator.New(),
+	}
+}
+
+type CouponService struct {
+	validate *validator.Validate
+	store    CouponStorable
+	index    CouponIndex
+	apiKey   string
+}
+
+var (
+	Err400InvalidQuery = &web.Error{
+		Status:      http.StatusBadRequest,
+		Code:        "invalid_query_parameters",
+		Description: "Invalid query parameters supplied",
+	}
+
+	Err400InvalidRequestBody = &web.Error{
+		Status:      http.StatusBadRequest,
+		Code:        "invalid_request_body",
+		Description: "Invalid input",
+	}
+
+	Err404CouponNotFound = &web.Error{
+		Status:      http.StatusNotFound,
+		Code:        "coupon_not_found",
+		Description: "Coupon not found",
+	}
+
+	Err409CouponExists = &web.Error{
+		Status:      http.StatusConflict,
+		Code:        "coupon_already_exists",
+		Description: "A coupon with this code already exists",
+	}
+
+	Err409IndexReloadInProgress = &web.Error{
+		Status:      http.StatusConflict,
+		Code:        "index_reload_in_progress",
+		Description: "The coupon index is already being reloaded",
+	}
+
+	Err422Validation = &web.Error{
+		Status:      http.StatusUnprocessableEntity,
+		Code:        "invalid_coupon_detail",
+		Description: "Validation exception",
+	}
+)
+
+// storeError turns an unknown code into a 404 and a duplicate one into a 409.
+func storeError(err error) error {
+	switch {
+	case errors.Is(err, store.ErrCouponNotFound):
+		return Err404CouponNotFound
+	case errors.Is(err, store.ErrCouponExists):
+		return Err409CouponExists
+	}
+	return err
+}
+
+func (s *CouponService) GetCoupon(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	code := chi.URLParam(r, "code")
+
+	coupon, err := s.store.GetCoupon(ctx, code)
+	if err != nil {
+		logger.Error(ctx, "failed fetching coupon: "+code, err)
+		web.RespondJSONError(w, storeError(err))
+		return
+	}
+
+	web.Respond(w, http.StatusOK, mapper.CouponToResponse(coupon))
+}
+
+// CreateCoupon adds a manual coupon which is valid without appearing in any
+// coupon file.
+func (s *CouponService) CreateCoupon(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req mapper.CreateCouponRequest
+	if err := web.DecodeBody(r, &req); err != nil {
+		logger.Error(ctx, "invalid request body", err)
+		web.RespondJSONError(w, Err400InvalidRequestBody)
+		return
+	}
+
+	if err := s.validate.Struct(req); err != nil {
+		logger.Error(ctx, "validation failed", err)
+		web.RespondJSONError(w, Err422Validation)
+		return
+	}
+
+	coupon, err := s.store.CreateCoupon(ctx, req.Code)
+	if err != nil {
+		logger.Error(ctx, "failed creating coupon in store", err)
+		web.RespondJSONError(w, storeError(err))
+		return
+	}
+
+	web.Respond(w, http.StatusCreated, mapper.CouponToResponse(coupon))
+}
+
+func (s *CouponService) DisableCoupon(w http.ResponseWriter, r *http.Request) {
+	s.setCouponDisabled(w, r, true)
+}
+
+func (s *CouponService) EnableCoupon(w http.ResponseWriter, r *http.Request) {
+	s.setCouponDisabled(w, r, false)
+}
+
+func (s *CouponService) setCouponDisabled(w http.ResponseWriter, r *http.Request, disabled bool) {
+	ctx := r.Context()
+	code := chi.URLParam(r, "code")
+
+	coupon, err := s.store.SetCouponDisabled(ctx, code, disabled)
+	if err != nil {
+		logger.Error(ctx, "failed updating coupon in store: "+code, err)
+		web.RespondJSONError(w, storeError(err))
+		return
+	}
+
+	web.Respond(w, http.StatusOK, mapper.CouponToResponse(coupon))
+}
+
+func (s *CouponService) AddCouponRule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	code := chi.URLParam(r, "code")
+
+	var req mapper.AddCouponRuleRequest
+	if err := web.DecodeBody(r, &req); err != nil {
+		logger.Error(ctx, "invalid request body", err)
+		web.RespondJSONError(w, Err400InvalidRequestBody)
+		return
+	}
+
+	if err := s.validate.Struct(req); err != nil {
+		logger.Error(ctx, "validation failed", err)
+		web.RespondJSONError(w, Err422Validation)
+		return
+	}
+
+	if req.Kind == string(models.DiscountPercentage) && req.Value > 100 {
+		logger.Error(ctx, "validation failed", fmt.Errorf("percentage %v is over 100", req.Value))
+		web.RespondJSONError(w, Err422Validation)
+		return
+	}
+
+	rule, err := s.store.AddCouponRule(ctx, mapper.CouponRuleFromRequest(code, req))
+	if err != nil {
+		logger.Error(ctx, "failed adding coupon rule in store", err)
+		web.RespondJSONError(w, storeError(err))
+		return
+	}
+
+	web.Respond(w, http.StatusCreated, mapper.CouponRuleToResponse(rule))
+}
+
+func (s *CouponService) ListRedemptions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	code := chi.URLParam(r, "code")
+
+	req, err := mapper.ListRedemptionsRequestFromQuery(r.URL.Query())
+	if err != nil {
+		logger.Error(ctx, "invalid query parameters", err)
+		web.RespondJSONError(w, Err400InvalidQuery)
+		return
+	}
+
+	if err := s.validate.Struct(req); err != nil {
+		logger.Error(ctx, "validation failed", err)
+		web.RespondJSONError(w, Err400InvalidQuery)
+		return
+	}
+
+	redemptions, err := s.store.ListCouponRedemptions(ctx, code, req.Limit)
+	if err != nil {
+		logger.Error(ctx, "failed listing coupon redemptions in store", err)
+		web.RespondJSONError(w, storeError(err))
+		return
+	}
+
+	web.Respond(w, http.StatusOK, mapper.ListRedemptionsToResponse(code, redemptions))
+}
+
+func (s *CouponService) GetIndexStatus(w http.ResponseWriter, r *http.Request) {
+	web.Respond(w, http.StatusOK, mapper.IndexStatusToResponse(s.index.Stats()))
+}
+
+// ReloadIndex rebuilds the coupon index in the background. Coupon checks keep
+// using the current index until the new one is ready.
+func (s *CouponService) ReloadIndex(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// the load outlives the request so it must not be cancelled with it
+	if err := s.index.LoadAsync(context.WithoutCancel(ctx)); err != nil {
+		if errors.Is(err, couponindex.ErrLoadInProgress) {
+			logger.Error(ctx, "coupon index reload already running", err)
+			web.RespondJSONError(w, Err409IndexReloadInProgress)
+			return
+		}
+		logger.Error(ctx, "failed to start coupon index reload", err)
+		web.RespondJSONError(w, err)
+		return
+	}
+
+	web.Respond(w, http.StatusAccepted, mapper.IndexStatusToResponse(s.index.Stats()))
+}
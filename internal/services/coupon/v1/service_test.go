@@ -0,0 +1,671 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sgrumley/kart-challenge/internal/couponindex"
+	"github.com/sgrumley/kart-challenge/internal/services/coupon/v1/mapper"
+	"github.com/sgrumley/kart-challenge/internal/store"
+	"github.com/sgrumley/kart-challenge/pkg/logger"
+	"github.com/sgrumley/kart-challenge/pkg/models"
+	"github.com/sgrumley/kart-challenge/pkg/testhelper"
+	"github.com/sgrumley/kart-challenge/pkg/web"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testAPIKey = "a-secret-key"
+
+var authHeaders = map[string]string{
+	"api_key": testAPIKey,
+}
+
+func loadedStats() couponindex.Stats {
+	return couponindex.Stats{
+		Loaded:   true,
+		LoadedAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		Duration: 1500 * time.Millisecond,
+		Codes:    map[int]int{2: 20, 1: 10},
+		Skipped:  1,
+	}
+}
+
+func Test_API_Service_GetIndexStatus(t *testing.T) {
+	t.Parallel()
+	testCases := map[string]struct {
+		headers       map[string]string
+		indexMock     *CouponIndexMock
+		wantAssertion func(t *testing.T, got *http.Response, indexMock *CouponIndexMock)
+	}{
+		"success/loaded": {
+			headers: authHeaders,
+			indexMock: &CouponIndexMock{
+				StatsFunc: loadedStats,
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, indexMock *CouponIndexMock) {
+				require.Equal(t, http.StatusOK, got.StatusCode)
+				require.Len(t, indexMock.StatsCalls(), 1)
+
+				loadedAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+				want := mapper.IndexStatusResponse{
+					Loaded:     true,
+					LoadedAt:   &loadedAt,
+					DurationMS: 1500,
+					Sources: []mapper.IndexSource{
+						{Source: 1, Codes: 10},
+						{Source: 2, Codes: 20},
+					},
+					Skipped: 1,
+				}
+
+				actual := testhelper.PayloadAsType[mapper.IndexStatusResponse](t, got.Body)
+				assert.Equal(t, want, actual)
+			},
+		},
+		"success/not_loaded": {
+			headers: authHeaders,
+			indexMock: &CouponIndexMock{
+				StatsFunc: func() couponindex.Stats {
+					return couponindex.Stats{Loading: true}
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, indexMock *CouponIndexMock) {
+				require.Equal(t, http.StatusOK, got.StatusCode)
+
+				want := mapper.IndexStatusResponse{
+					Loading: true,
+					Sources: []mapper.IndexSource{},
+				}
+
+				actual := testhelper.PayloadAsType[mapper.IndexStatusResponse](t, got.Body)
+				assert.Equal(t, want, actual)
+			},
+		},
+		"error/missing_api_key": {
+			indexMock: &CouponIndexMock{},
+			wantAssertion: func(t *testing.T, got *http.Response, indexMock *CouponIndexMock) {
+				require.Equal(t, http.StatusUnauthorized, got.StatusCode)
+				require.Len(t, indexMock.StatsCalls(), 0)
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			log := logger.NewLogger(
+				logger.WithLevel(slog.LevelDebug),
+				logger.WithFormat(logger.HandlerJSON),
+			)
+
+			svc := NewService(&CouponStorableMock{}, tc.indexMock, testAPIKey)
+			testServer := testhelper.SetupServer(svc, *log)
+
+			url := fmt.Sprintf("%s/api/v1/admin/coupons/index", testServer.URL)
+			res := testhelper.SendRequest[any](t, "GET", url, nil, tc.headers)
+			t.Cleanup(func() {
+				if res.Body != nil {
+					require.NoError(t, res.Body.Close())
+				}
+			})
+			tc.wantAssertion(t, res, tc.indexMock)
+			testServer.Close()
+		})
+	}
+}
+
+func Test_API_Service_ReloadIndex(t *testing.T) {
+	t.Parallel()
+	testCases := map[string]struct {
+		headers       map[string]string
+		indexMock     *CouponIndexMock
+		wantAssertion func(t *testing.T, got *http.Response, indexMock *CouponIndexMock)
+	}{
+		"success/reload_started": {
+			headers: authHeaders,
+			indexMock: &CouponIndexMock{
+				LoadAsyncFunc: func(ctx context.Context) error {
+					return nil
+				},
+				StatsFunc: func() couponindex.Stats {
+					stats := loadedStats()
+					stats.Loading = true
+					return stats
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, indexMock *CouponIndexMock) {
+				require.Equal(t, http.StatusAccepted, got.StatusCode)
+				require.Len(t, indexMock.LoadAsyncCalls(), 1)
+				assert.NoError(t, indexMock.LoadAsyncCalls()[0].Ctx.Err())
+
+				actual := testhelper.PayloadAsType[mapper.IndexStatusResponse](t, got.Body)
+				assert.True(t, actual.Loading)
+				assert.True(t, actual.Loaded)
+			},
+		},
+		"error/reload_in_progress": {
+			headers: authHeaders,
+			indexMock: &CouponIndexMock{
+				LoadAsyncFunc: func(ctx context.Context) error {
+					return couponindex.ErrLoadInProgress
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, indexMock *CouponIndexMock) {
+				require.Equal(t, http.StatusConflict, got.StatusCode)
+				require.Len(t, indexMock.LoadAsyncCalls(), 1)
+				require.Len(t, indexMock.StatsCalls(), 0)
+
+				actual := testhelper.PayloadAsType[web.ErrorResponse](t, got.Body)
+				expectedError := testhelper.MapExpectedErrorResponse(Err409IndexReloadInProgress)
+				assert.Equal(t, expectedError, actual)
+			},
+		},
+		"error/invalid_api_key": {
+			headers:   map[string]string{"api_key": "wrong-key"},
+			indexMock: &CouponIndexMock{},
+			wantAssertion: func(t *testing.T, got *http.Response, indexMock *CouponIndexMock) {
+				require.Equal(t, http.StatusUnauthorized, got.StatusCode)
+				require.Len(t, indexMock.LoadAsyncCalls(), 0)
+
+				actual := testhelper.PayloadAsType[web.ErrorResponse](t, got.Body)
+				expectedError := testhelper.MapExpectedErrorResponse(web.Err401Default)
+				assert.Equal(t, expectedError, actual)
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			log := logger.NewLogger(
+				logger.WithLevel(slog.LevelDebug),
+				logger.WithFormat(logger.HandlerJSON),
+			)
+
+			svc := NewService(&CouponStorableMock{}, tc.indexMock, testAPIKey)
+			testServer := testhelper.SetupServer(svc, *log)
+
+			url := fmt.Sprintf("%s/api/v1/admin/coupons/index/reload", testServer.URL)
+			res := testhelper.SendRequest(t, "POST", url, &struct{}{}, tc.headers)
+			t.Cleanup(func() {
+				if res.Body != nil {
+					require.NoError(t, res.Body.Close())
+				}
+			})
+			tc.wantAssertion(t, res, tc.indexMock)
+			testServer.Close()
+		})
+	}
+}
+
+func manualCoupon() models.Coupon {
+	return models.Coupon{
+		Code:    "MANUAL01",
+		Sources: []int{},
+		Manual:  true,
+		Valid:   true,
+		Rules: []models.CouponRule{
+			{ID: "rule-1", CouponCode: "MANUAL01", Kind: models.DiscountPercentage, Value: 10},
+		},
+	}
+}
+
+func Test_API_Service_GetCoupon(t *testing.T) {
+	t.Parallel()
+	testCases := map[string]struct {
+		headers       map[string]string
+		storeMock     *CouponStorableMock
+		wantAssertion func(t *testing.T, got *http.Response, storeMock *CouponStorableMock)
+	}{
+		"success/found": {
+			headers: authHeaders,
+			storeMock: &CouponStorableMock{
+				GetCouponFunc: func(ctx context.Context, code string) (models.Coupon, error) {
+					return models.Coupon{
+						Code:    code,
+						Sources: []int{1, 3},
+						Valid:   true,
+						Rules:   []models.CouponRule{},
+					}, nil
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *CouponStorableMock) {
+				require.Equal(t, http.StatusOK, got.StatusCode)
+				require.Len(t, storeMock.GetCouponCalls(), 1)
+				assert.Equal(t, "HAPPYHRS", storeMock.GetCouponCalls()[0].Code)
+
+				want := mapper.CouponResponse{
+					Code:    "HAPPYHRS",
+					Sources: []int{1, 3},
+					Valid:   true,
+					Rules:   []mapper.CouponRule{},
+				}
+
+				actual := testhelper.PayloadAsType[mapper.CouponResponse](t, got.Body)
+				assert.Equal(t, want, actual)
+			},
+		},
+		"error/not_found": {
+			headers: authHeaders,
+			storeMock: &CouponStorableMock{
+				GetCouponFunc: func(ctx context.Context, code string) (models.Coupon, error) {
+					return models.Coupon{}, store.ErrCouponNotFound
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *CouponStorableMock) {
+				require.Equal(t, http.StatusNotFound, got.StatusCode)
+
+				actual := testhelper.PayloadAsType[web.ErrorResponse](t, got.Body)
+				expectedError := testhelper.MapExpectedErrorResponse(Err404CouponNotFound)
+				assert.Equal(t, expectedError, actual)
+			},
+		},
+		"error/missing_api_key": {
+			storeMock: &CouponStorableMock{},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *CouponStorableMock) {
+				require.Equal(t, http.StatusUnauthorized, got.StatusCode)
+				require.Len(t, storeMock.GetCouponCalls(), 0)
+
+				actual := testhelper.PayloadAsType[web.ErrorResponse](t, got.Body)
+				expectedError := testhelper.MapExpectedErrorResponse(web.Err401Default)
+				assert.Equal(t, expectedError, actual)
+			},
+		},
+		"error/wrong_api_key": {
+			headers: map[string]string{
+				"api_key": "not-the-key",
+			},
+			storeMock: &CouponStorableMock{},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *CouponStorableMock) {
+				require.Equal(t, http.StatusUnauthorized, got.StatusCode)
+				require.Len(t, storeMock.GetCouponCalls(), 0)
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			log := logger.NewLogger(
+				logger.WithLevel(slog.LevelDebug),
+				logger.WithFormat(logger.HandlerJSON),
+			)
+
+			svc := NewService(tc.storeMock, nil, testAPIKey)
+			testServer := testhelper.SetupServer(svc, *log)
+
+			url := fmt.Sprintf("%s/api/v1/admin/coupons/HAPPYHRS", testServer.URL)
+			res := testhelper.SendRequest[any](t, "GET", url, nil, tc.headers)
+			t.Cleanup(func() {
+				if res.Body != nil {
+					require.NoError(t, res.Body.Close())
+				}
+			})
+			tc.wantAssertion(t, res, tc.storeMock)
+			testServer.Close()
+		})
+	}
+}
+
+func Test_API_Service_CreateCoupon(t *testing.T) {
+	t.Parallel()
+	testCases := map[string]struct {
+		body          *mapper.CreateCouponRequest
+		storeMock     *CouponStorableMock
+		wantAssertion func(t *testing.T, got *http.Response, storeMock *CouponStorableMock)
+	}{
+		"success/created": {
+			body: &mapper.CreateCouponRequest{Code: "MANUAL01"},
+			storeMock: &CouponStorableMock{
+				CreateCouponFunc: func(ctx context.Context, code string) (models.Coupon, error) {
+					return models.Coupon{Code: code, Manual: true, Valid: true}, nil
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *CouponStorableMock) {
+				require.Equal(t, http.StatusCreated, got.StatusCode)
+				require.Len(t, storeMock.CreateCouponCalls(), 1)
+
+				want := mapper.CouponResponse{
+					Code:    "MANUAL01",
+					Sources: []int{},
+					Manual:  true,
+					Valid:   true,
+					Rules:   []mapper.CouponRule{},
+				}
+
+				actual := testhelper.PayloadAsType[mapper.CouponResponse](t, got.Body)
+				assert.Equal(t, want, actual)
+			},
+		},
+		"error/already_exists": {
+			body: &mapper.CreateCouponRequest{Code: "MANUAL01"},
+			storeMock: &CouponStorableMock{
+				CreateCouponFunc: func(ctx context.Context, code string) (models.Coupon, error) {
+					return models.Coupon{}, store.ErrCouponExists
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *CouponStorableMock) {
+				require.Equal(t, http.StatusConflict, got.StatusCode)
+
+				actual := testhelper.PayloadAsType[web.ErrorResponse](t, got.Body)
+				expectedError := testhelper.MapExpectedErrorResponse(Err409CouponExists)
+				assert.Equal(t, expectedError, actual)
+			},
+		},
+		"error/code_too_short": {
+			body:      &mapper.CreateCouponRequest{Code: "SHORT"},
+			storeMock: &CouponStorableMock{},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *CouponStorableMock) {
+				require.Equal(t, http.StatusUnprocessableEntity, got.StatusCode)
+				require.Len(t, storeMock.CreateCouponCalls(), 0)
+
+				actual := testhelper.PayloadAsType[web.ErrorResponse](t, got.Body)
+				expectedError := testhelper.MapExpectedErrorResponse(Err422Validation)
+				assert.Equal(t, expectedError, actual)
+			},
+		},
+		"error/invalid_body": {
+			storeMock: &CouponStorableMock{},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *CouponStorableMock) {
+				require.Equal(t, http.StatusBadRequest, got.StatusCode)
+				require.Len(t, storeMock.CreateCouponCalls(), 0)
+
+				actual := testhelper.PayloadAsType[web.ErrorResponse](t, got.Body)
+				expectedError := testhelper.MapExpectedErrorResponse(Err400InvalidRequestBody)
+				assert.Equal(t, expectedError, actual)
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			log := logger.NewLogger(
+				logger.WithLevel(slog.LevelDebug),
+				logger.WithFormat(logger.HandlerJSON),
+			)
+
+			svc := NewService(tc.storeMock, nil, testAPIKey)
+			testServer := testhelper.SetupServer(svc, *log)
+
+			url := fmt.Sprintf("%s/api/v1/admin/coupons", testServer.URL)
+			res := testhelper.SendRequest(t, "POST", url, tc.body, authHeaders)
+			t.Cleanup(func() {
+				if res.Body != nil {
+					require.NoError(t, res.Body.Close())
+				}
+			})
+			tc.wantAssertion(t, res, tc.storeMock)
+			testServer.Close()
+		})
+	}
+}
+
+func Test_API_Service_SetCouponDisabled(t *testing.T) {
+	t.Parallel()
+	testCases := map[string]struct {
+		action        string
+		storeMock     *CouponStorableMock
+		wantAssertion func(t *testing.T, got *http.Response, storeMock *CouponStorableMock)
+	}{
+		"success/disable": {
+			action: "disable",
+			storeMock: &CouponStorableMock{
+				SetCouponDisabledFunc: func(ctx context.Context, code string, disabled bool) (models.Coupon, error) {
+					coupon := manualCoupon()
+					coupon.Disabled = disabled
+					coupon.DisabledAt = 1735689600000000000
+					coupon.Valid = false
+					return coupon, nil
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *CouponStorableMock) {
+				require.Equal(t, http.StatusOK, got.StatusCode)
+				require.Len(t, storeMock.SetCouponDisabledCalls(), 1)
+				assert.True(t, storeMock.SetCouponDisabledCalls()[0].Disabled)
+
+				actual := testhelper.PayloadAsType[mapper.CouponResponse](t, got.Body)
+				assert.True(t, actual.Disabled)
+				assert.False(t, actual.Valid)
+				assert.Equal(t, int64(1735689600000000000), actual.DisabledAt)
+			},
+		},
+		"success/enable": {
+			action: "enable",
+			storeMock: &CouponStorableMock{
+				SetCouponDisabledFunc: func(ctx context.Context, code string, disabled bool) (models.Coupon, error) {
+					return manualCoupon(), nil
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *CouponStorableMock) {
+				require.Equal(t, http.StatusOK, got.StatusCode)
+				require.Len(t, storeMock.SetCouponDisabledCalls(), 1)
+				assert.False(t, storeMock.SetCouponDisabledCalls()[0].Disabled)
+
+				actual := testhelper.PayloadAsType[mapper.CouponResponse](t, got.Body)
+				assert.False(t, actual.Disabled)
+				assert.True(t, actual.Valid)
+				require.Len(t, actual.Rules, 1)
+			},
+		},
+		"error/not_found": {
+			action: "disable",
+			storeMock: &CouponStorableMock{
+				SetCouponDisabledFunc: func(ctx context.Context, code string, disabled bool) (models.Coupon, error) {
+					return models.Coupon{}, store.ErrCouponNotFound
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *CouponStorableMock) {
+				require.Equal(t, http.StatusNotFound, got.StatusCode)
+
+				actual := testhelper.PayloadAsType[web.ErrorResponse](t, got.Body)
+				expectedError := testhelper.MapExpectedErrorResponse(Err404CouponNotFound)
+				assert.Equal(t, expectedError, actual)
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			log := logger.NewLogger(
+				logger.WithLevel(slog.LevelDebug),
+				logger.WithFormat(logger.HandlerJSON),
+			)
+
+			svc := NewService(tc.storeMock, nil, testAPIKey)
+			testServer := testhelper.SetupServer(svc, *log)
+
+			url := fmt.Sprintf("%s/api/v1/admin/coupons/MANUAL01/%s", testServer.URL, tc.action)
+			res := testhelper.SendRequest(t, "POST", url, &struct{}{}, authHeaders)
+			t.Cleanup(func() {
+				if res.Body != nil {
+					require.NoError(t, res.Body.Close())
+				}
+			})
+			tc.wantAssertion(t, res, tc.storeMock)
+			testServer.Close()
+		})
+	}
+}
+
+func Test_API_Service_AddCouponRule(t *testing.T) {
+	t.Parallel()
+	testCases := map[string]struct {
+		body          *mapper.AddCouponRuleRequest
+		storeMock     *CouponStorableMock
+		wantAssertion func(t *testing.T, got *http.Response, storeMock *CouponStorableMock)
+	}{
+		"success/added": {
+			body: &mapper.AddCouponRuleRequest{Kind: "fixed_amount", Value: 5, Category: "Waffle", MinBasket: 20},
+			storeMock: &CouponStorableMock{
+				AddCouponRuleFunc: func(ctx context.Context, rule models.CouponRule) (models.CouponRule, error) {
+					rule.ID = "rule-1"
+					return rule, nil
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *CouponStorableMock) {
+				require.Equal(t, http.StatusCreated, got.StatusCode)
+				require.Len(t, storeMock.AddCouponRuleCalls(), 1)
+				assert.Equal(t, "MANUAL01", storeMock.AddCouponRuleCalls()[0].Rule.CouponCode)
+
+				want := mapper.CouponRule{
+					ID:        "rule-1",
+					Kind:      "fixed_amount",
+					Value:     5,
+					Category:  "Waffle",
+					MinBasket: 20,
+				}
+
+				actual := testhelper.PayloadAsType[mapper.CouponRule](t, got.Body)
+				assert.Equal(t, want, actual)
+			},
+		},
+		"error/unknown_kind": {
+			body:      &mapper.AddCouponRuleRequest{Kind: "buy_one_get_one", Value: 1},
+			storeMock: &CouponStorableMock{},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *CouponStorableMock) {
+				require.Equal(t, http.StatusUnprocessableEntity, got.StatusCode)
+				require.Len(t, storeMock.AddCouponRuleCalls(), 0)
+			},
+		},
+		"error/percentage_over_100": {
+			body:      &mapper.AddCouponRuleRequest{Kind: "percentage", Value: 150},
+			storeMock: &CouponStorableMock{},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *CouponStorableMock) {
+				require.Equal(t, http.StatusUnprocessableEntity, got.StatusCode)
+				require.Len(t, storeMock.AddCouponRuleCalls(), 0)
+
+				actual := testhelper.PayloadAsType[web.ErrorResponse](t, got.Body)
+				expectedError := testhelper.MapExpectedErrorResponse(Err422Validation)
+				assert.Equal(t, expectedError, actual)
+			},
+		},
+		"error/coupon_not_found": {
+			body: &mapper.AddCouponRuleRequest{Kind: "percentage", Value: 10},
+			storeMock: &CouponStorableMock{
+				AddCouponRuleFunc: func(ctx context.Context, rule models.CouponRule) (models.CouponRule, error) {
+					return models.CouponRule{}, store.ErrCouponNotFound
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *CouponStorableMock) {
+				require.Equal(t, http.StatusNotFound, got.StatusCode)
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			log := logger.NewLogger(
+				logger.WithLevel(slog.LevelDebug),
+				logger.WithFormat(logger.HandlerJSON),
+			)
+
+			svc := NewService(tc.storeMock, nil, testAPIKey)
+			testServer := testhelper.SetupServer(svc, *log)
+
+			url := fmt.Sprintf("%s/api/v1/admin/coupons/MANUAL01/rules", testServer.URL)
+			res := testhelper.SendRequest(t, "POST", url, tc.body, authHeaders)
+			t.Cleanup(func() {
+				if res.Body != nil {
+					require.NoError(t, res.Body.Close())
+				}
+			})
+			tc.wantAssertion(t, res, tc.storeMock)
+			testServer.Close()
+		})
+	}
+}
+
+func Test_API_Service_ListRedemptions(t *testing.T) {
+	t.Parallel()
+	testCases := map[string]struct {
+		query         string
+		storeMock     *CouponStorableMock
+		wantAssertion func(t *testing.T, got *http.Response, storeMock *CouponStorableMock)
+	}{
+		"success/default_limit": {
+			storeMock: &CouponStorableMock{
+				ListCouponRedemptionsFunc: func(ctx context.Context, code string, limit int) ([]models.CouponRedemption, error) {
+					return []models.CouponRedemption{
+						{ID: "r-2", CouponCode: code, OrderID: "o-2", CreatedAt: 2, ReleasedAt: 3},
+						{ID: "r-1", CouponCode: code, OrderID: "o-1", CustomerID: "c-1", CreatedAt: 1},
+					}, nil
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *CouponStorableMock) {
+				require.Equal(t, http.StatusOK, got.StatusCode)
+				require.Len(t, storeMock.ListCouponRedemptionsCalls(), 1)
+				assert.Equal(t, mapper.DefaultRedemptionLimit, storeMock.ListCouponRedemptionsCalls()[0].Limit)
+
+				want := mapper.ListRedemptionsResponse{
+					Code: "MANUAL01",
+					Redemptions: []mapper.CouponRedemption{
+						{ID: "r-2", OrderID: "o-2", CreatedAt: 2, ReleasedAt: 3},
+						{ID: "r-1", OrderID: "o-1", CustomerID: "c-1", CreatedAt: 1},
+					},
+				}
+
+				actual := testhelper.PayloadAsType[mapper.ListRedemptionsResponse](t, got.Body)
+				assert.Equal(t, want, actual)
+			},
+		},
+		"success/custom_limit": {
+			query: "?limit=5",
+			storeMock: &CouponStorableMock{
+				ListCouponRedemptionsFunc: func(ctx context.Context, code string, limit int) ([]models.CouponRedemption, error) {
+					return []models.CouponRedemption{}, nil
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *CouponStorableMock) {
+				require.Equal(t, http.StatusOK, got.StatusCode)
+				require.Len(t, storeMock.ListCouponRedemptionsCalls(), 1)
+				assert.Equal(t, 5, storeMock.ListCouponRedemptionsCalls()[0].Limit)
+			},
+		},
+		"error/limit_too_large": {
+			query:     "?limit=501",
+			storeMock: &CouponStorableMock{},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *CouponStorableMock) {
+				require.Equal(t, http.StatusBadRequest, got.StatusCode)
+				require.Len(t, storeMock.ListCouponRedemptionsCalls(), 0)
+
+				actual := testhelper.PayloadAsType[web.ErrorResponse](t, got.Body)
+				expectedError := testhelper.MapExpectedErrorResponse(Err400InvalidQuery)
+				assert.Equal(t, expectedError, actual)
+			},
+		},
+		"error/not_found": {
+			storeMock: &CouponStorableMock{
+				ListCouponRedemptionsFunc: func(ctx context.Context, code string, limit int) ([]models.CouponRedemption, error) {
+					return nil, store.ErrCouponNotFound
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *CouponStorableMock) {
+				require.Equal(t, http.StatusNotFound, got.StatusCode)
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			log := logger.NewLogger(
+				logger.WithLevel(slog.LevelDebug),
+				logger.WithFormat(logger.HandlerJSON),
+			)
+
+			svc := NewService(tc.storeMock, nil, testAPIKey)
+			testServer := testhelper.SetupServer(svc, *log)
+
+			url := fmt.Sprintf("%s/api/v1/admin/coupons/MANUAL01/redemptions%s", testServer.URL, tc.query)
+			res := testhelper.SendRequest[any](t, "GET", url, nil, authHeaders)
+			t.Cleanup(func() {
+				if res.Body != nil {
+					require.NoError(t, res.Body.Close())
+				}
+			})
+			tc.wantAssertion(t, res, tc.storeMock)
+			testServer.Close()
+		})
+	}
+}
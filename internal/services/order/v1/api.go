@@ -2,10 +2,21 @@ package v1
 
 import (
 	"github.com/go-chi/chi/v5"
+
+	"github.com/sgrumley/kart-challenge/pkg/middleware"
 )
 
 func (s *OrderService) GetRoutes(r chi.Router) {
 	r.Group(func(r chi.Router) {
 		r.Post("/order", s.CreateOrder)
+		r.Get("/order/{order_id}", s.GetOrder)
+	})
+
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.RequireAPIKey(s.apiKey))
+
+		r.Get("/order", s.ListOrders)
+		r.Post("/order/{order_id}/status", s.UpdateOrderStatus)
+		r.Post("/order/{order_id}/cancel", s.CancelOrder)
 	})
 }
@@ -0,0 +1,65 @@
+package v1
+
+import (
+	"math"
+	"strings"
+
+	"github.com/sgrumley/kart-challenge/pkg/models"
+)
+
+// Discount evaluates every coupon rule against the priced lines and returns the
+// combined discount, capped at the subtotal. Lines must already carry their unit
+// price and line total.
+func Discount(items []models.Item, products []models.Product, subtotal float64, rules []models.CouponRule) float64 {
+	categories := make(map[string]string, len(products))
+	for _, p := range products {
+		categories[p.ID] = p.Category
+	}
+
+	var discount float64
+	for _, rule := range rules {
+		if subtotal < float64(rule.MinBasket) {
+			continue
+		}
+
+		eligible := make([]models.Item, 0, len(items))
+		for _, item := range items {
+			if rule.Category == "" || strings.EqualFold(rule.Category, categories[item.ProductID]) {
+				eligible = append(eligible, item)
+			}
+		}
+
+		discount += ruleDiscount(rule, eligible)
+	}
+
+	return math.Min(roundCents(discount), subtotal)
+}
+
+func ruleDiscount(rule models.CouponRule, eligible []models.Item) float64 {
+	if len(eligible) == 0 {
+		return 0
+	}
+
+	var eligibleTotal float64
+	for _, item := range eligible {
+		eligibleTotal += roundCents(float64(item.LineTotal))
+	}
+
+	switch rule.Kind {
+	case models.DiscountPercentage:
+		percent := math.Min(math.Max(float64(rule.Value), 0), 100)
+		return roundCents(eligibleTotal * percent / 100)
+
+	case models.DiscountFixedAmount:
+		return math.Min(float64(rule.Value), eligibleTotal)
+
+	case models.DiscountFreeCheapestItem:
+		cheapest := float64(eligible[0].UnitPrice)
+		for _, item := range eligible[1:] {
+			cheapest = math.Min(cheapest, float64(item.UnitPrice))
+		}
+		return roundCents(cheapest)
+	}
+
+	return 0
+}
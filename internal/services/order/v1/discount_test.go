@@ -0,0 +1,107 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/sgrumley/kart-challenge/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Discount(t *testing.T) {
+	t.Parallel()
+	products := []models.Product{
+		{ID: "wings", Category: "Appetizers", Price: 12.99},
+		{ID: "burger", Category: "Main Courses", Price: 15.99},
+		{ID: "coffee", Category: "Beverages", Price: 2.99},
+	}
+	items := []models.Item{
+		{ProductID: "wings", Quantity: 1, UnitPrice: 12.99, LineTotal: 12.99},
+		{ProductID: "burger", Quantity: 2, UnitPrice: 15.99, LineTotal: 31.98},
+		{ProductID: "coffee", Quantity: 2, UnitPrice: 2.99, LineTotal: 5.98},
+	}
+	subtotal := 50.95
+
+	testCases := map[string]struct {
+		rules []models.CouponRule
+		want  float64
+	}{
+		"no_rules": {
+			rules: []models.CouponRule{},
+			want:  0,
+		},
+		"percentage": {
+			rules: []models.CouponRule{
+				{Kind: models.DiscountPercentage, Value: 50},
+			},
+			want: 25.48,
+		},
+		"percentage_capped_at_hundred": {
+			rules: []models.CouponRule{
+				{Kind: models.DiscountPercentage, Value: 150},
+			},
+			want: 50.95,
+		},
+		"fixed_amount": {
+			rules: []models.CouponRule{
+				{Kind: models.DiscountFixedAmount, Value: 5},
+			},
+			want: 5,
+		},
+		"fixed_amount_limited_to_eligible_lines": {
+			rules: []models.CouponRule{
+				{Kind: models.DiscountFixedAmount, Value: 10, Category: "beverages"},
+			},
+			want: 5.98,
+		},
+		"free_cheapest_item": {
+			rules: []models.CouponRule{
+				{Kind: models.DiscountFreeCheapestItem},
+			},
+			want: 2.99,
+		},
+		"free_cheapest_item_in_category": {
+			rules: []models.CouponRule{
+				{Kind: models.DiscountFreeCheapestItem, Category: "Main Courses"},
+			},
+			want: 15.99,
+		},
+		"category_without_matching_lines": {
+			rules: []models.CouponRule{
+				{Kind: models.DiscountPercentage, Value: 50, Category: "Desserts"},
+			},
+			want: 0,
+		},
+		"min_basket_met": {
+			rules: []models.CouponRule{
+				{Kind: models.DiscountFixedAmount, Value: 10, MinBasket: 50},
+			},
+			want: 10,
+		},
+		"min_basket_not_met": {
+			rules: []models.CouponRule{
+				{Kind: models.DiscountFixedAmount, Value: 10, MinBasket: 60},
+			},
+			want: 0,
+		},
+		"rules_combine_and_cap_at_subtotal": {
+			rules: []models.CouponRule{
+				{Kind: models.DiscountPercentage, Value: 100},
+				{Kind: models.DiscountFixedAmount, Value: 10},
+			},
+			want: 50.95,
+		},
+		"unknown_kind": {
+			rules: []models.CouponRule{
+				{Kind: "buy_one_get_two", Value: 10},
+			},
+			want: 0,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got := Discount(items, products, subtotal, tc.rules)
+			assert.InDelta(t, tc.want, got, 0.001)
+		})
+	}
+}
@@ -1,15 +1,30 @@
 package mapper
 
-import "github.com/sgrumley/kart-challenge/pkg/models"
+import (
+	"fmt"
+	"net/url"
+	"strconv"
 
+	"github.com/sgrumley/kart-challenge/pkg/models"
+)
+
+const (
+	DefaultListLimit = 20
+	SortCreatedAsc   = "created_at"
+	SortCreatedDesc  = "-created_at"
+)
+
+// Item.Modifiers are the ids of the modifiers picked for the product.
 type Item struct {
-	ProductID string `json:"product_id" validate:"required,uuid4"`
-	Quantity  int    `json:"quantity" validate:"required,min=1"`
+	ProductID string   `json:"product_id" validate:"required,uuid"`
+	Quantity  int      `json:"quantity" validate:"required,min=1"`
+	Modifiers []string `json:"modifiers,omitempty" validate:"omitempty,max=50,dive,uuid"`
 }
 
 type CreateOrderRequest struct {
-	Items      []Item `json:"items" validate:"required"`
+	Items      []Item `json:"items" validate:"required,min=1,dive"`
 	CouponCode string `json:"coupon_code" validate:"omitempty,min=8,max=10"`
+	CustomerID string `json:"customer_id" validate:"omitempty,max=255"`
 }
 
 type Product struct {
@@ -19,10 +34,30 @@ type Product struct {
 	Price    float32 `json:"price"`
 }
 
+type LineModifier struct {
+	ID         string  `json:"id"`
+	Name       string  `json:"name"`
+	PriceDelta float32 `json:"price_delta"`
+}
+
+// LineItem.UnitPrice includes the price delta of its Modifiers.
+type LineItem struct {
+	ProductID string         `json:"product_id"`
+	Quantity  int            `json:"quantity"`
+	Modifiers []LineModifier `json:"modifiers,omitempty"`
+	UnitPrice float32        `json:"unit_price"`
+	LineTotal float32        `json:"line_total"`
+}
+
 type CreateOrderResponse struct {
-	ID       string    `json:"id"`
-	Items    []Item    `json:"items"`
-	Products []Product `json:"products"`
+	ID       string     `json:"id"`
+	Items    []Item     `json:"items"`
+	Lines    []LineItem `json:"lines"`
+	Products []Product  `json:"products"`
+	Subtotal float32    `json:"subtotal"`
+	Discount float32    `json:"discount"`
+	Total    float32    `json:"total"`
+	Status   string     `json:"status"`
 }
 
 func ItemsFromRequest(items []Item) []models.Item {
@@ -31,14 +66,29 @@ func ItemsFromRequest(items []Item) []models.Item {
 		modelItems[i] = models.Item{
 			ProductID: items[i].ProductID,
 			Quantity:  items[i].Quantity,
+			Modifiers: ModifiersFromRequest(items[i].Modifiers),
 		}
 	}
 	return modelItems
 }
 
+// ModifiersFromRequest only sets the ids, the rest is filled in when pricing.
+func ModifiersFromRequest(ids []string) []models.Modifier {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	modifiers := make([]models.Modifier, len(ids))
+	for i, id := range ids {
+		modifiers[i] = models.Modifier{ID: id}
+	}
+	return modifiers
+}
+
 func CreateOrderFromRequest(req CreateOrderRequest) models.Order {
 	return models.Order{
 		CouponCode: req.CouponCode,
+		CustomerID: req.CustomerID,
 		Items:      ItemsFromRequest(req.Items),
 	}
 }
@@ -49,12 +99,56 @@ func ItemsToResponse(items []models.Item) []Item {
 		responseItems[i] = Item{
 			ProductID: it.ProductID,
 			Quantity:  it.Quantity,
+			Modifiers: modifierIDs(it.Modifiers),
 		}
 	}
 
 	return responseItems
 }
 
+func LineItemsToResponse(items []models.Item) []LineItem {
+	responseLines := make([]LineItem, len(items))
+	for i, it := range items {
+		responseLines[i] = LineItem{
+			ProductID: it.ProductID,
+			Quantity:  it.Quantity,
+			Modifiers: LineModifiersToResponse(it.Modifiers),
+			UnitPrice: it.UnitPrice,
+			LineTotal: it.LineTotal,
+		}
+	}
+
+	return responseLines
+}
+
+func LineModifiersToResponse(modifiers []models.Modifier) []LineModifier {
+	if len(modifiers) == 0 {
+		return nil
+	}
+
+	res := make([]LineModifier, len(modifiers))
+	for i, m := range modifiers {
+		res[i] = LineModifier{
+			ID:         m.ID,
+			Name:       m.Name,
+			PriceDelta: m.PriceDelta,
+		}
+	}
+	return res
+}
+
+func modifierIDs(modifiers []models.Modifier) []string {
+	if len(modifiers) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(modifiers))
+	for i, m := range modifiers {
+		ids[i] = m.ID
+	}
+	return ids
+}
+
 func ProductsToResponse(products []models.Product) []Product {
 	responseProducts := make([]Product, len(products))
 	for i, p := range products {
@@ -73,6 +167,157 @@ func CreateOrderToResponse(res models.Order) CreateOrderResponse {
 	return CreateOrderResponse{
 		ID:       res.ID,
 		Items:    ItemsToResponse(res.Items),
+		Lines:    LineItemsToResponse(res.Items),
 		Products: ProductsToResponse(res.Products),
+		Subtotal: res.Subtotal,
+		Discount: res.Discount,
+		Total:    res.Total,
+		Status:   string(res.Status),
+	}
+}
+
+type StatusTransition struct {
+	From      string `json:"from,omitempty"`
+	To        string `json:"to"`
+	Reason    string `json:"reason,omitempty"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+type GetOrderResponse struct {
+	ID            string             `json:"id"`
+	CouponCode    string             `json:"coupon_code,omitempty"`
+	CustomerID    string             `json:"customer_id,omitempty"`
+	Items         []Item             `json:"items"`
+	Lines         []LineItem         `json:"lines"`
+	Products      []Product          `json:"products"`
+	Subtotal      float32            `json:"subtotal"`
+	Discount      float32            `json:"discount"`
+	Total         float32            `json:"total"`
+	Status        string             `json:"status"`
+	StatusHistory []StatusTransition `json:"status_history"`
+	CreatedAt     int64              `json:"created_at"`
+}
+
+func StatusHistoryToResponse(history []models.StatusTransition) []StatusTransition {
+	res := make([]StatusTransition, len(history))
+	for i, t := range history {
+		res[i] = StatusTransition{
+			From:      string(t.From),
+			To:        string(t.To),
+			Reason:    t.Reason,
+			CreatedAt: t.CreatedAt,
+		}
+	}
+	return res
+}
+
+func GetOrderToResponse(order *models.Order) GetOrderResponse {
+	return GetOrderResponse{
+		ID:            order.ID,
+		CouponCode:    order.CouponCode,
+		CustomerID:    order.CustomerID,
+		Items:         ItemsToResponse(order.Items),
+		Lines:         LineItemsToResponse(order.Items),
+		Products:      ProductsToResponse(order.Products),
+		Subtotal:      order.Subtotal,
+		Discount:      order.Discount,
+		Total:         order.Total,
+		Status:        string(order.Status),
+		StatusHistory: StatusHistoryToResponse(order.StatusHistory),
+		CreatedAt:     order.CreatedAt,
+	}
+}
+
+type CancelOrderRequest struct {
+	Reason string `json:"reason" validate:"required,max=255"`
+}
+
+type UpdateOrderStatusRequest struct {
+	Status string `json:"status" validate:"required,oneof=placed accepted preparing ready completed cancelled rejected"`
+	Reason string `json:"reason" validate:"omitempty,max=255"`
+}
+
+type ListOrdersRequest struct {
+	CreatedFrom int64  `validate:"omitempty,min=0"`
+	CreatedTo   int64  `validate:"omitempty,min=0,gtefield=CreatedFrom"`
+	CouponCode  string `validate:"omitempty,min=8,max=10"`
+	Sort        string `validate:"omitempty,oneof=created_at -created_at"`
+	Cursor      string
+	Limit       int `validate:"min=1,max=100"`
+}
+
+// ListOrdersRequestFromQuery fails only on a created_from, created_to or limit
+// that is not a number.
+func ListOrdersRequestFromQuery(query url.Values) (ListOrdersRequest, error) {
+	req := ListOrdersRequest{
+		CouponCode: query.Get("coupon_code"),
+		Sort:       query.Get("sort"),
+		Cursor:     query.Get("cursor"),
+		Limit:      DefaultListLimit,
+	}
+
+	var err error
+	if v := query.Get("created_from"); v != "" {
+		if req.CreatedFrom, err = strconv.ParseInt(v, 10, 64); err != nil {
+			return ListOrdersRequest{}, fmt.Errorf("created_from: %w", err)
+		}
+	}
+	if v := query.Get("created_to"); v != "" {
+		if req.CreatedTo, err = strconv.ParseInt(v, 10, 64); err != nil {
+			return ListOrdersRequest{}, fmt.Errorf("created_to: %w", err)
+		}
+	}
+	if v := query.Get("limit"); v != "" {
+		if req.Limit, err = strconv.Atoi(v); err != nil {
+			return ListOrdersRequest{}, fmt.Errorf("limit: %w", err)
+		}
+	}
+
+	return req, nil
+}
+
+func OrderFilterFromRequest(req ListOrdersRequest) models.OrderFilter {
+	return models.OrderFilter{
+		CreatedFrom: req.CreatedFrom,
+		CreatedTo:   req.CreatedTo,
+		CouponCode:  req.CouponCode,
+		Ascending:   req.Sort == SortCreatedAsc,
+		Cursor:      req.Cursor,
+		Limit:       req.Limit,
+	}
+}
+
+type OrderSummary struct {
+	ID         string  `json:"id"`
+	CouponCode string  `json:"coupon_code,omitempty"`
+	Subtotal   float32 `json:"subtotal"`
+	Discount   float32 `json:"discount"`
+	Total      float32 `json:"total"`
+	Status     string  `json:"status"`
+	CreatedAt  int64   `json:"created_at"`
+}
+
+type ListOrdersResponse struct {
+	Orders     []OrderSummary `json:"orders"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+}
+
+func ListOrdersToResponse(page models.OrderPage) ListOrdersResponse {
+	orders := make([]OrderSummary, len(page.Orders))
+	for i, o := range page.Orders {
+		orders[i] = OrderSummary{
+			ID:         o.ID,
+			CouponCode: o.CouponCode,
+			Subtotal:   o.Subtotal,
+			Discount:   o.Discount,
+			Total:      o.Total,
+			Status:     string(o.Status),
+			CreatedAt:  o.CreatedAt,
+		}
+	}
+
+	return ListOrdersResponse{
+		Orders:     orders,
+		NextCursor: page.NextCursor,
 	}
 }
@@ -7,6 +7,7 @@ import (
 	"context"
 	"github.com/sgrumley/kart-challenge/pkg/models"
 	"sync"
+	"time"
 )
 
 // Ensure, that OrderStorableMock does implement OrderStorable.
@@ -25,6 +26,24 @@ var _ OrderStorable = &OrderStorableMock{}
 //			CreateOrderFunc: func(ctx context.Context, order models.Order) (models.Order, error) {
 //				panic("mock out the CreateOrder method")
 //			},
+//			GetCouponRulesFunc: func(ctx context.Context, coupon string) ([]models.CouponRule, error) {
+//				panic("mock out the GetCouponRules method")
+//			},
+//			GetOrderFunc: func(ctx context.Context, id string) (models.Order, error) {
+//				panic("mock out the GetOrder method")
+//			},
+//			GetProductsFunc: func(ctx context.Context, ids []string) ([]models.Product, error) {
+//				panic("mock out the GetProducts method")
+//			},
+//			ListOrdersFunc: func(ctx context.Context, filter models.OrderFilter) (models.OrderPage, error) {
+//				panic("mock out the ListOrders method")
+//			},
+//			UnavailableProductsFunc: func(ctx context.Context, ids []string, at time.Time) ([]string, error) {
+//				panic("mock out the UnavailableProducts method")
+//			},
+//			UpdateOrderStatusFunc: func(ctx context.Context, id string, from models.OrderStatus, to models.OrderStatus, reason string) (models.Order, error) {
+//				panic("mock out the UpdateOrderStatus method")
+//			},
 //		}
 //
 //		// use mockedOrderStorable in code that requires OrderStorable
@@ -38,6 +57,24 @@ type OrderStorableMock struct {
 	// CreateOrderFunc mocks the CreateOrder method.
 	CreateOrderFunc func(ctx context.Context, order models.Order) (models.Order, error)
 
+	// GetCouponRulesFunc mocks the GetCouponRules method.
+	GetCouponRulesFunc func(ctx context.Context, coupon string) ([]models.CouponRule, error)
+
+	// GetOrderFunc mocks the GetOrder method.
+	GetOrderFunc func(ctx context.Context, id string) (models.Order, error)
+
+	// GetProductsFunc mocks the GetProducts method.
+	GetProductsFunc func(ctx context.Context, ids []string) ([]models.Product, error)
+
+	// ListOrdersFunc mocks the ListOrders method.
+	ListOrdersFunc func(ctx context.Context, filter models.OrderFilter) (models.OrderPage, error)
+
+	// UnavailableProductsFunc mocks the UnavailableProducts method.
+	UnavailableProductsFunc func(ctx context.Context, ids []string, at time.Time) ([]string, error)
+
+	// UpdateOrderStatusFunc mocks the UpdateOrderStatus method.
+	UpdateOrderStatusFunc func(ctx context.Context, id string, from models.OrderStatus, to models.OrderStatus, reason string) (models.Order, error)
+
 	// calls tracks calls to the methods.
 	calls struct {
 		// CheckCoupon holds details about calls to the CheckCoupon method.
@@ -54,9 +91,65 @@ type OrderStorableMock struct {
 			// Order is the order argument value.
 			Order models.Order
 		}
+		// GetCouponRules holds details about calls to the GetCouponRules method.
+		GetCouponRules []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Coupon is the coupon argument value.
+			Coupon string
+		}
+		// GetOrder holds details about calls to the GetOrder method.
+		GetOrder []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID string
+		}
+		// GetProducts holds details about calls to the GetProducts method.
+		GetProducts []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Ids is the ids argument value.
+			Ids []string
+		}
+		// ListOrders holds details about calls to the ListOrders method.
+		ListOrders []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Filter is the filter argument value.
+			Filter models.OrderFilter
+		}
+		// UnavailableProducts holds details about calls to the UnavailableProducts method.
+		UnavailableProducts []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Ids is the ids argument value.
+			Ids []string
+			// At is the at argument value.
+			At time.Time
+		}
+		// UpdateOrderStatus holds details about calls to the UpdateOrderStatus method.
+		UpdateOrderStatus []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID string
+			// From is the from argument value.
+			From models.OrderStatus
+			// To is the to argument value.
+			To models.OrderStatus
+			// Reason is the reason argument value.
+			Reason string
+		}
 	}
-	lockCheckCoupon sync.RWMutex
-	lockCreateOrder sync.RWMutex
+	lockCheckCoupon         sync.RWMutex
+	lockCreateOrder         sync.RWMutex
+	lockGetCouponRules      sync.RWMutex
+	lockGetOrder            sync.RWMutex
+	lockGetProducts         sync.RWMutex
+	lockListOrders          sync.RWMutex
+	lockUnavailableProducts sync.RWMutex
+	lockUpdateOrderStatus   sync.RWMutex
 }
 
 // CheckCoupon calls CheckCouponFunc.
@@ -131,6 +224,238 @@ func (mock *OrderStorableMock) CreateOrderCalls() []struct {
 	return calls
 }
 
+// GetCouponRules calls GetCouponRulesFunc.
+func (mock *OrderStorableMock) GetCouponRules(ctx context.Context, coupon string) ([]models.CouponRule, error) {
+	if mock.GetCouponRulesFunc == nil {
+		panic("OrderStorableMock.GetCouponRulesFunc: method is nil but OrderStorable.GetCouponRules was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Coupon string
+	}{
+		Ctx:    ctx,
+		Coupon: coupon,
+	}
+	mock.lockGetCouponRules.Lock()
+	mock.calls.GetCouponRules = append(mock.calls.GetCouponRules, callInfo)
+	mock.lockGetCouponRules.Unlock()
+	return mock.GetCouponRulesFunc(ctx, coupon)
+}
+
+// GetCouponRulesCalls gets all the calls that were made to GetCouponRules.
+// Check the length with:
+//
+//	len(mockedOrderStorable.GetCouponRulesCalls())
+func (mock *OrderStorableMock) GetCouponRulesCalls() []struct {
+	Ctx    context.Context
+	Coupon string
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Coupon string
+	}
+	mock.lockGetCouponRules.RLock()
+	calls = mock.calls.GetCouponRules
+	mock.lockGetCouponRules.RUnlock()
+	return calls
+}
+
+// GetOrder calls GetOrderFunc.
+func (mock *OrderStorableMock) GetOrder(ctx context.Context, id string) (models.Order, error) {
+	if mock.GetOrderFunc == nil {
+		panic("OrderStorableMock.GetOrderFunc: method is nil but OrderStorable.GetOrder was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		ID  string
+	}{
+		Ctx: ctx,
+		ID:  id,
+	}
+	mock.lockGetOrder.Lock()
+	mock.calls.GetOrder = append(mock.calls.GetOrder, callInfo)
+	mock.lockGetOrder.Unlock()
+	return mock.GetOrderFunc(ctx, id)
+}
+
+// GetOrderCalls gets all the calls that were made to GetOrder.
+// Check the length with:
+//
+//	len(mockedOrderStorable.GetOrderCalls())
+func (mock *OrderStorableMock) GetOrderCalls() []struct {
+	Ctx context.Context
+	ID  string
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  string
+	}
+	mock.lockGetOrder.RLock()
+	calls = mock.calls.GetOrder
+	mock.lockGetOrder.RUnlock()
+	return calls
+}
+
+// GetProducts calls GetProductsFunc.
+func (mock *OrderStorableMock) GetProducts(ctx context.Context, ids []string) ([]models.Product, error) {
+	if mock.GetProductsFunc == nil {
+		panic("OrderStorableMock.GetProductsFunc: method is nil but OrderStorable.GetProducts was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Ids []string
+	}{
+		Ctx: ctx,
+		Ids: ids,
+	}
+	mock.lockGetProducts.Lock()
+	mock.calls.GetProducts = append(mock.calls.GetProducts, callInfo)
+	mock.lockGetProducts.Unlock()
+	return mock.GetProductsFunc(ctx, ids)
+}
+
+// GetProductsCalls gets all the calls that were made to GetProducts.
+// Check the length with:
+//
+//	len(mockedOrderStorable.GetProductsCalls())
+func (mock *OrderStorableMock) GetProductsCalls() []struct {
+	Ctx context.Context
+	Ids []string
+} {
+	var calls []struct {
+		Ctx context.Context
+		Ids []string
+	}
+	mock.lockGetProducts.RLock()
+	calls = mock.calls.GetProducts
+	mock.lockGetProducts.RUnlock()
+	return calls
+}
+
+// ListOrders calls ListOrdersFunc.
+func (mock *OrderStorableMock) ListOrders(ctx context.Context, filter models.OrderFilter) (models.OrderPage, error) {
+	if mock.ListOrdersFunc == nil {
+		panic("OrderStorableMock.ListOrdersFunc: method is nil but OrderStorable.ListOrders was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Filter models.OrderFilter
+	}{
+		Ctx:    ctx,
+		Filter: filter,
+	}
+	mock.lockListOrders.Lock()
+	mock.calls.ListOrders = append(mock.calls.ListOrders, callInfo)
+	mock.lockListOrders.Unlock()
+	return mock.ListOrdersFunc(ctx, filter)
+}
+
+// ListOrdersCalls gets all the calls that were made to ListOrders.
+// Check the length with:
+//
+//	len(mockedOrderStorable.ListOrdersCalls())
+func (mock *OrderStorableMock) ListOrdersCalls() []struct {
+	Ctx    context.Context
+	Filter models.OrderFilter
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Filter models.OrderFilter
+	}
+	mock.lockListOrders.RLock()
+	calls = mock.calls.ListOrders
+	mock.lockListOrders.RUnlock()
+	return calls
+}
+
+// UnavailableProducts calls UnavailableProductsFunc.
+func (mock *OrderStorableMock) UnavailableProducts(ctx context.Context, ids []string, at time.Time) ([]string, error) {
+	if mock.UnavailableProductsFunc == nil {
+		panic("OrderStorableMock.UnavailableProductsFunc: method is nil but OrderStorable.UnavailableProducts was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Ids []string
+		At  time.Time
+	}{
+		Ctx: ctx,
+		Ids: ids,
+		At:  at,
+	}
+	mock.lockUnavailableProducts.Lock()
+	mock.calls.UnavailableProducts = append(mock.calls.UnavailableProducts, callInfo)
+	mock.lockUnavailableProducts.Unlock()
+	return mock.UnavailableProductsFunc(ctx, ids, at)
+}
+
+// UnavailableProductsCalls gets all the calls that were made to UnavailableProducts.
+// Check the length with:
+//
+//	len(mockedOrderStorable.UnavailableProductsCalls())
+func (mock *OrderStorableMock) UnavailableProductsCalls() []struct {
+	Ctx context.Context
+	Ids []string
+	At  time.Time
+} {
+	var calls []struct {
+		Ctx context.Context
+		Ids []string
+		At  time.Time
+	}
+	mock.lockUnavailableProducts.RLock()
+	calls = mock.calls.UnavailableProducts
+	mock.lockUnavailableProducts.RUnlock()
+	return calls
+}
+
+// UpdateOrderStatus calls UpdateOrderStatusFunc.
+func (mock *OrderStorableMock) UpdateOrderStatus(ctx context.Context, id string, from models.OrderStatus, to models.OrderStatus, reason string) (models.Order, error) {
+	if mock.UpdateOrderStatusFunc == nil {
+		panic("OrderStorableMock.UpdateOrderStatusFunc: method is nil but OrderStorable.UpdateOrderStatus was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		ID     string
+		From   models.OrderStatus
+		To     models.OrderStatus
+		Reason string
+	}{
+		Ctx:    ctx,
+		ID:     id,
+		From:   from,
+		To:     to,
+		Reason: reason,
+	}
+	mock.lockUpdateOrderStatus.Lock()
+	mock.calls.UpdateOrderStatus = append(mock.calls.UpdateOrderStatus, callInfo)
+	mock.lockUpdateOrderStatus.Unlock()
+	return mock.UpdateOrderStatusFunc(ctx, id, from, to, reason)
+}
+
+// UpdateOrderStatusCalls gets all the calls that were made to UpdateOrderStatus.
+// Check the length with:
+//
+//	len(mockedOrderStorable.UpdateOrderStatusCalls())
+func (mock *OrderStorableMock) UpdateOrderStatusCalls() []struct {
+	Ctx    context.Context
+	ID     string
+	From   models.OrderStatus
+	To     models.OrderStatus
+	Reason string
+} {
+	var calls []struct {
+		Ctx    context.Context
+		ID     string
+		From   models.OrderStatus
+		To     models.OrderStatus
+		Reason string
+	}
+	mock.lockUpdateOrderStatus.RLock()
+	calls = mock.calls.UpdateOrderStatus
+	mock.lockUpdateOrderStatus.RUnlock()
+	return calls
+}
+
 // Ensure, that IdempotencyStoreMock does implement IdempotencyStore.
 // If this is not the case, regenerate this file with moq.
 var _ IdempotencyStore = &IdempotencyStoreMock{}
@@ -0,0 +1,117 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/sgrumley/kart-challenge/pkg/models"
+)
+
+// ErrInvalidModifiers is returned when the modifiers picked for an item do not
+// belong to the product or break the selection rules of a modifier group.
+var ErrInvalidModifiers = errors.New("invalid modifier selection")
+
+// PriceOrder fills in the unit price and line total of every item along with the
+// order subtotal, the discount granted by the coupon rules and the grand total.
+func PriceOrder(order models.Order, products []models.Product, rules []models.CouponRule) (models.Order, error) {
+	byID := make(map[string]models.Product, len(products))
+	for _, p := range products {
+		byID[p.ID] = p
+	}
+
+	items := make([]models.Item, len(order.Items))
+	var subtotal float64
+	for i, item := range order.Items {
+		product, ok := byID[item.ProductID]
+		if !ok {
+			return models.Order{}, fmt.Errorf("product id %s not found", item.ProductID)
+		}
+
+		modifiers, err := selectModifiers(product, item.Modifiers)
+		if err != nil {
+			return models.Order{}, fmt.Errorf("product id %s: %w", item.ProductID, err)
+		}
+
+		unitPrice := float64(product.Price)
+		for _, m := range modifiers {
+			unitPrice += float64(m.PriceDelta)
+		}
+		unitPrice = roundCents(unitPrice)
+		if unitPrice < 0 {
+			return models.Order{}, fmt.Errorf("product id %s: %w: negative unit price", item.ProductID, ErrInvalidModifiers)
+		}
+
+		lineTotal := roundCents(unitPrice * float64(item.Quantity))
+		items[i] = models.Item{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+			Modifiers: modifiers,
+			UnitPrice: float32(unitPrice),
+			LineTotal: float32(lineTotal),
+		}
+		subtotal += lineTotal
+	}
+
+	subtotal = roundCents(subtotal)
+	discount := Discount(items, products, subtotal, rules)
+
+	order.Items = items
+	order.Subtotal = float32(subtotal)
+	order.Discount = float32(discount)
+	order.Total = float32(roundCents(subtotal - discount))
+
+	return order, nil
+}
+
+// selectModifiers resolves the picked modifier ids against the modifier groups of
+// the product and checks every group is within its min and max selections.
+func selectModifiers(product models.Product, picked []models.Modifier) ([]models.Modifier, error) {
+	type option struct {
+		modifier models.Modifier
+		group    int
+	}
+	options := make(map[string]option)
+	for g, group := range product.ModifierGroups {
+		for _, m := range group.Modifiers {
+			options[m.ID] = option{modifier: m, group: g}
+		}
+	}
+
+	var selected []models.Modifier
+	counts := make([]int, len(product.ModifierGroups))
+	seen := make(map[string]bool, len(picked))
+	for _, m := range picked {
+		opt, ok := options[m.ID]
+		if !ok {
+			return nil, fmt.Errorf("%w: modifier %s is not offered", ErrInvalidModifiers, m.ID)
+		}
+		if seen[m.ID] {
+			return nil, fmt.Errorf("%w: modifier %s picked twice", ErrInvalidModifiers, m.ID)
+		}
+		seen[m.ID] = true
+		counts[opt.group]++
+		selected = append(selected, opt.modifier)
+	}
+
+	for g, group := range product.ModifierGroups {
+		if counts[g] < group.MinSelect || counts[g] > group.MaxSelect {
+			return nil, fmt.Errorf("%w: %s needs between %d and %d picks, got %d",
+				ErrInvalidModifiers, group.Name, group.MinSelect, group.MaxSelect, counts[g])
+		}
+	}
+
+	return selected, nil
+}
+
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+func productIDs(items []models.Item) []string {
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.ProductID
+	}
+	return ids
+}
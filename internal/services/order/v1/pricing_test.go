@@ -0,0 +1,174 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/sgrumley/kart-challenge/internal/services/order/v1/mapper"
+	"github.com/sgrumley/kart-challenge/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SelectModifiers(t *testing.T) {
+	t.Parallel()
+	fries := models.Product{
+		ID: "fries",
+		ModifierGroups: []models.ModifierGroup{
+			{
+				Name:      "Dip",
+				MinSelect: 0,
+				MaxSelect: 1,
+				Modifiers: []models.Modifier{{ID: "40000000-0000-0000-0000-000000000001", Name: "Aioli"}},
+			},
+		},
+	}
+
+	testCases := map[string]struct {
+		product models.Product
+		picked  []string
+		wantIDs []string
+		wantErr string
+	}{
+		"success/required_pick_only": {
+			product: burgerProduct(),
+			picked:  []string{testRegularID},
+			wantIDs: []string{testRegularID},
+		},
+		"success/max_extras": {
+			product: burgerProduct(),
+			picked:  []string{testLargeID, testBaconID, testCheeseID},
+			wantIDs: []string{testLargeID, testBaconID, testCheeseID},
+		},
+		"success/no_groups_no_picks": {
+			product: models.Product{ID: "plain"},
+		},
+		"error/below_min_select": {
+			product: burgerProduct(),
+			picked:  []string{testBaconID},
+			wantErr: "Size needs between 1 and 1 picks, got 0",
+		},
+		"error/above_max_select": {
+			product: burgerProduct(),
+			picked:  []string{testRegularID, testLargeID},
+			wantErr: "Size needs between 1 and 1 picks, got 2",
+		},
+		"error/modifier_of_other_product": {
+			product: burgerProduct(),
+			picked:  []string{testRegularID, fries.ModifierGroups[0].Modifiers[0].ID},
+			wantErr: "is not offered",
+		},
+		"error/duplicate_modifier": {
+			product: burgerProduct(),
+			picked:  []string{testRegularID, testBaconID, testBaconID},
+			wantErr: "picked twice",
+		},
+		"error/modifier_on_product_without_groups": {
+			product: models.Product{ID: "plain"},
+			picked:  []string{testBaconID},
+			wantErr: "is not offered",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, err := selectModifiers(tc.product, mapper.ModifiersFromRequest(tc.picked))
+			if tc.wantErr != "" {
+				require.ErrorIs(t, err, ErrInvalidModifiers)
+				assert.Contains(t, err.Error(), tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			ids := make([]string, len(got))
+			for i, m := range got {
+				ids[i] = m.ID
+			}
+			assert.ElementsMatch(t, tc.wantIDs, ids)
+		})
+	}
+}
+
+func Test_PriceOrder(t *testing.T) {
+	t.Parallel()
+	coffee := models.Product{ID: "coffee", Category: "Beverages", Price: 2.99}
+
+	testCases := map[string]struct {
+		items        []models.Item
+		products     []models.Product
+		rules        []models.CouponRule
+		wantUnit     []float32
+		wantLine     []float32
+		wantSubtotal float32
+		wantDiscount float32
+		wantTotal    float32
+		wantErr      error
+	}{
+		"success/base_price_only": {
+			items:        []models.Item{{ProductID: "coffee", Quantity: 3}},
+			products:     []models.Product{coffee},
+			wantUnit:     []float32{2.99},
+			wantLine:     []float32{8.97},
+			wantSubtotal: 8.97,
+			wantTotal:    8.97,
+		},
+		"success/modifier_deltas_in_unit_price": {
+			items: []models.Item{
+				{ProductID: testBurgerID, Quantity: 2, Modifiers: mapper.ModifiersFromRequest([]string{testLargeID, testBaconID, testCheeseID})},
+				{ProductID: "coffee", Quantity: 1},
+			},
+			products:     []models.Product{burgerProduct(), coffee},
+			wantUnit:     []float32{21.49, 2.99},
+			wantLine:     []float32{42.98, 2.99},
+			wantSubtotal: 45.97,
+			wantTotal:    45.97,
+		},
+		"success/discount_on_priced_subtotal": {
+			items:        []models.Item{{ProductID: testBurgerID, Quantity: 1, Modifiers: mapper.ModifiersFromRequest([]string{testLargeID})}},
+			products:     []models.Product{burgerProduct()},
+			rules:        []models.CouponRule{{Kind: models.DiscountPercentage, Value: 10}},
+			wantUnit:     []float32{17.99},
+			wantLine:     []float32{17.99},
+			wantSubtotal: 17.99,
+			wantDiscount: 1.8,
+			wantTotal:    16.19,
+		},
+		"error/negative_unit_price": {
+			items: []models.Item{{ProductID: "coffee", Quantity: 1, Modifiers: mapper.ModifiersFromRequest([]string{"50000000-0000-0000-0000-000000000001"})}},
+			products: []models.Product{{
+				ID:    "coffee",
+				Price: 2.99,
+				ModifierGroups: []models.ModifierGroup{{
+					Name:      "Promo",
+					MaxSelect: 1,
+					Modifiers: []models.Modifier{{ID: "50000000-0000-0000-0000-000000000001", PriceDelta: -5}},
+				}},
+			}},
+			wantErr: ErrInvalidModifiers,
+		},
+		"error/invalid_modifiers": {
+			items:    []models.Item{{ProductID: testBurgerID, Quantity: 1}},
+			products: []models.Product{burgerProduct()},
+			wantErr:  ErrInvalidModifiers,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, err := PriceOrder(models.Order{Items: tc.items}, tc.products, tc.rules)
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, got.Items, len(tc.wantUnit))
+			for i, item := range got.Items {
+				assert.InDelta(t, tc.wantUnit[i], item.UnitPrice, 0.001)
+				assert.InDelta(t, tc.wantLine[i], item.LineTotal, 0.001)
+			}
+			assert.InDelta(t, tc.wantSubtotal, got.Subtotal, 0.001)
+			assert.InDelta(t, tc.wantDiscount, got.Discount, 0.001)
+			assert.InDelta(t, tc.wantTotal, got.Total, 0.001)
+		})
+	}
+}
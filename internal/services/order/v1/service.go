@@ -2,14 +2,19 @@ package v1
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
 	"github.com/sgrumley/kart-challenge/internal/services/order/v1/mapper"
 	"github.com/sgrumley/kart-challenge/internal/store"
 	"github.com/sgrumley/kart-challenge/pkg/idempotency"
 	"github.com/sgrumley/kart-challenge/pkg/logger"
+	"github.com/sgrumley/kart-challenge/pkg/middleware"
 	"github.com/sgrumley/kart-challenge/pkg/models"
 	"github.com/sgrumley/kart-challenge/pkg/web"
 )
@@ -24,6 +29,12 @@ var (
 type OrderStorable interface {
 	CreateOrder(ctx context.Context, order models.Order) (models.Order, error)
 	CheckCoupon(ctx context.Context, coupon string) bool
+	GetCouponRules(ctx context.Context, coupon string) ([]models.CouponRule, error)
+	GetProducts(ctx context.Context, ids []string) ([]models.Product, error)
+	GetOrder(ctx context.Context, id string) (models.Order, error)
+	ListOrders(ctx context.Context, filter models.OrderFilter) (models.OrderPage, error)
+	UpdateOrderStatus(ctx context.Context, id string, from, to models.OrderStatus, reason string) (models.Order, error)
+	UnavailableProducts(ctx context.Context, ids []string, at time.Time) ([]string, error)
 }
 
 type IdempotencyStore interface {
@@ -36,36 +47,131 @@ type OrderService struct {
 	validate    *validator.Validate
 	idemChecker IdempotencyStore
 	store       OrderStorable
+	apiKey      string
 }
 
-func NewService(store OrderStorable, idemChecker IdempotencyStore) *OrderService {
+// NewService builds the order service. The back-office routes require the
+// apiKey in the api_key header.
+func NewService(store OrderStorable, idemChecker IdempotencyStore, apiKey string) *OrderService {
 	return &OrderService{
 		store:       store,
 		idemChecker: idemChecker,
+		apiKey:      apiKey,
 		validate:    validator.New(),
 	}
 }
 
 var (
+	Err400InvalidOrderID = &web.Error{
+		Status:      http.StatusBadRequest,
+		Code:        "invalid_order_id",
+		Description: "Invalid ID supplied",
+	}
+
+	Err400InvalidQuery = &web.Error{
+		Status:      http.StatusBadRequest,
+		Code:        "invalid_query_parameters",
+		Description: "Invalid query parameters supplied",
+	}
+
 	Err401InvalidRequestBody = &web.Error{
 		Status:      http.StatusBadRequest,
 		Code:        "invalid_request_body",
 		Description: "Invalid input",
 	}
 
+	Err404OrderNotFound = &web.Error{
+		Status:      http.StatusNotFound,
+		Code:        "order_not_found",
+		Description: "Order not found",
+	}
+
 	Err409ConflictDuplicateRequest = &web.Error{
 		Status:      http.StatusConflict,
 		Code:        "request_already_inprogress",
 		Description: "The provided Idempotency-Key has already been used",
 	}
 
+	Err409IllegalStatusTransition = &web.Error{
+		Status:      http.StatusConflict,
+		Code:        "illegal_status_transition",
+		Description: "The order cannot move to the requested status",
+	}
+
+	Err409OrderNotCancellable = &web.Error{
+		Status:      http.StatusConflict,
+		Code:        "order_not_cancellable",
+		Description: "The order can no longer be cancelled",
+	}
+
 	Err422Validation = &web.Error{
 		Status:      http.StatusUnprocessableEntity,
 		Code:        "invalid_order_detail",
 		Description: "Validation exception",
 	}
+
+	Err422InvalidModifiers = &web.Error{
+		Status:      http.StatusUnprocessableEntity,
+		Code:        "invalid_modifier_selection",
+		Description: "The modifiers picked for a product are not offered or break its selection rules",
+	}
+
+	Err422CouponNotYetActive = &web.Error{
+		Status:      http.StatusUnprocessableEntity,
+		Code:        "coupon_not_yet_active",
+		Description: "The coupon cannot be used yet",
+	}
+
+	Err422CouponExpired = &web.Error{
+		Status:      http.StatusUnprocessableEntity,
+		Code:        "coupon_expired",
+		Description: "The coupon has expired",
+	}
+
+	Err422CouponExhausted = &web.Error{
+		Status:      http.StatusUnprocessableEntity,
+		Code:        "coupon_exhausted",
+		Description: "The coupon has reached its redemption limit",
+	}
 )
 
+// Err422OutOfStock names the product the order asked for more of than is in stock.
+func Err422OutOfStock(productID string) *web.Error {
+	return &web.Error{
+		Status:      http.StatusUnprocessableEntity,
+		Code:        "out_of_stock",
+		Description: fmt.Sprintf("Product %s is out of stock", productID),
+	}
+}
+
+// Err422ProductUnavailable names the product ordered outside its availability windows.
+func Err422ProductUnavailable(productID string) *web.Error {
+	return &web.Error{
+		Status:      http.StatusUnprocessableEntity,
+		Code:        "product_unavailable",
+		Description: fmt.Sprintf("Product %s is not available at this time", productID),
+	}
+}
+
+// createOrderError tells the customer which product ran out or which coupon
+// limit stopped the order.
+func createOrderError(err error) error {
+	var stockErr *store.OutOfStockError
+	if errors.As(err, &stockErr) {
+		return Err422OutOfStock(stockErr.ProductID)
+	}
+
+	switch {
+	case errors.Is(err, store.ErrCouponNotYetActive):
+		return Err422CouponNotYetActive
+	case errors.Is(err, store.ErrCouponExpired):
+		return Err422CouponExpired
+	case errors.Is(err, store.ErrCouponExhausted):
+		return Err422CouponExhausted
+	}
+	return fmt.Errorf("failed creating order in store: %w", err)
+}
+
 func (s *OrderService) CreateOrder(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	key := r.Header.Get("Idempotency-Key")
@@ -94,18 +200,65 @@ func (s *OrderService) CreateOrder(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	rules := []models.CouponRule{}
 	if req.CouponCode != "" {
 		if valid := s.store.CheckCoupon(ctx, req.CouponCode); !valid {
 			logger.Error(ctx, "invalid coupon", fmt.Errorf("coupon was not in atleast 2 files"))
 			web.RespondJSONError(w, Err422Validation)
 			return
 		}
+
+		var err error
+		rules, err = s.store.GetCouponRules(ctx, req.CouponCode)
+		if err != nil {
+			logger.Error(ctx, "failed fetching coupon rules from store", err)
+			web.RespondJSONError(w, fmt.Errorf("failed fetching coupon rules from store: %w", err))
+			return
+		}
+	}
+
+	order := mapper.CreateOrderFromRequest(req)
+	// there is no customer login, so the id per customer coupon limits count
+	// against is only trusted from back-office callers holding the api key
+	if !middleware.HasAPIKey(r, s.apiKey) {
+		order.CustomerID = ""
+	}
+
+	products, err := s.store.GetProducts(ctx, productIDs(order.Items))
+	if err != nil {
+		logger.Error(ctx, "failed fetching products from store", err)
+		web.RespondJSONError(w, fmt.Errorf("failed fetching products from store: %w", err))
+		return
+	}
+
+	unavailable, err := s.store.UnavailableProducts(ctx, productIDs(order.Items), time.Now())
+	if err != nil {
+		logger.Error(ctx, "failed checking product availability in store", err)
+		web.RespondJSONError(w, fmt.Errorf("failed checking product availability in store: %w", err))
+		return
+	}
+	if len(unavailable) > 0 {
+		logger.Error(ctx, "product unavailable", fmt.Errorf("products %v are outside their availability windows", unavailable))
+		web.RespondJSONError(w, Err422ProductUnavailable(unavailable[0]))
+		return
+	}
+
+	order, err = PriceOrder(order, products, rules)
+	if errors.Is(err, ErrInvalidModifiers) {
+		logger.Error(ctx, "invalid modifiers", err)
+		web.RespondJSONError(w, Err422InvalidModifiers)
+		return
+	}
+	if err != nil {
+		logger.Error(ctx, "failed pricing order", err)
+		web.RespondJSONError(w, Err422Validation)
+		return
 	}
 
-	order, err := s.store.CreateOrder(ctx, mapper.CreateOrderFromRequest(req))
+	order, err = s.store.CreateOrder(ctx, order)
 	if err != nil {
 		logger.Error(ctx, "failed creating order in store", err)
-		web.RespondJSONError(w, fmt.Errorf("failed creating order in store: %w", err))
+		web.RespondJSONError(w, createOrderError(err))
 		return
 	}
 
@@ -113,3 +266,161 @@ func (s *OrderService) CreateOrder(w http.ResponseWriter, r *http.Request) {
 
 	web.Respond(w, http.StatusCreated, mapper.CreateOrderToResponse(order))
 }
+
+func (s *OrderService) GetOrder(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	orderID := chi.URLParam(r, "order_id")
+	if _, err := uuid.Parse(orderID); err != nil {
+		logger.Error(ctx, "invalid order id is not uuid", Err400InvalidOrderID)
+		web.RespondJSONError(w, Err400InvalidOrderID)
+		return
+	}
+
+	order, err := s.store.GetOrder(ctx, orderID)
+	if err != nil {
+		logger.Error(ctx, "could not find order with id: "+orderID, err)
+		web.RespondJSONError(w, Err404OrderNotFound)
+		return
+	}
+	// anyone holding the order id can look it up, the customer is only shown to
+	// back-office callers
+	if !middleware.HasAPIKey(r, s.apiKey) {
+		order.CustomerID = ""
+	}
+
+	web.Respond(w, http.StatusOK, mapper.GetOrderToResponse(&order))
+}
+
+func (s *OrderService) ListOrders(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := mapper.ListOrdersRequestFromQuery(r.URL.Query())
+	if err != nil {
+		logger.Error(ctx, "invalid query parameters", err)
+		web.RespondJSONError(w, Err400InvalidQuery)
+		return
+	}
+
+	if err := s.validate.Struct(req); err != nil {
+		logger.Error(ctx, "validation failed", err)
+		web.RespondJSONError(w, Err400InvalidQuery)
+		return
+	}
+
+	page, err := s.store.ListOrders(ctx, mapper.OrderFilterFromRequest(req))
+	if err != nil {
+		if errors.Is(err, store.ErrInvalidCursor) {
+			logger.Error(ctx, "invalid cursor", err)
+			web.RespondJSONError(w, Err400InvalidQuery)
+			return
+		}
+		logger.Error(ctx, "failed listing orders in store", err)
+		web.RespondJSONError(w, fmt.Errorf("failed listing orders in store: %w", err))
+		return
+	}
+
+	web.Respond(w, http.StatusOK, mapper.ListOrdersToResponse(page))
+}
+
+func (s *OrderService) UpdateOrderStatus(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	orderID := chi.URLParam(r, "order_id")
+	if _, err := uuid.Parse(orderID); err != nil {
+		logger.Error(ctx, "invalid order id is not uuid", Err400InvalidOrderID)
+		web.RespondJSONError(w, Err400InvalidOrderID)
+		return
+	}
+
+	var req mapper.UpdateOrderStatusRequest
+	if err := web.DecodeBody(r, &req); err != nil {
+		logger.Error(ctx, "invalid request body", err)
+		web.RespondJSONError(w, Err401InvalidRequestBody)
+		return
+	}
+
+	if err := s.validate.Struct(req); err != nil {
+		logger.Error(ctx, "validation failed", err)
+		web.RespondJSONError(w, Err422Validation)
+		return
+	}
+
+	order, err := s.store.GetOrder(ctx, orderID)
+	if err != nil {
+		logger.Error(ctx, "could not find order with id: "+orderID, err)
+		web.RespondJSONError(w, Err404OrderNotFound)
+		return
+	}
+
+	to := models.OrderStatus(req.Status)
+	if !CanTransition(order.Status, to) {
+		logger.Error(ctx, "illegal status transition", fmt.Errorf("order %s cannot move from %s to %s", orderID, order.Status, to))
+		web.RespondJSONError(w, Err409IllegalStatusTransition)
+		return
+	}
+
+	order, err = s.store.UpdateOrderStatus(ctx, orderID, order.Status, to, req.Reason)
+	if err != nil {
+		if errors.Is(err, store.ErrStatusConflict) {
+			logger.Error(ctx, "order status changed concurrently", err)
+			web.RespondJSONError(w, Err409IllegalStatusTransition)
+			return
+		}
+		logger.Error(ctx, "failed updating order status in store", err)
+		web.RespondJSONError(w, fmt.Errorf("failed updating order status in store: %w", err))
+		return
+	}
+
+	web.Respond(w, http.StatusOK, mapper.GetOrderToResponse(&order))
+}
+
+// CancelOrder cancels an order that has not started being prepared. The stock
+// and any coupon redemption held by the order are released in the same transaction.
+func (s *OrderService) CancelOrder(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	orderID := chi.URLParam(r, "order_id")
+	if _, err := uuid.Parse(orderID); err != nil {
+		logger.Error(ctx, "invalid order id is not uuid", Err400InvalidOrderID)
+		web.RespondJSONError(w, Err400InvalidOrderID)
+		return
+	}
+
+	var req mapper.CancelOrderRequest
+	if err := web.DecodeBody(r, &req); err != nil {
+		logger.Error(ctx, "invalid request body", err)
+		web.RespondJSONError(w, Err401InvalidRequestBody)
+		return
+	}
+
+	if err := s.validate.Struct(req); err != nil {
+		logger.Error(ctx, "validation failed", err)
+		web.RespondJSONError(w, Err422Validation)
+		return
+	}
+
+	order, err := s.store.GetOrder(ctx, orderID)
+	if err != nil {
+		logger.Error(ctx, "could not find order with id: "+orderID, err)
+		web.RespondJSONError(w, Err404OrderNotFound)
+		return
+	}
+
+	if !CanTransition(order.Status, models.OrderStatusCancelled) {
+		logger.Error(ctx, "order not cancellable", fmt.Errorf("order %s is %s", orderID, order.Status))
+		web.RespondJSONError(w, Err409OrderNotCancellable)
+		return
+	}
+
+	order, err = s.store.UpdateOrderStatus(ctx, orderID, order.Status, models.OrderStatusCancelled, req.Reason)
+	if err != nil {
+		if errors.Is(err, store.ErrStatusConflict) {
+			logger.Error(ctx, "order status changed concurrently", err)
+			web.RespondJSONError(w, Err409OrderNotCancellable)
+			return
+		}
+		logger.Error(ctx, "failed cancelling order in store", err)
+		web.RespondJSONError(w, fmt.Errorf("failed cancelling order in store: %w", err))
+		return
+	}
+
+	web.Respond(w, http.StatusOK, mapper.GetOrderToResponse(&order))
+}
@@ -6,8 +6,10 @@ import (
 	"log/slog"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/sgrumley/kart-challenge/internal/services/order/v1/mapper"
+	"github.com/sgrumley/kart-challenge/internal/store"
 	"github.com/sgrumley/kart-challenge/pkg/logger"
 	"github.com/sgrumley/kart-challenge/pkg/models"
 	"github.com/sgrumley/kart-challenge/pkg/testhelper"
@@ -16,6 +18,12 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+const testAPIKey = "a-secret-key"
+
+var authHeaders = map[string]string{
+	"api_key": testAPIKey,
+}
+
 type OrderRequestOption func(order *mapper.CreateOrderRequest)
 
 func NewDefaultOrderRequest(opts ...OrderRequestOption) mapper.CreateOrderRequest {
@@ -40,6 +48,114 @@ func NewDefaultOrderRequest(opts ...OrderRequestOption) mapper.CreateOrderReques
 	return defaultOrder
 }
 
+func defaultProducts() []models.Product {
+	return []models.Product{
+		{
+			ID:       "00000000-0000-0000-0000-000000000001",
+			Name:     "Eggs",
+			Category: "Breakfast",
+			Price:    8.99,
+		},
+		{
+			ID:       "00000000-0000-0000-0000-000000000002",
+			Name:     "Bacon",
+			Category: "Breakfast",
+			Price:    7.99,
+		},
+	}
+}
+
+// redeemFailingStoreMock prices the default order and then fails to create it
+// with the given error, as the store does when a coupon cannot be redeemed.
+func redeemFailingStoreMock(err error) *OrderStorableMock {
+	return &OrderStorableMock{
+		CheckCouponFunc: func(ctx context.Context, coupon string) bool {
+			return true
+		},
+		GetCouponRulesFunc: func(ctx context.Context, coupon string) ([]models.CouponRule, error) {
+			return []models.CouponRule{}, nil
+		},
+		GetProductsFunc: func(ctx context.Context, ids []string) ([]models.Product, error) {
+			return defaultProducts(), nil
+		},
+		UnavailableProductsFunc: func(ctx context.Context, ids []string, at time.Time) ([]string, error) {
+			return nil, nil
+		},
+		CreateOrderFunc: func(ctx context.Context, order models.Order) (models.Order, error) {
+			return models.Order{}, err
+		},
+	}
+}
+
+// burgerProduct offers a required single choice size and up to two extras.
+func burgerProduct() models.Product {
+	return models.Product{
+		ID:       testBurgerID,
+		Name:     "Classic Cheeseburger",
+		Category: "Main Courses",
+		Price:    15.99,
+		ModifierGroups: []models.ModifierGroup{
+			{
+				ID:        "20000000-0000-0000-0000-000000000001",
+				Name:      "Size",
+				MinSelect: 1,
+				MaxSelect: 1,
+				Modifiers: []models.Modifier{
+					{ID: testRegularID, Name: "Regular"},
+					{ID: testLargeID, Name: "Large", PriceDelta: 2},
+				},
+			},
+			{
+				ID:        "20000000-0000-0000-0000-000000000002",
+				Name:      "Extras",
+				MinSelect: 0,
+				MaxSelect: 2,
+				Modifiers: []models.Modifier{
+					{ID: testBaconID, Name: "Bacon", PriceDelta: 2.5},
+					{ID: testCheeseID, Name: "Extra Cheese", PriceDelta: 1},
+				},
+			},
+		},
+	}
+}
+
+const (
+	testBurgerID  = "00000000-0000-0000-0000-000000000006"
+	testRegularID = "30000000-0000-0000-0000-000000000001"
+	testLargeID   = "30000000-0000-0000-0000-000000000002"
+	testBaconID   = "30000000-0000-0000-0000-000000000003"
+	testCheeseID  = "30000000-0000-0000-0000-000000000004"
+)
+
+// burgerOrderRequest orders two burgers with the given modifiers.
+func burgerOrderRequest(modifiers ...string) *mapper.CreateOrderRequest {
+	return &mapper.CreateOrderRequest{
+		Items: []mapper.Item{
+			{
+				ProductID: testBurgerID,
+				Quantity:  2,
+				Modifiers: modifiers,
+			},
+		},
+	}
+}
+
+// burgerStoreMock returns the burger product and echoes the order it is given.
+func burgerStoreMock() *OrderStorableMock {
+	return &OrderStorableMock{
+		GetProductsFunc: func(ctx context.Context, ids []string) ([]models.Product, error) {
+			return []models.Product{burgerProduct()}, nil
+		},
+		UnavailableProductsFunc: func(ctx context.Context, ids []string, at time.Time) ([]string, error) {
+			return nil, nil
+		},
+		CreateOrderFunc: func(ctx context.Context, order models.Order) (models.Order, error) {
+			order.ID = "12300000-0000-0000-0000-000000000000"
+			return order, nil
+		},
+	}
+}
+
 func Test_API_Service_CreateOrder(t *testing.T) {
 	t.Parallel()
 	testCases := map[string]struct {
@@ -68,60 +184,73 @@ func Test_API_Service_CreateOrder(t *testing.T) {
 				CheckCouponFunc: func(ctx context.Context, coupon string) bool {
 					return true
 				},
+				GetCouponRulesFunc: func(ctx context.Context, coupon string) ([]models.CouponRule, error) {
+					return []models.CouponRule{}, nil
+				},
+				GetProductsFunc: func(ctx context.Context, ids []string) ([]models.Product, error) {
+					return defaultProducts(), nil
+				},
+				UnavailableProductsFunc: func(ctx context.Context, ids []string, at time.Time) ([]string, error) {
+					return nil, nil
+				},
 				CreateOrderFunc: func(ctx context.Context, order models.Order) (models.Order, error) {
-					return models.Order{
-						ID: "12300000-0000-0000-0000-000000000000",
-						Items: []models.Item{
-							{
-								ProductID: "00000000-0000-0000-0000-000000000001",
-								Quantity:  1,
-							},
-							{
-								ProductID: "00000000-0000-0000-0000-000000000002",
-								Quantity:  2,
-							},
-						},
-						Products: []models.Product{
-							{
-								ID:       "00000000-0000-0000-0000-000000000001",
-								Name:     "Eggs",
-								Category: "Breakfast",
-								Price:    8.99,
-							},
-							{
-								ID:       "00000000-0000-0000-0000-000000000002",
-								Name:     "Bacon",
-								Category: "Breakfast",
-								Price:    7.99,
-							},
-						},
-					}, nil
+					order.ID = "12300000-0000-0000-0000-000000000000"
+					order.Products = defaultProducts()
+					return order, nil
 				},
 			},
 			wantAssertion: func(t *testing.T, got *http.Response, storeMock *OrderStorableMock) {
 				require.Equal(t, http.StatusCreated, got.StatusCode)
 				require.Len(t, storeMock.CreateOrderCalls(), 1)
+				require.Len(t, storeMock.GetProductsCalls(), 1)
 				defaultRequest := NewDefaultOrderRequest()
+				assert.Equal(t, []string{
+					"00000000-0000-0000-0000-000000000001",
+					"00000000-0000-0000-0000-000000000002",
+				}, storeMock.GetProductsCalls()[0].Ids)
+
 				expectedItems := []models.Item{
 					{
 						ProductID: "00000000-0000-0000-0000-000000000001",
 						Quantity:  1,
+						UnitPrice: 8.99,
+						LineTotal: 8.99,
 					},
 					{
 						ProductID: "00000000-0000-0000-0000-000000000002",
 						Quantity:  2,
+						UnitPrice: 7.99,
+						LineTotal: 15.98,
 					},
 				}
 
 				expectedStoreCalledWith := models.Order{
 					CouponCode: defaultRequest.CouponCode,
 					Items:      expectedItems,
+					Subtotal:   24.97,
+					Total:      24.97,
 				}
 				assert.Equal(t, expectedStoreCalledWith, storeMock.CreateOrderCalls()[0].Order)
 
 				want := &mapper.CreateOrderResponse{
 					ID:    "12300000-0000-0000-0000-000000000000",
 					Items: defaultRequest.Items,
+					Lines: []mapper.LineItem{
+						{
+							ProductID: "00000000-0000-0000-0000-000000000001",
+							Quantity:  1,
+							UnitPrice: 8.99,
+							LineTotal: 8.99,
+						},
+						{
+							ProductID: "00000000-0000-0000-0000-000000000002",
+							Quantity:  2,
+							UnitPrice: 7.99,
+							LineTotal: 15.98,
+						},
+					},
+					Subtotal: 24.97,
+					Total:    24.97,
 					Products: []mapper.Product{
 						{
 							ID:       "00000000-0000-0000-0000-000000000001",
@@ -142,6 +271,110 @@ func Test_API_Service_CreateOrder(t *testing.T) {
 				assert.Equal(t, want, &actual)
 			},
 		},
+		"success/coupon_discount_applied": {
+			req: func() *mapper.CreateOrderRequest {
+				def := NewDefaultOrderRequest()
+				return &def
+			},
+			headers: map[string]string{
+				"Idempotency-Key": "key",
+				"api_key":         "a-secret-key",
+			},
+			idemMock: &IdempotencyStoreMock{
+				ExistsFunc: func(key string) bool {
+					return false
+				},
+				SetFunc: func(key string) {},
+			},
+			storeMock: &OrderStorableMock{
+				CheckCouponFunc: func(ctx context.Context, coupon string) bool {
+					return true
+				},
+				GetCouponRulesFunc: func(ctx context.Context, coupon string) ([]models.CouponRule, error) {
+					return []models.CouponRule{
+						{
+							CouponCode: coupon,
+							Kind:       models.DiscountPercentage,
+							Value:      10,
+						},
+					}, nil
+				},
+				GetProductsFunc: func(ctx context.Context, ids []string) ([]models.Product, error) {
+					return defaultProducts(), nil
+				},
+				UnavailableProductsFunc: func(ctx context.Context, ids []string, at time.Time) ([]string, error) {
+					return nil, nil
+				},
+				CreateOrderFunc: func(ctx context.Context, order models.Order) (models.Order, error) {
+					order.ID = "12300000-0000-0000-0000-000000000000"
+					return order, nil
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *OrderStorableMock) {
+				require.Equal(t, http.StatusCreated, got.StatusCode)
+				require.Len(t, storeMock.GetCouponRulesCalls(), 1)
+				assert.Equal(t, "FIFTYOFF", storeMock.GetCouponRulesCalls()[0].Coupon)
+				require.Len(t, storeMock.CreateOrderCalls(), 1)
+
+				stored := storeMock.CreateOrderCalls()[0].Order
+				assert.Equal(t, float32(24.97), stored.Subtotal)
+				assert.Equal(t, float32(2.5), stored.Discount)
+				assert.Equal(t, float32(22.47), stored.Total)
+
+				actual := testhelper.PayloadAsType[mapper.CreateOrderResponse](t, got.Body)
+				assert.Equal(t, float32(2.5), actual.Discount)
+				assert.Equal(t, float32(22.47), actual.Total)
+			},
+		},
+		"success/modifiers_priced": {
+			req: func() *mapper.CreateOrderRequest {
+				return burgerOrderRequest(testLargeID, testBaconID)
+			},
+			headers: map[string]string{
+				"Idempotency-Key": "key",
+			},
+			idemMock: &IdempotencyStoreMock{
+				ExistsFunc: func(key string) bool {
+					return false
+				},
+				SetFunc: func(key string) {},
+			},
+			storeMock: burgerStoreMock(),
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *OrderStorableMock) {
+				require.Equal(t, http.StatusCreated, got.StatusCode)
+				require.Len(t, storeMock.CreateOrderCalls(), 1)
+
+				stored := storeMock.CreateOrderCalls()[0].Order
+				assert.Equal(t, []models.Item{
+					{
+						ProductID: testBurgerID,
+						Quantity:  2,
+						Modifiers: []models.Modifier{
+							{ID: testLargeID, Name: "Large", PriceDelta: 2},
+							{ID: testBaconID, Name: "Bacon", PriceDelta: 2.5},
+						},
+						UnitPrice: 20.49,
+						LineTotal: 40.98,
+					},
+				}, stored.Items)
+				assert.Equal(t, float32(40.98), stored.Total)
+
+				actual := testhelper.PayloadAsType[mapper.CreateOrderResponse](t, got.Body)
+				assert.Equal(t, []mapper.LineItem{
+					{
+						ProductID: testBurgerID,
+						Quantity:  2,
+						Modifiers: []mapper.LineModifier{
+							{ID: testLargeID, Name: "Large", PriceDelta: 2},
+							{ID: testBaconID, Name: "Bacon", PriceDelta: 2.5},
+						},
+						UnitPrice: 20.49,
+						LineTotal: 40.98,
+					},
+				}, actual.Lines)
+				assert.Equal(t, []string{testLargeID, testBaconID}, actual.Items[0].Modifiers)
+			},
+		},
 		"error/missing_idempotency_key": {
 			req: func() *mapper.CreateOrderRequest {
 				def := NewDefaultOrderRequest()
@@ -207,6 +440,100 @@ func Test_API_Service_CreateOrder(t *testing.T) {
 				assert.Equal(t, expectedError, actual)
 			},
 		},
+		"error/negative_quantity": {
+			req: func() *mapper.CreateOrderRequest {
+				def := NewDefaultOrderRequest()
+				def.Items[1].Quantity = -3
+				return &def
+			},
+			headers: map[string]string{
+				"Idempotency-Key": "key",
+			},
+			idemMock: &IdempotencyStoreMock{
+				ExistsFunc: func(key string) bool {
+					return false
+				},
+			},
+			storeMock: &OrderStorableMock{},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *OrderStorableMock) {
+				require.Equal(t, http.StatusUnprocessableEntity, got.StatusCode)
+				require.Len(t, storeMock.GetProductsCalls(), 0)
+				require.Len(t, storeMock.CreateOrderCalls(), 0)
+				actual := testhelper.PayloadAsType[web.ErrorResponse](t, got.Body)
+				expectedError := testhelper.MapExpectedErrorResponse(Err422Validation)
+				assert.Equal(t, expectedError, actual)
+			},
+		},
+		"error/empty_items": {
+			req: func() *mapper.CreateOrderRequest {
+				def := NewDefaultOrderRequest()
+				def.Items = []mapper.Item{}
+				return &def
+			},
+			headers: map[string]string{
+				"Idempotency-Key": "key",
+			},
+			idemMock: &IdempotencyStoreMock{
+				ExistsFunc: func(key string) bool {
+					return false
+				},
+			},
+			storeMock: &OrderStorableMock{},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *OrderStorableMock) {
+				require.Equal(t, http.StatusUnprocessableEntity, got.StatusCode)
+				require.Len(t, storeMock.GetProductsCalls(), 0)
+				require.Len(t, storeMock.CreateOrderCalls(), 0)
+				actual := testhelper.PayloadAsType[web.ErrorResponse](t, got.Body)
+				expectedError := testhelper.MapExpectedErrorResponse(Err422Validation)
+				assert.Equal(t, expectedError, actual)
+			},
+		},
+		"error/invalid_product_id": {
+			req: func() *mapper.CreateOrderRequest {
+				def := NewDefaultOrderRequest()
+				def.Items[0].ProductID = "not-a-uuid"
+				return &def
+			},
+			headers: map[string]string{
+				"Idempotency-Key": "key",
+			},
+			idemMock: &IdempotencyStoreMock{
+				ExistsFunc: func(key string) bool {
+					return false
+				},
+			},
+			storeMock: &OrderStorableMock{},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *OrderStorableMock) {
+				require.Equal(t, http.StatusUnprocessableEntity, got.StatusCode)
+				require.Len(t, storeMock.GetProductsCalls(), 0)
+				require.Len(t, storeMock.CreateOrderCalls(), 0)
+				actual := testhelper.PayloadAsType[web.ErrorResponse](t, got.Body)
+				expectedError := testhelper.MapExpectedErrorResponse(Err422Validation)
+				assert.Equal(t, expectedError, actual)
+			},
+		},
+		"error/invalid_modifier_id": {
+			req: func() *mapper.CreateOrderRequest {
+				return burgerOrderRequest(testRegularID, "not-a-uuid")
+			},
+			headers: map[string]string{
+				"Idempotency-Key": "key",
+			},
+			idemMock: &IdempotencyStoreMock{
+				ExistsFunc: func(key string) bool {
+					return false
+				},
+			},
+			storeMock: &OrderStorableMock{},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *OrderStorableMock) {
+				require.Equal(t, http.StatusUnprocessableEntity, got.StatusCode)
+				require.Len(t, storeMock.GetProductsCalls(), 0)
+				require.Len(t, storeMock.CreateOrderCalls(), 0)
+				actual := testhelper.PayloadAsType[web.ErrorResponse](t, got.Body)
+				expectedError := testhelper.MapExpectedErrorResponse(Err422Validation)
+				assert.Equal(t, expectedError, actual)
+			},
+		},
 		"error/invalid_coupon": {
 			req: func() *mapper.CreateOrderRequest {
 				def := NewDefaultOrderRequest()
@@ -234,7 +561,7 @@ func Test_API_Service_CreateOrder(t *testing.T) {
 				assert.Equal(t, expectedError, actual)
 			},
 		},
-		"error/store_failed": {
+		"error/unknown_product": {
 			req: func() *mapper.CreateOrderRequest {
 				def := NewDefaultOrderRequest()
 				return &def
@@ -252,32 +579,1000 @@ func Test_API_Service_CreateOrder(t *testing.T) {
 				CheckCouponFunc: func(ctx context.Context, coupon string) bool {
 					return true
 				},
-				CreateOrderFunc: func(ctx context.Context, order models.Order) (models.Order, error) {
-					return models.Order{}, fmt.Errorf("error")
+				GetCouponRulesFunc: func(ctx context.Context, coupon string) ([]models.CouponRule, error) {
+					return []models.CouponRule{}, nil
+				},
+				GetProductsFunc: func(ctx context.Context, ids []string) ([]models.Product, error) {
+					return defaultProducts()[:1], nil
+				},
+				UnavailableProductsFunc: func(ctx context.Context, ids []string, at time.Time) ([]string, error) {
+					return nil, nil
 				},
 			},
 			wantAssertion: func(t *testing.T, got *http.Response, storeMock *OrderStorableMock) {
-				require.Equal(t, http.StatusInternalServerError, got.StatusCode)
-				require.Len(t, storeMock.CreateOrderCalls(), 1)
+				require.Equal(t, http.StatusUnprocessableEntity, got.StatusCode)
+				require.Len(t, storeMock.CreateOrderCalls(), 0)
 				actual := testhelper.PayloadAsType[web.ErrorResponse](t, got.Body)
-				expectedError := testhelper.MapExpectedErrorResponse(web.Err500Default)
+				expectedError := testhelper.MapExpectedErrorResponse(Err422Validation)
 				assert.Equal(t, expectedError, actual)
 			},
 		},
-	}
-
-	for name, tc := range testCases {
-		t.Run(name, func(t *testing.T) {
-			log := logger.NewLogger(
-				logger.WithLevel(slog.LevelDebug),
-				logger.WithFormat(logger.HandlerJSON),
-			)
-
-			svc := NewService(tc.storeMock, tc.idemMock)
-			testServer := testhelper.SetupServer(svc, *log)
-
-			url := fmt.Sprintf("%s/api/v1/order", testServer.URL)
-			res := testhelper.SendRequest[mapper.CreateOrderRequest](t, "POST", url, tc.req(), tc.headers)
+		"error/required_modifier_missing": {
+			req: func() *mapper.CreateOrderRequest {
+				return burgerOrderRequest(testBaconID)
+			},
+			headers: map[string]string{
+				"Idempotency-Key": "key",
+			},
+			idemMock: &IdempotencyStoreMock{
+				ExistsFunc: func(key string) bool {
+					return false
+				},
+			},
+			storeMock: burgerStoreMock(),
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *OrderStorableMock) {
+				require.Equal(t, http.StatusUnprocessableEntity, got.StatusCode)
+				require.Len(t, storeMock.CreateOrderCalls(), 0)
+				actual := testhelper.PayloadAsType[web.ErrorResponse](t, got.Body)
+				expectedError := testhelper.MapExpectedErrorResponse(Err422InvalidModifiers)
+				assert.Equal(t, expectedError, actual)
+			},
+		},
+		"error/too_many_modifiers_in_group": {
+			req: func() *mapper.CreateOrderRequest {
+				return burgerOrderRequest(testRegularID, testLargeID)
+			},
+			headers: map[string]string{
+				"Idempotency-Key": "key",
+			},
+			idemMock: &IdempotencyStoreMock{
+				ExistsFunc: func(key string) bool {
+					return false
+				},
+			},
+			storeMock: burgerStoreMock(),
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *OrderStorableMock) {
+				require.Equal(t, http.StatusUnprocessableEntity, got.StatusCode)
+				require.Len(t, storeMock.CreateOrderCalls(), 0)
+				actual := testhelper.PayloadAsType[web.ErrorResponse](t, got.Body)
+				expectedError := testhelper.MapExpectedErrorResponse(Err422InvalidModifiers)
+				assert.Equal(t, expectedError, actual)
+			},
+		},
+		"error/modifier_not_offered": {
+			req: func() *mapper.CreateOrderRequest {
+				return burgerOrderRequest(testRegularID, "30000000-0000-0000-0000-000000000099")
+			},
+			headers: map[string]string{
+				"Idempotency-Key": "key",
+			},
+			idemMock: &IdempotencyStoreMock{
+				ExistsFunc: func(key string) bool {
+					return false
+				},
+			},
+			storeMock: burgerStoreMock(),
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *OrderStorableMock) {
+				require.Equal(t, http.StatusUnprocessableEntity, got.StatusCode)
+				require.Len(t, storeMock.CreateOrderCalls(), 0)
+				actual := testhelper.PayloadAsType[web.ErrorResponse](t, got.Body)
+				expectedError := testhelper.MapExpectedErrorResponse(Err422InvalidModifiers)
+				assert.Equal(t, expectedError, actual)
+			},
+		},
+		"error/duplicate_modifier": {
+			req: func() *mapper.CreateOrderRequest {
+				return burgerOrderRequest(testRegularID, testBaconID, testBaconID)
+			},
+			headers: map[string]string{
+				"Idempotency-Key": "key",
+			},
+			idemMock: &IdempotencyStoreMock{
+				ExistsFunc: func(key string) bool {
+					return false
+				},
+			},
+			storeMock: burgerStoreMock(),
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *OrderStorableMock) {
+				require.Equal(t, http.StatusUnprocessableEntity, got.StatusCode)
+				require.Len(t, storeMock.CreateOrderCalls(), 0)
+				actual := testhelper.PayloadAsType[web.ErrorResponse](t, got.Body)
+				expectedError := testhelper.MapExpectedErrorResponse(Err422InvalidModifiers)
+				assert.Equal(t, expectedError, actual)
+			},
+		},
+		"error/coupon_not_yet_active": {
+			req: func() *mapper.CreateOrderRequest {
+				def := NewDefaultOrderRequest()
+				return &def
+			},
+			headers: map[string]string{
+				"Idempotency-Key": "key",
+				"api_key":         "a-secret-key",
+			},
+			idemMock: &IdempotencyStoreMock{
+				ExistsFunc: func(key string) bool {
+					return false
+				},
+			},
+			storeMock: redeemFailingStoreMock(store.ErrCouponNotYetActive),
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *OrderStorableMock) {
+				require.Equal(t, http.StatusUnprocessableEntity, got.StatusCode)
+				require.Len(t, storeMock.CreateOrderCalls(), 1)
+				actual := testhelper.PayloadAsType[web.ErrorResponse](t, got.Body)
+				expectedError := testhelper.MapExpectedErrorResponse(Err422CouponNotYetActive)
+				assert.Equal(t, expectedError, actual)
+			},
+		},
+		"error/coupon_expired": {
+			req: func() *mapper.CreateOrderRequest {
+				def := NewDefaultOrderRequest()
+				return &def
+			},
+			headers: map[string]string{
+				"Idempotency-Key": "key",
+				"api_key":         "a-secret-key",
+			},
+			idemMock: &IdempotencyStoreMock{
+				ExistsFunc: func(key string) bool {
+					return false
+				},
+			},
+			storeMock: redeemFailingStoreMock(store.ErrCouponExpired),
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *OrderStorableMock) {
+				require.Equal(t, http.StatusUnprocessableEntity, got.StatusCode)
+				require.Len(t, storeMock.CreateOrderCalls(), 1)
+				actual := testhelper.PayloadAsType[web.ErrorResponse](t, got.Body)
+				expectedError := testhelper.MapExpectedErrorResponse(Err422CouponExpired)
+				assert.Equal(t, expectedError, actual)
+			},
+		},
+		"error/coupon_exhausted": {
+			req: func() *mapper.CreateOrderRequest {
+				def := NewDefaultOrderRequest()
+				return &def
+			},
+			headers: map[string]string{
+				"Idempotency-Key": "key",
+				"api_key":         "a-secret-key",
+			},
+			idemMock: &IdempotencyStoreMock{
+				ExistsFunc: func(key string) bool {
+					return false
+				},
+			},
+			storeMock: redeemFailingStoreMock(fmt.Errorf("redeem: %w", store.ErrCouponExhausted)),
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *OrderStorableMock) {
+				require.Equal(t, http.StatusUnprocessableEntity, got.StatusCode)
+				require.Len(t, storeMock.CreateOrderCalls(), 1)
+				actual := testhelper.PayloadAsType[web.ErrorResponse](t, got.Body)
+				expectedError := testhelper.MapExpectedErrorResponse(Err422CouponExhausted)
+				assert.Equal(t, expectedError, actual)
+			},
+		},
+		"error/out_of_stock": {
+			req: func() *mapper.CreateOrderRequest {
+				def := NewDefaultOrderRequest()
+				return &def
+			},
+			headers: map[string]string{
+				"Idempotency-Key": "key",
+			},
+			idemMock: &IdempotencyStoreMock{
+				ExistsFunc: func(key string) bool {
+					return false
+				},
+			},
+			storeMock: redeemFailingStoreMock(&store.OutOfStockError{ProductID: "00000000-0000-0000-0000-000000000002"}),
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *OrderStorableMock) {
+				require.Equal(t, http.StatusUnprocessableEntity, got.StatusCode)
+				require.Len(t, storeMock.CreateOrderCalls(), 1)
+				actual := testhelper.PayloadAsType[web.ErrorResponse](t, got.Body)
+				expectedError := testhelper.MapExpectedErrorResponse(Err422OutOfStock("00000000-0000-0000-0000-000000000002"))
+				assert.Equal(t, expectedError, actual)
+				assert.Contains(t, actual.Error.Message, "00000000-0000-0000-0000-000000000002")
+			},
+		},
+		"error/product_unavailable": {
+			req: func() *mapper.CreateOrderRequest {
+				def := NewDefaultOrderRequest()
+				return &def
+			},
+			headers: map[string]string{
+				"Idempotency-Key": "key",
+			},
+			idemMock: &IdempotencyStoreMock{
+				ExistsFunc: func(key string) bool {
+					return false
+				},
+			},
+			storeMock: &OrderStorableMock{
+				CheckCouponFunc: func(ctx context.Context, coupon string) bool {
+					return true
+				},
+				GetCouponRulesFunc: func(ctx context.Context, coupon string) ([]models.CouponRule, error) {
+					return []models.CouponRule{}, nil
+				},
+				GetProductsFunc: func(ctx context.Context, ids []string) ([]models.Product, error) {
+					return defaultProducts(), nil
+				},
+				UnavailableProductsFunc: func(ctx context.Context, ids []string, at time.Time) ([]string, error) {
+					return []string{"00000000-0000-0000-0000-000000000002"}, nil
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *OrderStorableMock) {
+				require.Equal(t, http.StatusUnprocessableEntity, got.StatusCode)
+				require.Len(t, storeMock.UnavailableProductsCalls(), 1)
+				require.Len(t, storeMock.CreateOrderCalls(), 0)
+				actual := testhelper.PayloadAsType[web.ErrorResponse](t, got.Body)
+				expectedError := testhelper.MapExpectedErrorResponse(Err422ProductUnavailable("00000000-0000-0000-0000-000000000002"))
+				assert.Equal(t, expectedError, actual)
+			},
+		},
+		"success/customer_id_from_api_key_caller": {
+			req: func() *mapper.CreateOrderRequest {
+				def := NewDefaultOrderRequest()
+				def.CustomerID = "customer-1"
+				return &def
+			},
+			headers: map[string]string{
+				"Idempotency-Key": "key",
+				"api_key":         testAPIKey,
+			},
+			idemMock: &IdempotencyStoreMock{
+				ExistsFunc: func(key string) bool {
+					return false
+				},
+				SetFunc: func(key string) {},
+			},
+			storeMock: &OrderStorableMock{
+				CheckCouponFunc: func(ctx context.Context, coupon string) bool {
+					return true
+				},
+				GetCouponRulesFunc: func(ctx context.Context, coupon string) ([]models.CouponRule, error) {
+					return []models.CouponRule{}, nil
+				},
+				GetProductsFunc: func(ctx context.Context, ids []string) ([]models.Product, error) {
+					return defaultProducts(), nil
+				},
+				UnavailableProductsFunc: func(ctx context.Context, ids []string, at time.Time) ([]string, error) {
+					return nil, nil
+				},
+				CreateOrderFunc: func(ctx context.Context, order models.Order) (models.Order, error) {
+					order.ID = "12300000-0000-0000-0000-000000000000"
+					return order, nil
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *OrderStorableMock) {
+				require.Equal(t, http.StatusCreated, got.StatusCode)
+				require.Len(t, storeMock.CreateOrderCalls(), 1)
+				assert.Equal(t, "customer-1", storeMock.CreateOrderCalls()[0].Order.CustomerID)
+			},
+		},
+		"success/customer_id_dropped_without_api_key": {
+			req: func() *mapper.CreateOrderRequest {
+				def := NewDefaultOrderRequest()
+				def.CustomerID = "customer-1"
+				return &def
+			},
+			headers: map[string]string{
+				"Idempotency-Key": "key",
+			},
+			idemMock: &IdempotencyStoreMock{
+				ExistsFunc: func(key string) bool {
+					return false
+				},
+				SetFunc: func(key string) {},
+			},
+			storeMock: &OrderStorableMock{
+				CheckCouponFunc: func(ctx context.Context, coupon string) bool {
+					return true
+				},
+				GetCouponRulesFunc: func(ctx context.Context, coupon string) ([]models.CouponRule, error) {
+					return []models.CouponRule{}, nil
+				},
+				GetProductsFunc: func(ctx context.Context, ids []string) ([]models.Product, error) {
+					return defaultProducts(), nil
+				},
+				UnavailableProductsFunc: func(ctx context.Context, ids []string, at time.Time) ([]string, error) {
+					return nil, nil
+				},
+				CreateOrderFunc: func(ctx context.Context, order models.Order) (models.Order, error) {
+					order.ID = "12300000-0000-0000-0000-000000000000"
+					return order, nil
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *OrderStorableMock) {
+				require.Equal(t, http.StatusCreated, got.StatusCode)
+				require.Len(t, storeMock.CreateOrderCalls(), 1)
+				assert.Empty(t, storeMock.CreateOrderCalls()[0].Order.CustomerID)
+			},
+		},
+		"error/store_failed": {
+			req: func() *mapper.CreateOrderRequest {
+				def := NewDefaultOrderRequest()
+				return &def
+			},
+			headers: map[string]string{
+				"Idempotency-Key": "key",
+				"api_key":         "a-secret-key",
+			},
+			idemMock: &IdempotencyStoreMock{
+				ExistsFunc: func(key string) bool {
+					return false
+				},
+			},
+			storeMock: &OrderStorableMock{
+				CheckCouponFunc: func(ctx context.Context, coupon string) bool {
+					return true
+				},
+				GetCouponRulesFunc: func(ctx context.Context, coupon string) ([]models.CouponRule, error) {
+					return []models.CouponRule{}, nil
+				},
+				GetProductsFunc: func(ctx context.Context, ids []string) ([]models.Product, error) {
+					return defaultProducts(), nil
+				},
+				UnavailableProductsFunc: func(ctx context.Context, ids []string, at time.Time) ([]string, error) {
+					return nil, nil
+				},
+				CreateOrderFunc: func(ctx context.Context, order models.Order) (models.Order, error) {
+					return models.Order{}, fmt.Errorf("error")
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *OrderStorableMock) {
+				require.Equal(t, http.StatusInternalServerError, got.StatusCode)
+				require.Len(t, storeMock.CreateOrderCalls(), 1)
+				actual := testhelper.PayloadAsType[web.ErrorResponse](t, got.Body)
+				expectedError := testhelper.MapExpectedErrorResponse(web.Err500Default)
+				assert.Equal(t, expectedError, actual)
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			log := logger.NewLogger(
+				logger.WithLevel(slog.LevelDebug),
+				logger.WithFormat(logger.HandlerJSON),
+			)
+
+			svc := NewService(tc.storeMock, tc.idemMock, testAPIKey)
+			testServer := testhelper.SetupServer(svc, *log)
+
+			url := fmt.Sprintf("%s/api/v1/order", testServer.URL)
+			res := testhelper.SendRequest[mapper.CreateOrderRequest](t, "POST", url, tc.req(), tc.headers)
+			t.Cleanup(func() {
+				if res.Body != nil {
+					require.NoError(t, res.Body.Close())
+				}
+			})
+			tc.wantAssertion(t, res, tc.storeMock)
+			testServer.Close()
+		})
+	}
+}
+
+func Test_API_Service_GetOrder(t *testing.T) {
+	t.Parallel()
+	testCases := map[string]struct {
+		orderID       string
+		headers       map[string]string
+		storeMock     *OrderStorableMock
+		wantAssertion func(t *testing.T, got *http.Response, storeMock *OrderStorableMock)
+	}{
+		"success/happy_path": {
+			orderID: "12300000-0000-0000-0000-000000000000",
+			storeMock: &OrderStorableMock{
+				GetOrderFunc: func(ctx context.Context, id string) (models.Order, error) {
+					return models.Order{
+						ID:         "12300000-0000-0000-0000-000000000000",
+						CouponCode: "FIFTYOFF",
+						Items: []models.Item{
+							{
+								ProductID: "00000000-0000-0000-0000-000000000002",
+								Quantity:  2,
+								UnitPrice: 7.99,
+								LineTotal: 15.98,
+							},
+						},
+						Products: defaultProducts()[1:],
+						Subtotal: 15.98,
+						Total:    15.98,
+						Status:   models.OrderStatusAccepted,
+						StatusHistory: []models.StatusTransition{
+							{
+								To:        models.OrderStatusPlaced,
+								CreatedAt: 1728825102000,
+							},
+							{
+								From:      models.OrderStatusPlaced,
+								To:        models.OrderStatusAccepted,
+								CreatedAt: 1728825402000,
+							},
+						},
+						CreatedAt: 1728825102000,
+					}, nil
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *OrderStorableMock) {
+				requestID := "12300000-0000-0000-0000-000000000000"
+				require.Equal(t, http.StatusOK, got.StatusCode)
+				require.Len(t, storeMock.GetOrderCalls(), 1)
+				assert.Equal(t, requestID, storeMock.GetOrderCalls()[0].ID)
+
+				want := &mapper.GetOrderResponse{
+					ID:         requestID,
+					CouponCode: "FIFTYOFF",
+					Items: []mapper.Item{
+						{
+							ProductID: "00000000-0000-0000-0000-000000000002",
+							Quantity:  2,
+						},
+					},
+					Lines: []mapper.LineItem{
+						{
+							ProductID: "00000000-0000-0000-0000-000000000002",
+							Quantity:  2,
+							UnitPrice: 7.99,
+							LineTotal: 15.98,
+						},
+					},
+					Products: []mapper.Product{
+						{
+							ID:       "00000000-0000-0000-0000-000000000002",
+							Name:     "Bacon",
+							Category: "Breakfast",
+							Price:    7.99,
+						},
+					},
+					Subtotal: 15.98,
+					Total:    15.98,
+					Status:   "accepted",
+					StatusHistory: []mapper.StatusTransition{
+						{
+							To:        "placed",
+							CreatedAt: 1728825102000,
+						},
+						{
+							From:      "placed",
+							To:        "accepted",
+							CreatedAt: 1728825402000,
+						},
+					},
+					CreatedAt: 1728825102000,
+				}
+
+				actual := testhelper.PayloadAsType[mapper.GetOrderResponse](t, got.Body)
+				assert.Equal(t, want, &actual)
+			},
+		},
+		"success/customer_hidden_without_api_key": {
+			orderID: "12300000-0000-0000-0000-000000000000",
+			storeMock: &OrderStorableMock{
+				GetOrderFunc: func(ctx context.Context, id string) (models.Order, error) {
+					return models.Order{ID: id, CustomerID: "customer-1", Status: models.OrderStatusPlaced}, nil
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *OrderStorableMock) {
+				require.Equal(t, http.StatusOK, got.StatusCode)
+				actual := testhelper.PayloadAsType[mapper.GetOrderResponse](t, got.Body)
+				assert.Empty(t, actual.CustomerID)
+			},
+		},
+		"success/customer_shown_with_api_key": {
+			orderID: "12300000-0000-0000-0000-000000000000",
+			headers: authHeaders,
+			storeMock: &OrderStorableMock{
+				GetOrderFunc: func(ctx context.Context, id string) (models.Order, error) {
+					return models.Order{ID: id, CustomerID: "customer-1", Status: models.OrderStatusPlaced}, nil
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *OrderStorableMock) {
+				require.Equal(t, http.StatusOK, got.StatusCode)
+				actual := testhelper.PayloadAsType[mapper.GetOrderResponse](t, got.Body)
+				assert.Equal(t, "customer-1", actual.CustomerID)
+			},
+		},
+		"error/invalid_order_id": {
+			orderID:   "invalid-uuid",
+			storeMock: &OrderStorableMock{},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *OrderStorableMock) {
+				require.Equal(t, http.StatusBadRequest, got.StatusCode)
+				require.Len(t, storeMock.GetOrderCalls(), 0)
+
+				actual := testhelper.PayloadAsType[web.ErrorResponse](t, got.Body)
+				expectedError := testhelper.MapExpectedErrorResponse(Err400InvalidOrderID)
+				assert.Equal(t, expectedError, actual)
+			},
+		},
+		"error/store_not_found": {
+			orderID: "12300000-0000-0000-0000-000000000000",
+			storeMock: &OrderStorableMock{
+				GetOrderFunc: func(ctx context.Context, id string) (models.Order, error) {
+					return models.Order{}, fmt.Errorf("error")
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *OrderStorableMock) {
+				require.Equal(t, http.StatusNotFound, got.StatusCode)
+				require.Len(t, storeMock.GetOrderCalls(), 1)
+
+				actual := testhelper.PayloadAsType[web.ErrorResponse](t, got.Body)
+				expectedError := testhelper.MapExpectedErrorResponse(Err404OrderNotFound)
+				assert.Equal(t, expectedError, actual)
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			log := logger.NewLogger(
+				logger.WithLevel(slog.LevelDebug),
+				logger.WithFormat(logger.HandlerJSON),
+			)
+
+			svc := NewService(tc.storeMock, &IdempotencyStoreMock{}, testAPIKey)
+			testServer := testhelper.SetupServer(svc, *log)
+
+			url := fmt.Sprintf("%s/api/v1/order/%s", testServer.URL, tc.orderID)
+			res := testhelper.SendRequest[any](t, "GET", url, nil, tc.headers)
+			t.Cleanup(func() {
+				if res.Body != nil {
+					require.NoError(t, res.Body.Close())
+				}
+			})
+			tc.wantAssertion(t, res, tc.storeMock)
+			testServer.Close()
+		})
+	}
+}
+
+func Test_API_Service_ListOrders(t *testing.T) {
+	t.Parallel()
+	testCases := map[string]struct {
+		query         string
+		headers       map[string]string
+		storeMock     *OrderStorableMock
+		wantAssertion func(t *testing.T, got *http.Response, storeMock *OrderStorableMock)
+	}{
+		"success/happy_path": {
+			headers: authHeaders,
+			query:   "?created_from=1728825102000&created_to=1728832202000&coupon_code=FIFTYOFF&sort=created_at&limit=1&cursor=abc",
+			storeMock: &OrderStorableMock{
+				ListOrdersFunc: func(ctx context.Context, filter models.OrderFilter) (models.OrderPage, error) {
+					return models.OrderPage{
+						Orders: []models.Order{
+							{
+								ID:         "10000000-0000-0000-0000-000000000001",
+								CouponCode: "FIFTYOFF",
+								Subtotal:   24.97,
+								Total:      24.97,
+								CreatedAt:  1728825102000,
+							},
+						},
+						NextCursor: "next",
+					}, nil
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *OrderStorableMock) {
+				require.Equal(t, http.StatusOK, got.StatusCode)
+				require.Len(t, storeMock.ListOrdersCalls(), 1)
+
+				expectedFilter := models.OrderFilter{
+					CreatedFrom: 1728825102000,
+					CreatedTo:   1728832202000,
+					CouponCode:  "FIFTYOFF",
+					Ascending:   true,
+					Cursor:      "abc",
+					Limit:       1,
+				}
+				assert.Equal(t, expectedFilter, storeMock.ListOrdersCalls()[0].Filter)
+
+				want := &mapper.ListOrdersResponse{
+					Orders: []mapper.OrderSummary{
+						{
+							ID:         "10000000-0000-0000-0000-000000000001",
+							CouponCode: "FIFTYOFF",
+							Subtotal:   24.97,
+							Total:      24.97,
+							CreatedAt:  1728825102000,
+						},
+					},
+					NextCursor: "next",
+				}
+
+				actual := testhelper.PayloadAsType[mapper.ListOrdersResponse](t, got.Body)
+				assert.Equal(t, want, &actual)
+			},
+		},
+		"success/defaults": {
+			headers: authHeaders,
+			query:   "",
+			storeMock: &OrderStorableMock{
+				ListOrdersFunc: func(ctx context.Context, filter models.OrderFilter) (models.OrderPage, error) {
+					return models.OrderPage{Orders: []models.Order{}}, nil
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *OrderStorableMock) {
+				require.Equal(t, http.StatusOK, got.StatusCode)
+				require.Len(t, storeMock.ListOrdersCalls(), 1)
+				assert.Equal(t, models.OrderFilter{Limit: mapper.DefaultListLimit}, storeMock.ListOrdersCalls()[0].Filter)
+			},
+		},
+		"error/invalid_limit": {
+			headers:   authHeaders,
+			query:     "?limit=abc",
+			storeMock: &OrderStorableMock{},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *OrderStorableMock) {
+				require.Equal(t, http.StatusBadRequest, got.StatusCode)
+				require.Len(t, storeMock.ListOrdersCalls(), 0)
+
+				actual := testhelper.PayloadAsType[web.ErrorResponse](t, got.Body)
+				expectedError := testhelper.MapExpectedErrorResponse(Err400InvalidQuery)
+				assert.Equal(t, expectedError, actual)
+			},
+		},
+		"error/invalid_sort": {
+			headers:   authHeaders,
+			query:     "?sort=price",
+			storeMock: &OrderStorableMock{},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *OrderStorableMock) {
+				require.Equal(t, http.StatusBadRequest, got.StatusCode)
+				require.Len(t, storeMock.ListOrdersCalls(), 0)
+			},
+		},
+		"error/invalid_cursor": {
+			headers: authHeaders,
+			query:   "?cursor=not-a-cursor",
+			storeMock: &OrderStorableMock{
+				ListOrdersFunc: func(ctx context.Context, filter models.OrderFilter) (models.OrderPage, error) {
+					return models.OrderPage{}, store.ErrInvalidCursor
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *OrderStorableMock) {
+				require.Equal(t, http.StatusBadRequest, got.StatusCode)
+				require.Len(t, storeMock.ListOrdersCalls(), 1)
+
+				actual := testhelper.PayloadAsType[web.ErrorResponse](t, got.Body)
+				expectedError := testhelper.MapExpectedErrorResponse(Err400InvalidQuery)
+				assert.Equal(t, expectedError, actual)
+			},
+		},
+		"error/store_failed": {
+			headers: authHeaders,
+			query:   "",
+			storeMock: &OrderStorableMock{
+				ListOrdersFunc: func(ctx context.Context, filter models.OrderFilter) (models.OrderPage, error) {
+					return models.OrderPage{}, fmt.Errorf("error")
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *OrderStorableMock) {
+				require.Equal(t, http.StatusInternalServerError, got.StatusCode)
+
+				actual := testhelper.PayloadAsType[web.ErrorResponse](t, got.Body)
+				expectedError := testhelper.MapExpectedErrorResponse(web.Err500Default)
+				assert.Equal(t, expectedError, actual)
+			},
+		},
+		"error/missing_api_key": {
+			storeMock: &OrderStorableMock{},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *OrderStorableMock) {
+				require.Equal(t, http.StatusUnauthorized, got.StatusCode)
+				require.Len(t, storeMock.ListOrdersCalls(), 0)
+
+				actual := testhelper.PayloadAsType[web.ErrorResponse](t, got.Body)
+				expectedError := testhelper.MapExpectedErrorResponse(web.Err401Default)
+				assert.Equal(t, expectedError, actual)
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			log := logger.NewLogger(
+				logger.WithLevel(slog.LevelDebug),
+				logger.WithFormat(logger.HandlerJSON),
+			)
+
+			svc := NewService(tc.storeMock, &IdempotencyStoreMock{}, testAPIKey)
+			testServer := testhelper.SetupServer(svc, *log)
+
+			url := fmt.Sprintf("%s/api/v1/order%s", testServer.URL, tc.query)
+			res := testhelper.SendRequest[any](t, "GET", url, nil, tc.headers)
+			t.Cleanup(func() {
+				if res.Body != nil {
+					require.NoError(t, res.Body.Close())
+				}
+			})
+			tc.wantAssertion(t, res, tc.storeMock)
+			testServer.Close()
+		})
+	}
+}
+
+func Test_API_Service_UpdateOrderStatus(t *testing.T) {
+	t.Parallel()
+	orderID := "12300000-0000-0000-0000-000000000000"
+	getOrder := func(status models.OrderStatus) func(ctx context.Context, id string) (models.Order, error) {
+		return func(ctx context.Context, id string) (models.Order, error) {
+			return models.Order{ID: id, Status: status}, nil
+		}
+	}
+
+	testCases := map[string]struct {
+		orderID       string
+		headers       map[string]string
+		req           *mapper.UpdateOrderStatusRequest
+		storeMock     *OrderStorableMock
+		wantAssertion func(t *testing.T, got *http.Response, storeMock *OrderStorableMock)
+	}{
+		"success/happy_path": {
+			orderID: orderID,
+			headers: authHeaders,
+			req: &mapper.UpdateOrderStatusRequest{
+				Status: "accepted",
+				Reason: "kitchen open",
+			},
+			storeMock: &OrderStorableMock{
+				GetOrderFunc: getOrder(models.OrderStatusPlaced),
+				UpdateOrderStatusFunc: func(ctx context.Context, id string, from, to models.OrderStatus, reason string) (models.Order, error) {
+					return models.Order{ID: id, Status: to}, nil
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *OrderStorableMock) {
+				require.Equal(t, http.StatusOK, got.StatusCode)
+				require.Len(t, storeMock.UpdateOrderStatusCalls(), 1)
+				call := storeMock.UpdateOrderStatusCalls()[0]
+				assert.Equal(t, orderID, call.ID)
+				assert.Equal(t, models.OrderStatusPlaced, call.From)
+				assert.Equal(t, models.OrderStatusAccepted, call.To)
+				assert.Equal(t, "kitchen open", call.Reason)
+
+				actual := testhelper.PayloadAsType[mapper.GetOrderResponse](t, got.Body)
+				assert.Equal(t, "accepted", actual.Status)
+			},
+		},
+		"error/invalid_order_id": {
+			orderID:   "invalid-uuid",
+			headers:   authHeaders,
+			req:       &mapper.UpdateOrderStatusRequest{Status: "accepted"},
+			storeMock: &OrderStorableMock{},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *OrderStorableMock) {
+				require.Equal(t, http.StatusBadRequest, got.StatusCode)
+				require.Len(t, storeMock.GetOrderCalls(), 0)
+
+				actual := testhelper.PayloadAsType[web.ErrorResponse](t, got.Body)
+				expectedError := testhelper.MapExpectedErrorResponse(Err400InvalidOrderID)
+				assert.Equal(t, expectedError, actual)
+			},
+		},
+		"error/unknown_status": {
+			orderID:   orderID,
+			headers:   authHeaders,
+			req:       &mapper.UpdateOrderStatusRequest{Status: "eaten"},
+			storeMock: &OrderStorableMock{},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *OrderStorableMock) {
+				require.Equal(t, http.StatusUnprocessableEntity, got.StatusCode)
+				require.Len(t, storeMock.GetOrderCalls(), 0)
+
+				actual := testhelper.PayloadAsType[web.ErrorResponse](t, got.Body)
+				expectedError := testhelper.MapExpectedErrorResponse(Err422Validation)
+				assert.Equal(t, expectedError, actual)
+			},
+		},
+		"error/order_not_found": {
+			orderID: orderID,
+			headers: authHeaders,
+			req:     &mapper.UpdateOrderStatusRequest{Status: "accepted"},
+			storeMock: &OrderStorableMock{
+				GetOrderFunc: func(ctx context.Context, id string) (models.Order, error) {
+					return models.Order{}, fmt.Errorf("error")
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *OrderStorableMock) {
+				require.Equal(t, http.StatusNotFound, got.StatusCode)
+				require.Len(t, storeMock.UpdateOrderStatusCalls(), 0)
+
+				actual := testhelper.PayloadAsType[web.ErrorResponse](t, got.Body)
+				expectedError := testhelper.MapExpectedErrorResponse(Err404OrderNotFound)
+				assert.Equal(t, expectedError, actual)
+			},
+		},
+		"error/illegal_transition": {
+			orderID: orderID,
+			headers: authHeaders,
+			req:     &mapper.UpdateOrderStatusRequest{Status: "ready"},
+			storeMock: &OrderStorableMock{
+				GetOrderFunc: getOrder(models.OrderStatusPlaced),
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *OrderStorableMock) {
+				require.Equal(t, http.StatusConflict, got.StatusCode)
+				require.Len(t, storeMock.UpdateOrderStatusCalls(), 0)
+
+				actual := testhelper.PayloadAsType[web.ErrorResponse](t, got.Body)
+				expectedError := testhelper.MapExpectedErrorResponse(Err409IllegalStatusTransition)
+				assert.Equal(t, expectedError, actual)
+			},
+		},
+		"error/terminal_status": {
+			orderID: orderID,
+			headers: authHeaders,
+			req:     &mapper.UpdateOrderStatusRequest{Status: "placed"},
+			storeMock: &OrderStorableMock{
+				GetOrderFunc: getOrder(models.OrderStatusCompleted),
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *OrderStorableMock) {
+				require.Equal(t, http.StatusConflict, got.StatusCode)
+				require.Len(t, storeMock.UpdateOrderStatusCalls(), 0)
+			},
+		},
+		"error/concurrent_update": {
+			orderID: orderID,
+			headers: authHeaders,
+			req:     &mapper.UpdateOrderStatusRequest{Status: "accepted"},
+			storeMock: &OrderStorableMock{
+				GetOrderFunc: getOrder(models.OrderStatusPlaced),
+				UpdateOrderStatusFunc: func(ctx context.Context, id string, from, to models.OrderStatus, reason string) (models.Order, error) {
+					return models.Order{}, store.ErrStatusConflict
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *OrderStorableMock) {
+				require.Equal(t, http.StatusConflict, got.StatusCode)
+				require.Len(t, storeMock.UpdateOrderStatusCalls(), 1)
+
+				actual := testhelper.PayloadAsType[web.ErrorResponse](t, got.Body)
+				expectedError := testhelper.MapExpectedErrorResponse(Err409IllegalStatusTransition)
+				assert.Equal(t, expectedError, actual)
+			},
+		},
+		"error/missing_api_key": {
+			orderID: orderID,
+			req: &mapper.UpdateOrderStatusRequest{
+				Status: "completed",
+			},
+			storeMock: &OrderStorableMock{},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *OrderStorableMock) {
+				require.Equal(t, http.StatusUnauthorized, got.StatusCode)
+				require.Len(t, storeMock.GetOrderCalls(), 0)
+				require.Len(t, storeMock.UpdateOrderStatusCalls(), 0)
+
+				actual := testhelper.PayloadAsType[web.ErrorResponse](t, got.Body)
+				expectedError := testhelper.MapExpectedErrorResponse(web.Err401Default)
+				assert.Equal(t, expectedError, actual)
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			log := logger.NewLogger(
+				logger.WithLevel(slog.LevelDebug),
+				logger.WithFormat(logger.HandlerJSON),
+			)
+
+			svc := NewService(tc.storeMock, &IdempotencyStoreMock{}, testAPIKey)
+			testServer := testhelper.SetupServer(svc, *log)
+
+			url := fmt.Sprintf("%s/api/v1/order/%s/status", testServer.URL, tc.orderID)
+			res := testhelper.SendRequest[mapper.UpdateOrderStatusRequest](t, "POST", url, tc.req, tc.headers)
+			t.Cleanup(func() {
+				if res.Body != nil {
+					require.NoError(t, res.Body.Close())
+				}
+			})
+			tc.wantAssertion(t, res, tc.storeMock)
+			testServer.Close()
+		})
+	}
+}
+
+func Test_API_Service_CancelOrder(t *testing.T) {
+	t.Parallel()
+	orderID := "12300000-0000-0000-0000-000000000000"
+	getOrder := func(status models.OrderStatus) func(ctx context.Context, id string) (models.Order, error) {
+		return func(ctx context.Context, id string) (models.Order, error) {
+			return models.Order{ID: id, Status: status}, nil
+		}
+	}
+
+	testCases := map[string]struct {
+		headers       map[string]string
+		req           *mapper.CancelOrderRequest
+		storeMock     *OrderStorableMock
+		wantAssertion func(t *testing.T, got *http.Response, storeMock *OrderStorableMock)
+	}{
+		"success/happy_path": {
+			headers: authHeaders,
+			req:     &mapper.CancelOrderRequest{Reason: "changed my mind"},
+			storeMock: &OrderStorableMock{
+				GetOrderFunc: getOrder(models.OrderStatusAccepted),
+				UpdateOrderStatusFunc: func(ctx context.Context, id string, from, to models.OrderStatus, reason string) (models.Order, error) {
+					return models.Order{ID: id, Status: to}, nil
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *OrderStorableMock) {
+				require.Equal(t, http.StatusOK, got.StatusCode)
+				require.Len(t, storeMock.UpdateOrderStatusCalls(), 1)
+				call := storeMock.UpdateOrderStatusCalls()[0]
+				assert.Equal(t, models.OrderStatusAccepted, call.From)
+				assert.Equal(t, models.OrderStatusCancelled, call.To)
+				assert.Equal(t, "changed my mind", call.Reason)
+
+				actual := testhelper.PayloadAsType[mapper.GetOrderResponse](t, got.Body)
+				assert.Equal(t, "cancelled", actual.Status)
+			},
+		},
+		"error/missing_reason": {
+			headers:   authHeaders,
+			req:       &mapper.CancelOrderRequest{},
+			storeMock: &OrderStorableMock{},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *OrderStorableMock) {
+				require.Equal(t, http.StatusUnprocessableEntity, got.StatusCode)
+				require.Len(t, storeMock.GetOrderCalls(), 0)
+
+				actual := testhelper.PayloadAsType[web.ErrorResponse](t, got.Body)
+				expectedError := testhelper.MapExpectedErrorResponse(Err422Validation)
+				assert.Equal(t, expectedError, actual)
+			},
+		},
+		"error/already_preparing": {
+			headers: authHeaders,
+			req:     &mapper.CancelOrderRequest{Reason: "too slow"},
+			storeMock: &OrderStorableMock{
+				GetOrderFunc: getOrder(models.OrderStatusPreparing),
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *OrderStorableMock) {
+				require.Equal(t, http.StatusConflict, got.StatusCode)
+				require.Len(t, storeMock.UpdateOrderStatusCalls(), 0)
+
+				actual := testhelper.PayloadAsType[web.ErrorResponse](t, got.Body)
+				expectedError := testhelper.MapExpectedErrorResponse(Err409OrderNotCancellable)
+				assert.Equal(t, expectedError, actual)
+			},
+		},
+		"error/concurrent_update": {
+			headers: authHeaders,
+			req:     &mapper.CancelOrderRequest{Reason: "changed my mind"},
+			storeMock: &OrderStorableMock{
+				GetOrderFunc: getOrder(models.OrderStatusPlaced),
+				UpdateOrderStatusFunc: func(ctx context.Context, id string, from, to models.OrderStatus, reason string) (models.Order, error) {
+					return models.Order{}, store.ErrStatusConflict
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *OrderStorableMock) {
+				require.Equal(t, http.StatusConflict, got.StatusCode)
+
+				actual := testhelper.PayloadAsType[web.ErrorResponse](t, got.Body)
+				expectedError := testhelper.MapExpectedErrorResponse(Err409OrderNotCancellable)
+				assert.Equal(t, expectedError, actual)
+			},
+		},
+		"error/missing_api_key": {
+			req:       &mapper.CancelOrderRequest{Reason: "changed my mind"},
+			storeMock: &OrderStorableMock{},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *OrderStorableMock) {
+				require.Equal(t, http.StatusUnauthorized, got.StatusCode)
+				require.Len(t, storeMock.GetOrderCalls(), 0)
+				require.Len(t, storeMock.UpdateOrderStatusCalls(), 0)
+
+				actual := testhelper.PayloadAsType[web.ErrorResponse](t, got.Body)
+				expectedError := testhelper.MapExpectedErrorResponse(web.Err401Default)
+				assert.Equal(t, expectedError, actual)
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			log := logger.NewLogger(
+				logger.WithLevel(slog.LevelDebug),
+				logger.WithFormat(logger.HandlerJSON),
+			)
+
+			svc := NewService(tc.storeMock, &IdempotencyStoreMock{}, testAPIKey)
+			testServer := testhelper.SetupServer(svc, *log)
+
+			url := fmt.Sprintf("%s/api/v1/order/%s/cancel", testServer.URL, orderID)
+			res := testhelper.SendRequest[mapper.CancelOrderRequest](t, "POST", url, tc.req, tc.headers)
 			t.Cleanup(func() {
 				if res.Body != nil {
 					require.NoError(t, res.Body.Close())
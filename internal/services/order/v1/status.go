@@ -0,0 +1,32 @@
+package v1
+
+import "github.com/sgrumley/kart-challenge/pkg/models"
+
+// orderTransitions is the order lifecycle state machine, each status maps to the
+// statuses it may move to next. Completed, cancelled and rejected are terminal.
+var orderTransitions = map[models.OrderStatus][]models.OrderStatus{
+	models.OrderStatusPlaced: {
+		models.OrderStatusAccepted,
+		models.OrderStatusRejected,
+		models.OrderStatusCancelled,
+	},
+	models.OrderStatusAccepted: {
+		models.OrderStatusPreparing,
+		models.OrderStatusCancelled,
+	},
+	models.OrderStatusPreparing: {
+		models.OrderStatusReady,
+	},
+	models.OrderStatusReady: {
+		models.OrderStatusCompleted,
+	},
+}
+
+func CanTransition(from, to models.OrderStatus) bool {
+	for _, next := range orderTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
@@ -2,11 +2,27 @@ package v1
 
 import (
 	"github.com/go-chi/chi/v5"
+
+	"github.com/sgrumley/kart-challenge/pkg/middleware"
 )
 
 func (s *ProductService) GetRoutes(r chi.Router) {
 	r.Group(func(r chi.Router) {
+		r.Get("/product/search", s.SearchProducts)
 		r.Get("/product/{product_id}", s.GetProduct)
 		r.Get("/product", s.ListProducts)
 	})
+
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.RequireAPIKey(s.apiKey))
+
+		r.Post("/product", s.CreateProduct)
+		r.Put("/product/{product_id}", s.UpdateProduct)
+		r.Patch("/product/{product_id}", s.PatchProduct)
+		r.Delete("/product/{product_id}", s.DeleteProduct)
+
+		r.Put("/product/{product_id}/stock", s.SetProductStock)
+		r.Post("/product/{product_id}/stock/adjustments", s.AdjustProductStock)
+		r.Delete("/product/{product_id}/stock", s.UntrackProductStock)
+	})
 }
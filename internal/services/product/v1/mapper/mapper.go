@@ -1,6 +1,22 @@
 package mapper
 
-import "github.com/sgrumley/kart-challenge/pkg/models"
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/sgrumley/kart-challenge/pkg/models"
+)
+
+const (
+	DefaultListLimit   = 20
+	DefaultSearchLimit = 20
+)
+
+// listingParams are the query parameters that switch ListProducts from the
+// plain array to the paginated envelope.
+var listingParams = []string{"category", "min_price", "max_price", "q", "sort", "cursor", "limit"}
 
 type GetProductResponse struct {
 	ID        string  `json:"id"`
@@ -11,18 +27,63 @@ type GetProductResponse struct {
 
 func GetProductToResponse(product *models.Product) *Product {
 	return &Product{
-		ID:       product.ID,
-		Name:     product.Name,
-		Category: product.Category,
-		Price:    product.Price,
+		ID:             product.ID,
+		Name:           product.Name,
+		Category:       product.Category,
+		Price:          product.Price,
+		Stock:          product.Stock,
+		ModifierGroups: ModifierGroupsToResponse(product.ModifierGroups),
 	}
 }
 
 type Product struct {
-	ID       string  `json:"id"`
-	Name     string  `json:"name"`
-	Category string  `json:"catergory"`
-	Price    float32 `json:"price"`
+	ID             string          `json:"id"`
+	Name           string          `json:"name"`
+	Category       string          `json:"catergory"`
+	Price          float32         `json:"price"`
+	Stock          *int            `json:"stock,omitempty"`
+	ModifierGroups []ModifierGroup `json:"modifier_groups,omitempty"`
+}
+
+// ModifierGroup asks for between MinSelect and MaxSelect of its Modifiers to be
+// picked when ordering the product.
+type ModifierGroup struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	MinSelect int        `json:"min_select"`
+	MaxSelect int        `json:"max_select"`
+	Modifiers []Modifier `json:"modifiers"`
+}
+
+type Modifier struct {
+	ID         string  `json:"id"`
+	Name       string  `json:"name"`
+	PriceDelta float32 `json:"price_delta"`
+}
+
+func ModifierGroupsToResponse(groups []models.ModifierGroup) []ModifierGroup {
+	if len(groups) == 0 {
+		return nil
+	}
+
+	res := make([]ModifierGroup, len(groups))
+	for i, g := range groups {
+		res[i] = ModifierGroup{
+			ID:        g.ID,
+			Name:      g.Name,
+			MinSelect: g.MinSelect,
+			MaxSelect: g.MaxSelect,
+			Modifiers: make([]Modifier, len(g.Modifiers)),
+		}
+		for j, m := range g.Modifiers {
+			res[i].Modifiers[j] = Modifier{
+				ID:         m.ID,
+				Name:       m.Name,
+				PriceDelta: m.PriceDelta,
+			}
+		}
+	}
+	return res
 }
 
 type ListProductsResponse []Product
@@ -31,11 +92,247 @@ func ListProductsToResponse(products []models.Product) ListProductsResponse {
 	res := make(ListProductsResponse, len(products))
 	for i, p := range products {
 		res[i] = Product{
-			ID:       p.ID,
-			Name:     p.Name,
-			Category: p.Category,
-			Price:    p.Price,
+			ID:             p.ID,
+			Name:           p.Name,
+			Category:       p.Category,
+			Price:          p.Price,
+			Stock:          p.Stock,
+			ModifierGroups: ModifierGroupsToResponse(p.ModifierGroups),
 		}
 	}
 	return res
 }
+
+// CreateProductRequest files the product under CategoryID, or under the
+// category named Category when no id is given.
+type CreateProductRequest struct {
+	Name       string  `json:"name" validate:"required,max=255"`
+	Category   string  `json:"catergory" validate:"omitempty,max=255"`
+	CategoryID string  `json:"category_id" validate:"omitempty,uuid"`
+	Price      float32 `json:"price" validate:"gt=0"`
+}
+
+// UpdateProductRequest replaces every field of a product, an omitted category
+// clears it.
+type UpdateProductRequest CreateProductRequest
+
+// SetProductStockRequest sets the units in stock, untracking the stock is a
+// separate request so a missing stock is never taken to mean untracked.
+type SetProductStockRequest struct {
+	Stock *int `json:"stock" validate:"required,min=0,max=1000000"`
+}
+
+// AdjustProductStockRequest adds Delta units to the stock, a negative Delta
+// takes them away.
+type AdjustProductStockRequest struct {
+	Delta int `json:"delta" validate:"required,min=-1000000,max=1000000"`
+}
+
+// PatchProductRequest only changes the fields that are given.
+type PatchProductRequest struct {
+	Name       *string  `json:"name" validate:"omitempty,min=1,max=255"`
+	Category   *string  `json:"catergory" validate:"omitempty,max=255"`
+	CategoryID *string  `json:"category_id" validate:"omitempty,uuid"`
+	Price      *float32 `json:"price" validate:"omitempty,gt=0"`
+}
+
+func (r PatchProductRequest) Empty() bool {
+	return r.Name == nil && r.Category == nil && r.CategoryID == nil && r.Price == nil
+}
+
+func CreateProductFromRequest(req CreateProductRequest) models.Product {
+	return models.Product{
+		Name:       req.Name,
+		Category:   req.Category,
+		CategoryID: req.CategoryID,
+		Price:      req.Price,
+	}
+}
+
+func UpdateProductFromRequest(id string, req UpdateProductRequest) models.Product {
+	return models.Product{
+		ID:         id,
+		Name:       req.Name,
+		Category:   req.Category,
+		CategoryID: req.CategoryID,
+		Price:      req.Price,
+	}
+}
+
+func PatchProductFromRequest(req PatchProductRequest) models.ProductPatch {
+	return models.ProductPatch{
+		Name:       req.Name,
+		Category:   req.Category,
+		CategoryID: req.CategoryID,
+		Price:      req.Price,
+	}
+}
+
+type ListProductsRequest struct {
+	Category string  `validate:"omitempty,max=255"`
+	MinPrice float32 `validate:"omitempty,min=0"`
+	MaxPrice float32 `validate:"omitempty,min=0,gtefield=MinPrice"`
+	Query    string  `validate:"omitempty,max=255"`
+	Sort     string  `validate:"omitempty,oneof=name -name price -price created_at -created_at"`
+	Cursor   string
+	Limit    int `validate:"min=1,max=100"`
+}
+
+// IsPagedListing reports whether any listing parameter was given. Without
+// them the full catalogue is returned as a plain array for existing clients.
+func IsPagedListing(query url.Values) bool {
+	for _, p := range listingParams {
+		if query.Has(p) {
+			return true
+		}
+	}
+	return false
+}
+
+func ListProductsRequestFromQuery(query url.Values) (ListProductsRequest, error) {
+	req := ListProductsRequest{
+		Category: query.Get("category"),
+		Query:    query.Get("q"),
+		Sort:     query.Get("sort"),
+		Cursor:   query.Get("cursor"),
+		Limit:    DefaultListLimit,
+	}
+
+	if v := query.Get("min_price"); v != "" {
+		price, err := strconv.ParseFloat(v, 32)
+		if err != nil {
+			return ListProductsRequest{}, fmt.Errorf("min_price: %w", err)
+		}
+		req.MinPrice = float32(price)
+	}
+	if v := query.Get("max_price"); v != "" {
+		price, err := strconv.ParseFloat(v, 32)
+		if err != nil {
+			return ListProductsRequest{}, fmt.Errorf("max_price: %w", err)
+		}
+		req.MaxPrice = float32(price)
+	}
+	if v := query.Get("limit"); v != "" {
+		var err error
+		if req.Limit, err = strconv.Atoi(v); err != nil {
+			return ListProductsRequest{}, fmt.Errorf("limit: %w", err)
+		}
+	}
+
+	return req, nil
+}
+
+func ProductFilterFromRequest(req ListProductsRequest) models.ProductFilter {
+	return models.ProductFilter{
+		Category: req.Category,
+		MinPrice: req.MinPrice,
+		MaxPrice: req.MaxPrice,
+		Query:    req.Query,
+		Sort:     models.ProductSort(req.Sort),
+		Cursor:   req.Cursor,
+		Limit:    req.Limit,
+	}
+}
+
+type Pagination struct {
+	Limit      int    `json:"limit"`
+	Count      int    `json:"count"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+type ListProductsPageResponse struct {
+	Products   ListProductsResponse `json:"products"`
+	Pagination Pagination           `json:"pagination"`
+}
+
+func ListProductsPageToResponse(page models.ProductPage, limit int) ListProductsPageResponse {
+	return ListProductsPageResponse{
+		Products: ListProductsToResponse(page.Products),
+		Pagination: Pagination{
+			Limit:      limit,
+			Count:      len(page.Products),
+			NextCursor: page.NextCursor,
+		},
+	}
+}
+
+// IncludeUnavailableFromQuery reads the include_unavailable flag, products
+// outside their availability windows are only listed when it is set.
+func IncludeUnavailableFromQuery(query url.Values) (bool, error) {
+	v := query.Get("include_unavailable")
+	if v == "" {
+		return false, nil
+	}
+	include, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf("include_unavailable: %w", err)
+	}
+	return include, nil
+}
+
+type SearchProductsRequest struct {
+	Query string `validate:"required,max=100"`
+	Limit int    `validate:"min=1,max=50"`
+}
+
+// SearchProductsRequestFromQuery trims q so a blank search fails validation.
+func SearchProductsRequestFromQuery(query url.Values) (SearchProductsRequest, error) {
+	req := SearchProductsRequest{
+		Query: strings.TrimSpace(query.Get("q")),
+		Limit: DefaultSearchLimit,
+	}
+
+	if v := query.Get("limit"); v != "" {
+		var err error
+		if req.Limit, err = strconv.Atoi(v); err != nil {
+			return SearchProductsRequest{}, fmt.Errorf("limit: %w", err)
+		}
+	}
+
+	return req, nil
+}
+
+const (
+	MatchFullText = "full_text"
+	MatchFuzzy    = "fuzzy"
+)
+
+type ProductMatch struct {
+	Product
+	Match     string  `json:"match"`
+	Score     float32 `json:"score"`
+	Highlight string  `json:"highlight"`
+}
+
+type SearchProductsResponse struct {
+	Query   string         `json:"query"`
+	Results []ProductMatch `json:"results"`
+}
+
+func SearchProductsToResponse(query string, matches []models.ProductMatch) SearchProductsResponse {
+	res := SearchProductsResponse{
+		Query:   query,
+		Results: make([]ProductMatch, len(matches)),
+	}
+
+	for i, m := range matches {
+		match := MatchFuzzy
+		if m.FullText {
+			match = MatchFullText
+		}
+		res.Results[i] = ProductMatch{
+			Product: Product{
+				ID:       m.Product.ID,
+				Name:     m.Product.Name,
+				Category: m.Product.Category,
+				Price:    m.Product.Price,
+				Stock:    m.Product.Stock,
+			},
+			Match:     match,
+			Score:     m.Score,
+			Highlight: m.Headline,
+		}
+	}
+
+	return res
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"github.com/sgrumley/kart-challenge/pkg/models"
 	"sync"
+	"time"
 )
 
 // Ensure, that ProductStorableMock does implement ProductStorable.
@@ -19,12 +20,36 @@ var _ ProductStorable = &ProductStorableMock{}
 //
 //		// make and configure a mocked ProductStorable
 //		mockedProductStorable := &ProductStorableMock{
+//			AdjustProductStockFunc: func(ctx context.Context, id string, delta int) (models.Product, error) {
+//				panic("mock out the AdjustProductStock method")
+//			},
+//			CreateProductFunc: func(ctx context.Context, product models.Product) (models.Product, error) {
+//				panic("mock out the CreateProduct method")
+//			},
+//			DeleteProductFunc: func(ctx context.Context, id string) error {
+//				panic("mock out the DeleteProduct method")
+//			},
 //			GetProductFunc: func(ctx context.Context, id string) (models.Product, error) {
 //				panic("mock out the GetProduct method")
 //			},
-//			ListProductsFunc: func(ctx context.Context) ([]models.Product, error) {
+//			ListProductsFunc: func(ctx context.Context, availableAt time.Time) ([]models.Product, error) {
 //				panic("mock out the ListProducts method")
 //			},
+//			ListProductsPageFunc: func(ctx context.Context, filter models.ProductFilter) (models.ProductPage, error) {
+//				panic("mock out the ListProductsPage method")
+//			},
+//			PatchProductFunc: func(ctx context.Context, id string, patch models.ProductPatch) (models.Product, error) {
+//				panic("mock out the PatchProduct method")
+//			},
+//			SearchProductsFunc: func(ctx context.Context, query string, limit int) ([]models.ProductMatch, error) {
+//				panic("mock out the SearchProducts method")
+//			},
+//			SetProductStockFunc: func(ctx context.Context, id string, stock *int) (models.Product, error) {
+//				panic("mock out the SetProductStock method")
+//			},
+//			UpdateProductFunc: func(ctx context.Context, product models.Product) (models.Product, error) {
+//				panic("mock out the UpdateProduct method")
+//			},
 //		}
 //
 //		// use mockedProductStorable in code that requires ProductStorable
@@ -32,14 +57,61 @@ var _ ProductStorable = &ProductStorableMock{}
 //
 //	}
 type ProductStorableMock struct {
+	// AdjustProductStockFunc mocks the AdjustProductStock method.
+	AdjustProductStockFunc func(ctx context.Context, id string, delta int) (models.Product, error)
+
+	// CreateProductFunc mocks the CreateProduct method.
+	CreateProductFunc func(ctx context.Context, product models.Product) (models.Product, error)
+
+	// DeleteProductFunc mocks the DeleteProduct method.
+	DeleteProductFunc func(ctx context.Context, id string) error
+
 	// GetProductFunc mocks the GetProduct method.
 	GetProductFunc func(ctx context.Context, id string) (models.Product, error)
 
 	// ListProductsFunc mocks the ListProducts method.
-	ListProductsFunc func(ctx context.Context) ([]models.Product, error)
+	ListProductsFunc func(ctx context.Context, availableAt time.Time) ([]models.Product, error)
+
+	// ListProductsPageFunc mocks the ListProductsPage method.
+	ListProductsPageFunc func(ctx context.Context, filter models.ProductFilter) (models.ProductPage, error)
+
+	// PatchProductFunc mocks the PatchProduct method.
+	PatchProductFunc func(ctx context.Context, id string, patch models.ProductPatch) (models.Product, error)
+
+	// SearchProductsFunc mocks the SearchProducts method.
+	SearchProductsFunc func(ctx context.Context, query string, limit int) ([]models.ProductMatch, error)
+
+	// SetProductStockFunc mocks the SetProductStock method.
+	SetProductStockFunc func(ctx context.Context, id string, stock *int) (models.Product, error)
+
+	// UpdateProductFunc mocks the UpdateProduct method.
+	UpdateProductFunc func(ctx context.Context, product models.Product) (models.Product, error)
 
 	// calls tracks calls to the methods.
 	calls struct {
+		// AdjustProductStock holds details about calls to the AdjustProductStock method.
+		AdjustProductStock []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID string
+			// Delta is the delta argument value.
+			Delta int
+		}
+		// CreateProduct holds details about calls to the CreateProduct method.
+		CreateProduct []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Product is the product argument value.
+			Product models.Product
+		}
+		// DeleteProduct holds details about calls to the DeleteProduct method.
+		DeleteProduct []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID string
+		}
 		// GetProduct holds details about calls to the GetProduct method.
 		GetProduct []struct {
 			// Ctx is the ctx argument value.
@@ -51,10 +123,173 @@ type ProductStorableMock struct {
 		ListProducts []struct {
 			// Ctx is the ctx argument value.
 			Ctx context.Context
+			// AvailableAt is the availableAt argument value.
+			AvailableAt time.Time
+		}
+		// ListProductsPage holds details about calls to the ListProductsPage method.
+		ListProductsPage []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Filter is the filter argument value.
+			Filter models.ProductFilter
+		}
+		// PatchProduct holds details about calls to the PatchProduct method.
+		PatchProduct []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID string
+			// Patch is the patch argument value.
+			Patch models.ProductPatch
+		}
+		// SearchProducts holds details about calls to the SearchProducts method.
+		SearchProducts []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Query is the query argument value.
+			Query string
+			// Limit is the limit argument value.
+			Limit int
+		}
+		// SetProductStock holds details about calls to the SetProductStock method.
+		SetProductStock []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID string
+			// Stock is the stock argument value.
+			Stock *int
+		}
+		// UpdateProduct holds details about calls to the UpdateProduct method.
+		UpdateProduct []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Product is the product argument value.
+			Product models.Product
 		}
 	}
-	lockGetProduct   sync.RWMutex
-	lockListProducts sync.RWMutex
+	lockAdjustProductStock sync.RWMutex
+	lockCreateProduct      sync.RWMutex
+	lockDeleteProduct      sync.RWMutex
+	lockGetProduct         sync.RWMutex
+	lockListProducts       sync.RWMutex
+	lockListProductsPage   sync.RWMutex
+	lockPatchProduct       sync.RWMutex
+	lockSearchProducts     sync.RWMutex
+	lockSetProductStock    sync.RWMutex
+	lockUpdateProduct      sync.RWMutex
+}
+
+// AdjustProductStock calls AdjustProductStockFunc.
+func (mock *ProductStorableMock) AdjustProductStock(ctx context.Context, id string, delta int) (models.Product, error) {
+	if mock.AdjustProductStockFunc == nil {
+		panic("ProductStorableMock.AdjustProductStockFunc: method is nil but ProductStorable.AdjustProductStock was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		ID    string
+		Delta int
+	}{
+		Ctx:   ctx,
+		ID:    id,
+		Delta: delta,
+	}
+	mock.lockAdjustProductStock.Lock()
+	mock.calls.AdjustProductStock = append(mock.calls.AdjustProductStock, callInfo)
+	mock.lockAdjustProductStock.Unlock()
+	return mock.AdjustProductStockFunc(ctx, id, delta)
+}
+
+// AdjustProductStockCalls gets all the calls that were made to AdjustProductStock.
+// Check the length with:
+//
+//	len(mockedProductStorable.AdjustProductStockCalls())
+func (mock *ProductStorableMock) AdjustProductStockCalls() []struct {
+	Ctx   context.Context
+	ID    string
+	Delta int
+} {
+	var calls []struct {
+		Ctx   context.Context
+		ID    string
+		Delta int
+	}
+	mock.lockAdjustProductStock.RLock()
+	calls = mock.calls.AdjustProductStock
+	mock.lockAdjustProductStock.RUnlock()
+	return calls
+}
+
+// CreateProduct calls CreateProductFunc.
+func (mock *ProductStorableMock) CreateProduct(ctx context.Context, product models.Product) (models.Product, error) {
+	if mock.CreateProductFunc == nil {
+		panic("ProductStorableMock.CreateProductFunc: method is nil but ProductStorable.CreateProduct was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		Product models.Product
+	}{
+		Ctx:     ctx,
+		Product: product,
+	}
+	mock.lockCreateProduct.Lock()
+	mock.calls.CreateProduct = append(mock.calls.CreateProduct, callInfo)
+	mock.lockCreateProduct.Unlock()
+	return mock.CreateProductFunc(ctx, product)
+}
+
+// CreateProductCalls gets all the calls that were made to CreateProduct.
+// Check the length with:
+//
+//	len(mockedProductStorable.CreateProductCalls())
+func (mock *ProductStorableMock) CreateProductCalls() []struct {
+	Ctx     context.Context
+	Product models.Product
+} {
+	var calls []struct {
+		Ctx     context.Context
+		Product models.Product
+	}
+	mock.lockCreateProduct.RLock()
+	calls = mock.calls.CreateProduct
+	mock.lockCreateProduct.RUnlock()
+	return calls
+}
+
+// DeleteProduct calls DeleteProductFunc.
+func (mock *ProductStorableMock) DeleteProduct(ctx context.Context, id string) error {
+	if mock.DeleteProductFunc == nil {
+		panic("ProductStorableMock.DeleteProductFunc: method is nil but ProductStorable.DeleteProduct was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		ID  string
+	}{
+		Ctx: ctx,
+		ID:  id,
+	}
+	mock.lockDeleteProduct.Lock()
+	mock.calls.DeleteProduct = append(mock.calls.DeleteProduct, callInfo)
+	mock.lockDeleteProduct.Unlock()
+	return mock.DeleteProductFunc(ctx, id)
+}
+
+// DeleteProductCalls gets all the calls that were made to DeleteProduct.
+// Check the length with:
+//
+//	len(mockedProductStorable.DeleteProductCalls())
+func (mock *ProductStorableMock) DeleteProductCalls() []struct {
+	Ctx context.Context
+	ID  string
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  string
+	}
+	mock.lockDeleteProduct.RLock()
+	calls = mock.calls.DeleteProduct
+	mock.lockDeleteProduct.RUnlock()
+	return calls
 }
 
 // GetProduct calls GetProductFunc.
@@ -94,19 +329,21 @@ func (mock *ProductStorableMock) GetProductCalls() []struct {
 }
 
 // ListProducts calls ListProductsFunc.
-func (mock *ProductStorableMock) ListProducts(ctx context.Context) ([]models.Product, error) {
+func (mock *ProductStorableMock) ListProducts(ctx context.Context, availableAt time.Time) ([]models.Product, error) {
 	if mock.ListProductsFunc == nil {
 		panic("ProductStorableMock.ListProductsFunc: method is nil but ProductStorable.ListProducts was just called")
 	}
 	callInfo := struct {
-		Ctx context.Context
+		Ctx         context.Context
+		AvailableAt time.Time
 	}{
-		Ctx: ctx,
+		Ctx:         ctx,
+		AvailableAt: availableAt,
 	}
 	mock.lockListProducts.Lock()
 	mock.calls.ListProducts = append(mock.calls.ListProducts, callInfo)
 	mock.lockListProducts.Unlock()
-	return mock.ListProductsFunc(ctx)
+	return mock.ListProductsFunc(ctx, availableAt)
 }
 
 // ListProductsCalls gets all the calls that were made to ListProducts.
@@ -114,13 +351,207 @@ func (mock *ProductStorableMock) ListProducts(ctx context.Context) ([]models.Pro
 //
 //	len(mockedProductStorable.ListProductsCalls())
 func (mock *ProductStorableMock) ListProductsCalls() []struct {
-	Ctx context.Context
+	Ctx         context.Context
+	AvailableAt time.Time
 } {
 	var calls []struct {
-		Ctx context.Context
+		Ctx         context.Context
+		AvailableAt time.Time
 	}
 	mock.lockListProducts.RLock()
 	calls = mock.calls.ListProducts
 	mock.lockListProducts.RUnlock()
 	return calls
 }
+
+// ListProductsPage calls ListProductsPageFunc.
+func (mock *ProductStorableMock) ListProductsPage(ctx context.Context, filter models.ProductFilter) (models.ProductPage, error) {
+	if mock.ListProductsPageFunc == nil {
+		panic("ProductStorableMock.ListProductsPageFunc: method is nil but ProductStorable.ListProductsPage was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Filter models.ProductFilter
+	}{
+		Ctx:    ctx,
+		Filter: filter,
+	}
+	mock.lockListProductsPage.Lock()
+	mock.calls.ListProductsPage = append(mock.calls.ListProductsPage, callInfo)
+	mock.lockListProductsPage.Unlock()
+	return mock.ListProductsPageFunc(ctx, filter)
+}
+
+// ListProductsPageCalls gets all the calls that were made to ListProductsPage.
+// Check the length with:
+//
+//	len(mockedProductStorable.ListProductsPageCalls())
+func (mock *ProductStorableMock) ListProductsPageCalls() []struct {
+	Ctx    context.Context
+	Filter models.ProductFilter
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Filter models.ProductFilter
+	}
+	mock.lockListProductsPage.RLock()
+	calls = mock.calls.ListProductsPage
+	mock.lockListProductsPage.RUnlock()
+	return calls
+}
+
+// PatchProduct calls PatchProductFunc.
+func (mock *ProductStorableMock) PatchProduct(ctx context.Context, id string, patch models.ProductPatch) (models.Product, error) {
+	if mock.PatchProductFunc == nil {
+		panic("ProductStorableMock.PatchProductFunc: method is nil but ProductStorable.PatchProduct was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		ID    string
+		Patch models.ProductPatch
+	}{
+		Ctx:   ctx,
+		ID:    id,
+		Patch: patch,
+	}
+	mock.lockPatchProduct.Lock()
+	mock.calls.PatchProduct = append(mock.calls.PatchProduct, callInfo)
+	mock.lockPatchProduct.Unlock()
+	return mock.PatchProductFunc(ctx, id, patch)
+}
+
+// PatchProductCalls gets all the calls that were made to PatchProduct.
+// Check the length with:
+//
+//	len(mockedProductStorable.PatchProductCalls())
+func (mock *ProductStorableMock) PatchProductCalls() []struct {
+	Ctx   context.Context
+	ID    string
+	Patch models.ProductPatch
+} {
+	var calls []struct {
+		Ctx   context.Context
+		ID    string
+		Patch models.ProductPatch
+	}
+	mock.lockPatchProduct.RLock()
+	calls = mock.calls.PatchProduct
+	mock.lockPatchProduct.RUnlock()
+	return calls
+}
+
+// SearchProducts calls SearchProductsFunc.
+func (mock *ProductStorableMock) SearchProducts(ctx context.Context, query string, limit int) ([]models.ProductMatch, error) {
+	if mock.SearchProductsFunc == nil {
+		panic("ProductStorableMock.SearchProductsFunc: method is nil but ProductStorable.SearchProducts was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Query string
+		Limit int
+	}{
+		Ctx:   ctx,
+		Query: query,
+		Limit: limit,
+	}
+	mock.lockSearchProducts.Lock()
+	mock.calls.SearchProducts = append(mock.calls.SearchProducts, callInfo)
+	mock.lockSearchProducts.Unlock()
+	return mock.SearchProductsFunc(ctx, query, limit)
+}
+
+// SearchProductsCalls gets all the calls that were made to SearchProducts.
+// Check the length with:
+//
+//	len(mockedProductStorable.SearchProductsCalls())
+func (mock *ProductStorableMock) SearchProductsCalls() []struct {
+	Ctx   context.Context
+	Query string
+	Limit int
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Query string
+		Limit int
+	}
+	mock.lockSearchProducts.RLock()
+	calls = mock.calls.SearchProducts
+	mock.lockSearchProducts.RUnlock()
+	return calls
+}
+
+// SetProductStock calls SetProductStockFunc.
+func (mock *ProductStorableMock) SetProductStock(ctx context.Context, id string, stock *int) (models.Product, error) {
+	if mock.SetProductStockFunc == nil {
+		panic("ProductStorableMock.SetProductStockFunc: method is nil but ProductStorable.SetProductStock was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		ID    string
+		Stock *int
+	}{
+		Ctx:   ctx,
+		ID:    id,
+		Stock: stock,
+	}
+	mock.lockSetProductStock.Lock()
+	mock.calls.SetProductStock = append(mock.calls.SetProductStock, callInfo)
+	mock.lockSetProductStock.Unlock()
+	return mock.SetProductStockFunc(ctx, id, stock)
+}
+
+// SetProductStockCalls gets all the calls that were made to SetProductStock.
+// Check the length with:
+//
+//	len(mockedProductStorable.SetProductStockCalls())
+func (mock *ProductStorableMock) SetProductStockCalls() []struct {
+	Ctx   context.Context
+	ID    string
+	Stock *int
+} {
+	var calls []struct {
+		Ctx   context.Context
+		ID    string
+		Stock *int
+	}
+	mock.lockSetProductStock.RLock()
+	calls = mock.calls.SetProductStock
+	mock.lockSetProductStock.RUnlock()
+	return calls
+}
+
+// UpdateProduct calls UpdateProductFunc.
+func (mock *ProductStorableMock) UpdateProduct(ctx context.Context, product models.Product) (models.Product, error) {
+	if mock.UpdateProductFunc == nil {
+		panic("ProductStorableMock.UpdateProductFunc: method is nil but ProductStorable.UpdateProduct was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		Product models.Product
+	}{
+		Ctx:     ctx,
+		Product: product,
+	}
+	mock.lockUpdateProduct.Lock()
+	mock.calls.UpdateProduct = append(mock.calls.UpdateProduct, callInfo)
+	mock.lockUpdateProduct.Unlock()
+	return mock.UpdateProductFunc(ctx, product)
+}
+
+// UpdateProductCalls gets all the calls that were made to UpdateProduct.
+// Check the length with:
+//
+//	len(mockedProductStorable.UpdateProductCalls())
+func (mock *ProductStorableMock) UpdateProductCalls() []struct {
+	Ctx     context.Context
+	Product models.Product
+} {
+	var calls []struct {
+		Ctx     context.Context
+		Product models.Product
+	}
+	mock.lockUpdateProduct.RLock()
+	calls = mock.calls.UpdateProduct
+	mock.lockUpdateProduct.RUnlock()
+	return calls
+}
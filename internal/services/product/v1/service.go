@@ -2,13 +2,18 @@ package v1
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 	"github.com/sgrumley/kart-challenge/internal/services/product/v1/mapper"
 	"github.com/sgrumley/kart-challenge/internal/store"
 	"github.com/sgrumley/kart-challenge/pkg/logger"
+	"github.com/sgrumley/kart-challenge/pkg/middleware"
 	"github.com/sgrumley/kart-challenge/pkg/models"
 	"github.com/sgrumley/kart-challenge/pkg/web"
 )
@@ -19,17 +24,31 @@ var _ ProductStorable = (*store.Store)(nil)
 
 type ProductStorable interface {
 	GetProduct(ctx context.Context, id string) (models.Product, error)
-	ListProducts(ctx context.Context) ([]models.Product, error)
+	ListProducts(ctx context.Context, availableAt time.Time) ([]models.Product, error)
+	ListProductsPage(ctx context.Context, filter models.ProductFilter) (models.ProductPage, error)
+	SearchProducts(ctx context.Context, query string, limit int) ([]models.ProductMatch, error)
+	CreateProduct(ctx context.Context, product models.Product) (models.Product, error)
+	UpdateProduct(ctx context.Context, product models.Product) (models.Product, error)
+	PatchProduct(ctx context.Context, id string, patch models.ProductPatch) (models.Product, error)
+	DeleteProduct(ctx context.Context, id string) error
+	SetProductStock(ctx context.Context, id string, stock *int) (models.Product, error)
+	AdjustProductStock(ctx context.Context, id string, delta int) (models.Product, error)
 }
 
-func NewService(store ProductStorable) *ProductService {
+// NewService builds the product service. Reads are public, writes require the
+// apiKey in the api_key header.
+func NewService(store ProductStorable, apiKey string) *ProductService {
 	return &ProductService{
-		store: store,
+		store:    store,
+		apiKey:   apiKey,
+		validate: validator.New(),
 	}
 }
 
 type ProductService struct {
-	store ProductStorable
+	validate *validator.Validate
+	store    ProductStorable
+	apiKey   string
 }
 
 var (
@@ -39,13 +58,57 @@ var (
 		Description: "Invalid ID supplied",
 	}
 
+	Err400InvalidQuery = &web.Error{
+		Status:      http.StatusBadRequest,
+		Code:        "invalid_query_parameters",
+		Description: "Invalid query parameters supplied",
+	}
+
+	Err400InvalidRequestBody = &web.Error{
+		Status:      http.StatusBadRequest,
+		Code:        "invalid_request_body",
+		Description: "Invalid input",
+	}
+
 	Err404ProductNotFound = &web.Error{
 		Status:      http.StatusNotFound,
 		Code:        "product_not_found",
 		Description: "Product not found",
 	}
+
+	Err409InsufficientStock = &web.Error{
+		Status:      http.StatusConflict,
+		Code:        "insufficient_stock",
+		Description: "The stock cannot go below zero",
+	}
+
+	Err422Validation = &web.Error{
+		Status:      http.StatusUnprocessableEntity,
+		Code:        "invalid_product_detail",
+		Description: "Validation exception",
+	}
+
+	Err422UnknownCategory = &web.Error{
+		Status:      http.StatusUnprocessableEntity,
+		Code:        "unknown_category",
+		Description: "The category does not exist",
+	}
 )
 
+// writeProductError is shared by the product writes, which can miss the product,
+// point at an unknown category or take the stock below zero.
+func writeProductError(err error) error {
+	switch {
+	case errors.Is(err, store.ErrProductNotFound):
+		return Err404ProductNotFound
+	case errors.Is(err, store.ErrCategoryNotFound):
+		return Err422UnknownCategory
+	case errors.Is(err, store.ErrInsufficientStock):
+		return Err409InsufficientStock
+	}
+	return err
+}
+
 func (s *ProductService) GetProduct(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	productID := chi.URLParam(r, "product_id")
@@ -65,10 +128,307 @@ func (s *ProductService) GetProduct(w http.ResponseWriter, r *http.Request) {
 	web.Respond(w, http.StatusOK, mapper.GetProductToResponse(&product))
 }
 
+// ListProducts returns the currently available catalogue as a plain array. When
+// any listing parameter is given it returns a filtered page wrapped in an
+// envelope instead. Admins can pass include_unavailable to list every product.
 func (s *ProductService) ListProducts(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	products, _ := s.store.ListProducts(ctx)
+	include, err := mapper.IncludeUnavailableFromQuery(r.URL.Query())
+	if err != nil {
+		logger.Error(ctx, "invalid query parameters", err)
+		web.RespondJSONError(w, Err400InvalidQuery)
+		return
+	}
+	if include && !middleware.HasAPIKey(r, s.apiKey) {
+		logger.Error(ctx, "unauthorized request", fmt.Errorf("include_unavailable needs the %s header", middleware.APIKeyHeader))
+		web.RespondJSONError(w, web.Err401Default)
+		return
+	}
+
+	// a zero time lists unavailable products too
+	var availableAt time.Time
+	if !include {
+		availableAt = time.Now()
+	}
+
+	if mapper.IsPagedListing(r.URL.Query()) {
+		s.listProductsPage(w, r, availableAt)
+		return
+	}
+
+	products, err := s.store.ListProducts(ctx, availableAt)
+	if err != nil {
+		logger.Error(ctx, "failed listing products in store", err)
+		web.RespondJSONError(w, fmt.Errorf("failed listing products in store: %w", err))
+		return
+	}
 
 	web.Respond(w, http.StatusOK, mapper.ListProductsToResponse(products))
 }
+
+func (s *ProductService) listProductsPage(w http.ResponseWriter, r *http.Request, availableAt time.Time) {
+	ctx := r.Context()
+
+	req, err := mapper.ListProductsRequestFromQuery(r.URL.Query())
+	if err != nil {
+		logger.Error(ctx, "invalid query parameters", err)
+		web.RespondJSONError(w, Err400InvalidQuery)
+		return
+	}
+
+	if err := s.validate.Struct(req); err != nil {
+		logger.Error(ctx, "validation failed", err)
+		web.RespondJSONError(w, Err400InvalidQuery)
+		return
+	}
+
+	filter := mapper.ProductFilterFromRequest(req)
+	filter.AvailableAt = availableAt
+
+	page, err := s.store.ListProductsPage(ctx, filter)
+	if err != nil {
+		if errors.Is(err, store.ErrInvalidCursor) {
+			logger.Error(ctx, "invalid cursor", err)
+			web.RespondJSONError(w, Err400InvalidQuery)
+			return
+		}
+		logger.Error(ctx, "failed listing products in store", err)
+		web.RespondJSONError(w, fmt.Errorf("failed listing products in store: %w", err))
+		return
+	}
+
+	web.Respond(w, http.StatusOK, mapper.ListProductsPageToResponse(page, req.Limit))
+}
+
+// SearchProducts ranks products by full-text match on their name and category,
+// names that are only close to the query are returned after them.
+func (s *ProductService) SearchProducts(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := mapper.SearchProductsRequestFromQuery(r.URL.Query())
+	if err != nil {
+		logger.Error(ctx, "invalid query parameters", err)
+		web.RespondJSONError(w, Err400InvalidQuery)
+		return
+	}
+
+	if err := s.validate.Struct(req); err != nil {
+		logger.Error(ctx, "validation failed", err)
+		web.RespondJSONError(w, Err400InvalidQuery)
+		return
+	}
+
+	matches, err := s.store.SearchProducts(ctx, req.Query, req.Limit)
+	if err != nil {
+		logger.Error(ctx, "failed searching products in store", err)
+		web.RespondJSONError(w, fmt.Errorf("failed searching products in store: %w", err))
+		return
+	}
+
+	web.Respond(w, http.StatusOK, mapper.SearchProductsToResponse(req.Query, matches))
+}
+
+func (s *ProductService) CreateProduct(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req mapper.CreateProductRequest
+	if err := web.DecodeBody(r, &req); err != nil {
+		logger.Error(ctx, "invalid request body", err)
+		web.RespondJSONError(w, Err400InvalidRequestBody)
+		return
+	}
+
+	if err := s.validate.Struct(req); err != nil {
+		logger.Error(ctx, "validation failed", err)
+		web.RespondJSONError(w, Err422Validation)
+		return
+	}
+
+	product, err := s.store.CreateProduct(ctx, mapper.CreateProductFromRequest(req))
+	if err != nil {
+		logger.Error(ctx, "failed creating product in store", err)
+		web.RespondJSONError(w, writeProductError(err))
+		return
+	}
+
+	web.Respond(w, http.StatusCreated, mapper.GetProductToResponse(&product))
+}
+
+func (s *ProductService) UpdateProduct(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	productID := chi.URLParam(r, "product_id")
+	if _, err := uuid.Parse(productID); err != nil {
+		logger.Error(ctx, "invalid product id is not uuid", Err400InvalidProductID)
+		web.RespondJSONError(w, Err400InvalidProductID)
+		return
+	}
+
+	var req mapper.UpdateProductRequest
+	if err := web.DecodeBody(r, &req); err != nil {
+		logger.Error(ctx, "invalid request body", err)
+		web.RespondJSONError(w, Err400InvalidRequestBody)
+		return
+	}
+
+	if err := s.validate.Struct(req); err != nil {
+		logger.Error(ctx, "validation failed", err)
+		web.RespondJSONError(w, Err422Validation)
+		return
+	}
+
+	product, err := s.store.UpdateProduct(ctx, mapper.UpdateProductFromRequest(productID, req))
+	if err != nil {
+		logger.Error(ctx, "failed updating product in store: "+productID, err)
+		web.RespondJSONError(w, writeProductError(err))
+		return
+	}
+
+	web.Respond(w, http.StatusOK, mapper.GetProductToResponse(&product))
+}
+
+func (s *ProductService) PatchProduct(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	productID := chi.URLParam(r, "product_id")
+	if _, err := uuid.Parse(productID); err != nil {
+		logger.Error(ctx, "invalid product id is not uuid", Err400InvalidProductID)
+		web.RespondJSONError(w, Err400InvalidProductID)
+		return
+	}
+
+	var req mapper.PatchProductRequest
+	if err := web.DecodeBody(r, &req); err != nil {
+		logger.Error(ctx, "invalid request body", err)
+		web.RespondJSONError(w, Err400InvalidRequestBody)
+		return
+	}
+
+	if req.Empty() {
+		logger.Error(ctx, "validation failed", fmt.Errorf("no product fields to patch"))
+		web.RespondJSONError(w, Err422Validation)
+		return
+	}
+
+	if err := s.validate.Struct(req); err != nil {
+		logger.Error(ctx, "validation failed", err)
+		web.RespondJSONError(w, Err422Validation)
+		return
+	}
+
+	product, err := s.store.PatchProduct(ctx, productID, mapper.PatchProductFromRequest(req))
+	if err != nil {
+		logger.Error(ctx, "failed patching product in store: "+productID, err)
+		web.RespondJSONError(w, writeProductError(err))
+		return
+	}
+
+	web.Respond(w, http.StatusOK, mapper.GetProductToResponse(&product))
+}
+
+// DeleteProduct soft deletes a product, past orders keep referencing it.
+func (s *ProductService) DeleteProduct(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	productID := chi.URLParam(r, "product_id")
+	if _, err := uuid.Parse(productID); err != nil {
+		logger.Error(ctx, "invalid product id is not uuid", Err400InvalidProductID)
+		web.RespondJSONError(w, Err400InvalidProductID)
+		return
+	}
+
+	if err := s.store.DeleteProduct(ctx, productID); err != nil {
+		logger.Error(ctx, "failed deleting product in store: "+productID, err)
+		web.RespondJSONError(w, writeProductError(err))
+		return
+	}
+
+	web.RespondNoContent(w)
+}
+
+// SetProductStock sets the number of units in stock, orders for more than are
+// left are rejected from then on.
+func (s *ProductService) SetProductStock(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	productID := chi.URLParam(r, "product_id")
+	if _, err := uuid.Parse(productID); err != nil {
+		logger.Error(ctx, "invalid product id is not uuid", Err400InvalidProductID)
+		web.RespondJSONError(w, Err400InvalidProductID)
+		return
+	}
+
+	var req mapper.SetProductStockRequest
+	if err := web.DecodeBody(r, &req); err != nil {
+		logger.Error(ctx, "invalid request body", err)
+		web.RespondJSONError(w, Err400InvalidRequestBody)
+		return
+	}
+
+	if err := s.validate.Struct(req); err != nil {
+		logger.Error(ctx, "validation failed", err)
+		web.RespondJSONError(w, Err422Validation)
+		return
+	}
+
+	product, err := s.store.SetProductStock(ctx, productID, req.Stock)
+	if err != nil {
+		logger.Error(ctx, "failed setting product stock in store: "+productID, err)
+		web.RespondJSONError(w, writeProductError(err))
+		return
+	}
+
+	web.Respond(w, http.StatusOK, mapper.GetProductToResponse(&product))
+}
+
+// AdjustProductStock adds to or takes away from the stock, e.g. when a delivery
+// arrives or units are written off.
+func (s *ProductService) AdjustProductStock(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	productID := chi.URLParam(r, "product_id")
+	if _, err := uuid.Parse(productID); err != nil {
+		logger.Error(ctx, "invalid product id is not uuid", Err400InvalidProductID)
+		web.RespondJSONError(w, Err400InvalidProductID)
+		return
+	}
+
+	var req mapper.AdjustProductStockRequest
+	if err := web.DecodeBody(r, &req); err != nil {
+		logger.Error(ctx, "invalid request body", err)
+		web.RespondJSONError(w, Err400InvalidRequestBody)
+		return
+	}
+
+	if err := s.validate.Struct(req); err != nil {
+		logger.Error(ctx, "validation failed", err)
+		web.RespondJSONError(w, Err422Validation)
+		return
+	}
+
+	product, err := s.store.AdjustProductStock(ctx, productID, req.Delta)
+	if err != nil {
+		logger.Error(ctx, "failed adjusting product stock in store: "+productID, err)
+		web.RespondJSONError(w, writeProductError(err))
+		return
+	}
+
+	web.Respond(w, http.StatusOK, mapper.GetProductToResponse(&product))
+}
+
+// UntrackProductStock stops tracking the stock, the product can then be ordered
+// in any quantity.
+func (s *ProductService) UntrackProductStock(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	productID := chi.URLParam(r, "product_id")
+	if _, err := uuid.Parse(productID); err != nil {
+		logger.Error(ctx, "invalid product id is not uuid", Err400InvalidProductID)
+		web.RespondJSONError(w, Err400InvalidProductID)
+		return
+	}
+
+	product, err := s.store.SetProductStock(ctx, productID, nil)
+	if err != nil {
+		logger.Error(ctx, "failed untracking product stock in store: "+productID, err)
+		web.RespondJSONError(w, writeProductError(err))
+		return
+	}
+
+	web.Respond(w, http.StatusOK, mapper.GetProductToResponse(&product))
+}
@@ -6,8 +6,10 @@ import (
 	"log/slog"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/sgrumley/kart-challenge/internal/services/product/v1/mapper"
+	"github.com/sgrumley/kart-challenge/internal/store"
 	"github.com/sgrumley/kart-challenge/pkg/logger"
 	"github.com/sgrumley/kart-challenge/pkg/models"
 	"github.com/sgrumley/kart-challenge/pkg/testhelper"
@@ -16,6 +18,15 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+const (
+	testAPIKey    = "a-secret-key"
+	testProductID = "00000000-0000-0000-0000-000000000001"
+)
+
+var authHeaders = map[string]string{
+	"api_key": testAPIKey,
+}
+
 func Test_API_Service_GetProduct(t *testing.T) {
 	t.Parallel()
 	testCases := map[string]struct {
@@ -52,6 +63,56 @@ func Test_API_Service_GetProduct(t *testing.T) {
 				assert.Equal(t, want, &actual)
 			},
 		},
+		"success/with_modifier_groups": {
+			productID: "00000000-0000-0000-0000-000000000006",
+			storeMock: &ProductStorableMock{
+				GetProductFunc: func(ctx context.Context, id string) (models.Product, error) {
+					return models.Product{
+						ID:       "00000000-0000-0000-0000-000000000006",
+						Name:     "burger",
+						Category: "mains",
+						Price:    15.99,
+						ModifierGroups: []models.ModifierGroup{
+							{
+								ID:        "20000000-0000-0000-0000-000000000001",
+								Name:      "Size",
+								MinSelect: 1,
+								MaxSelect: 1,
+								Modifiers: []models.Modifier{
+									{ID: "30000000-0000-0000-0000-000000000001", Name: "Regular"},
+									{ID: "30000000-0000-0000-0000-000000000002", Name: "Large", PriceDelta: 2},
+								},
+							},
+						},
+					}, nil
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *ProductStorableMock) {
+				require.Equal(t, http.StatusOK, got.StatusCode)
+
+				want := mapper.Product{
+					ID:       "00000000-0000-0000-0000-000000000006",
+					Name:     "burger",
+					Category: "mains",
+					Price:    15.99,
+					ModifierGroups: []mapper.ModifierGroup{
+						{
+							ID:        "20000000-0000-0000-0000-000000000001",
+							Name:      "Size",
+							MinSelect: 1,
+							MaxSelect: 1,
+							Modifiers: []mapper.Modifier{
+								{ID: "30000000-0000-0000-0000-000000000001", Name: "Regular"},
+								{ID: "30000000-0000-0000-0000-000000000002", Name: "Large", PriceDelta: 2},
+							},
+						},
+					},
+				}
+
+				actual := testhelper.PayloadAsType[mapper.Product](t, got.Body)
+				assert.Equal(t, want, actual)
+			},
+		},
 		"error/missing_product_id": {
 			productID: "",
 			storeMock: &ProductStorableMock{},
@@ -99,7 +160,7 @@ func Test_API_Service_GetProduct(t *testing.T) {
 				logger.WithFormat(logger.HandlerJSON),
 			)
 
-			svc := NewService(tc.storeMock)
+			svc := NewService(tc.storeMock, testAPIKey)
 			testServer := testhelper.SetupServer(svc, *log)
 
 			url := fmt.Sprintf("%s/api/v1/product/%s", testServer.URL, tc.productID)
@@ -118,12 +179,14 @@ func Test_API_Service_GetProduct(t *testing.T) {
 func Test_API_Service_ListProducts(t *testing.T) {
 	t.Parallel()
 	testCases := map[string]struct {
+		query         string
+		headers       map[string]string
 		storeMock     *ProductStorableMock
 		wantAssertion func(t *testing.T, got *http.Response, storeMock *ProductStorableMock)
 	}{
 		"success/happy_path": {
 			storeMock: &ProductStorableMock{
-				ListProductsFunc: func(ctx context.Context) ([]models.Product, error) {
+				ListProductsFunc: func(ctx context.Context, availableAt time.Time) ([]models.Product, error) {
 					return []models.Product{
 						{
 							ID:       "00000000-0000-0000-0000-000000000001",
@@ -143,6 +206,7 @@ func Test_API_Service_ListProducts(t *testing.T) {
 			wantAssertion: func(t *testing.T, got *http.Response, storeMock *ProductStorableMock) {
 				require.Equal(t, http.StatusOK, got.StatusCode)
 				require.Len(t, storeMock.ListProductsCalls(), 1)
+				assert.False(t, storeMock.ListProductsCalls()[0].AvailableAt.IsZero())
 
 				want := &mapper.ListProductsResponse{
 					{
@@ -163,6 +227,56 @@ func Test_API_Service_ListProducts(t *testing.T) {
 				assert.Equal(t, want, &actual)
 			},
 		},
+		"success/include_unavailable_for_admin": {
+			query:   "?include_unavailable=true",
+			headers: authHeaders,
+			storeMock: &ProductStorableMock{
+				ListProductsFunc: func(ctx context.Context, availableAt time.Time) ([]models.Product, error) {
+					return []models.Product{}, nil
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *ProductStorableMock) {
+				require.Equal(t, http.StatusOK, got.StatusCode)
+				require.Len(t, storeMock.ListProductsCalls(), 1)
+				assert.True(t, storeMock.ListProductsCalls()[0].AvailableAt.IsZero())
+			},
+		},
+		"error/include_unavailable_without_api_key": {
+			query:     "?include_unavailable=true",
+			storeMock: &ProductStorableMock{},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *ProductStorableMock) {
+				require.Equal(t, http.StatusUnauthorized, got.StatusCode)
+				require.Len(t, storeMock.ListProductsCalls(), 0)
+			},
+		},
+		"error/store_failed": {
+			storeMock: &ProductStorableMock{
+				ListProductsFunc: func(ctx context.Context, availableAt time.Time) ([]models.Product, error) {
+					return nil, fmt.Errorf("error")
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *ProductStorableMock) {
+				require.Equal(t, http.StatusInternalServerError, got.StatusCode)
+				require.Len(t, storeMock.ListProductsCalls(), 1)
+
+				actual := testhelper.PayloadAsType[web.ErrorResponse](t, got.Body)
+				expectedError := testhelper.MapExpectedErrorResponse(web.Err500Default)
+				assert.Equal(t, expectedError, actual)
+			},
+		},
+		"error/invalid_include_unavailable": {
+			query:     "?include_unavailable=maybe",
+			headers:   authHeaders,
+			storeMock: &ProductStorableMock{},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *ProductStorableMock) {
+				require.Equal(t, http.StatusBadRequest, got.StatusCode)
+				require.Len(t, storeMock.ListProductsCalls(), 0)
+
+				actual := testhelper.PayloadAsType[web.ErrorResponse](t, got.Body)
+				expectedError := testhelper.MapExpectedErrorResponse(Err400InvalidQuery)
+				assert.Equal(t, expectedError, actual)
+			},
+		},
 	}
 
 	for name, tc := range testCases {
@@ -172,10 +286,262 @@ func Test_API_Service_ListProducts(t *testing.T) {
 				logger.WithFormat(logger.HandlerJSON),
 			)
 
-			svc := NewService(tc.storeMock)
+			svc := NewService(tc.storeMock, testAPIKey)
 			testServer := testhelper.SetupServer(svc, *log)
 
-			url := fmt.Sprintf("%s/api/v1/product", testServer.URL)
+			url := fmt.Sprintf("%s/api/v1/product%s", testServer.URL, tc.query)
+			res := testhelper.SendRequest[any](t, "GET", url, nil, tc.headers)
+			t.Cleanup(func() {
+				if res.Body != nil {
+					require.NoError(t, res.Body.Close())
+				}
+			})
+			tc.wantAssertion(t, res, tc.storeMock)
+			testServer.Close()
+		})
+	}
+}
+
+func Test_API_Service_ListProductsPage(t *testing.T) {
+	t.Parallel()
+	testCases := map[string]struct {
+		query         string
+		storeMock     *ProductStorableMock
+		wantAssertion func(t *testing.T, got *http.Response, storeMock *ProductStorableMock)
+	}{
+		"success/filtered_page": {
+			query: "?category=breakfast&min_price=5&max_price=10&q=egg&sort=-price&limit=1",
+			storeMock: &ProductStorableMock{
+				ListProductsPageFunc: func(ctx context.Context, filter models.ProductFilter) (models.ProductPage, error) {
+					return models.ProductPage{
+						Products: []models.Product{
+							{ID: testProductID, Name: "eggs", Category: "breakfast", Price: 8.99},
+						},
+						NextCursor: "next-page",
+					}, nil
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *ProductStorableMock) {
+				require.Equal(t, http.StatusOK, got.StatusCode)
+				require.Len(t, storeMock.ListProductsCalls(), 0)
+				require.Len(t, storeMock.ListProductsPageCalls(), 1)
+
+				wantFilter := models.ProductFilter{
+					Category: "breakfast",
+					MinPrice: 5,
+					MaxPrice: 10,
+					Query:    "egg",
+					Sort:     models.ProductSortPriceDesc,
+					Limit:    1,
+				}
+				gotFilter := storeMock.ListProductsPageCalls()[0].Filter
+				assert.False(t, gotFilter.AvailableAt.IsZero())
+				gotFilter.AvailableAt = time.Time{}
+				assert.Equal(t, wantFilter, gotFilter)
+
+				want := mapper.ListProductsPageResponse{
+					Products: mapper.ListProductsResponse{
+						{ID: testProductID, Name: "eggs", Category: "breakfast", Price: 8.99},
+					},
+					Pagination: mapper.Pagination{
+						Limit:      1,
+						Count:      1,
+						NextCursor: "next-page",
+					},
+				}
+
+				actual := testhelper.PayloadAsType[mapper.ListProductsPageResponse](t, got.Body)
+				assert.Equal(t, want, actual)
+			},
+		},
+		"success/cursor_uses_default_limit": {
+			query: "?cursor=next-page",
+			storeMock: &ProductStorableMock{
+				ListProductsPageFunc: func(ctx context.Context, filter models.ProductFilter) (models.ProductPage, error) {
+					return models.ProductPage{Products: []models.Product{}}, nil
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *ProductStorableMock) {
+				require.Equal(t, http.StatusOK, got.StatusCode)
+				require.Len(t, storeMock.ListProductsPageCalls(), 1)
+
+				filter := storeMock.ListProductsPageCalls()[0].Filter
+				assert.Equal(t, "next-page", filter.Cursor)
+				assert.Equal(t, mapper.DefaultListLimit, filter.Limit)
+
+				actual := testhelper.PayloadAsType[mapper.ListProductsPageResponse](t, got.Body)
+				assert.Empty(t, actual.Products)
+				assert.Empty(t, actual.Pagination.NextCursor)
+			},
+		},
+		"error/invalid_sort": {
+			query:     "?sort=rating",
+			storeMock: &ProductStorableMock{},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *ProductStorableMock) {
+				require.Equal(t, http.StatusBadRequest, got.StatusCode)
+				require.Len(t, storeMock.ListProductsPageCalls(), 0)
+
+				actual := testhelper.PayloadAsType[web.ErrorResponse](t, got.Body)
+				expectedError := testhelper.MapExpectedErrorResponse(Err400InvalidQuery)
+				assert.Equal(t, expectedError, actual)
+			},
+		},
+		"error/max_below_min_price": {
+			query:     "?min_price=10&max_price=5",
+			storeMock: &ProductStorableMock{},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *ProductStorableMock) {
+				require.Equal(t, http.StatusBadRequest, got.StatusCode)
+				require.Len(t, storeMock.ListProductsPageCalls(), 0)
+			},
+		},
+		"error/invalid_price": {
+			query:     "?min_price=cheap",
+			storeMock: &ProductStorableMock{},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *ProductStorableMock) {
+				require.Equal(t, http.StatusBadRequest, got.StatusCode)
+				require.Len(t, storeMock.ListProductsPageCalls(), 0)
+			},
+		},
+		"error/invalid_cursor": {
+			query: "?cursor=garbage",
+			storeMock: &ProductStorableMock{
+				ListProductsPageFunc: func(ctx context.Context, filter models.ProductFilter) (models.ProductPage, error) {
+					return models.ProductPage{}, store.ErrInvalidCursor
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *ProductStorableMock) {
+				require.Equal(t, http.StatusBadRequest, got.StatusCode)
+
+				actual := testhelper.PayloadAsType[web.ErrorResponse](t, got.Body)
+				expectedError := testhelper.MapExpectedErrorResponse(Err400InvalidQuery)
+				assert.Equal(t, expectedError, actual)
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			log := logger.NewLogger(
+				logger.WithLevel(slog.LevelDebug),
+				logger.WithFormat(logger.HandlerJSON),
+			)
+
+			svc := NewService(tc.storeMock, testAPIKey)
+			testServer := testhelper.SetupServer(svc, *log)
+
+			url := fmt.Sprintf("%s/api/v1/product%s", testServer.URL, tc.query)
+			res := testhelper.SendRequest[any](t, "GET", url, nil, nil)
+			t.Cleanup(func() {
+				if res.Body != nil {
+					require.NoError(t, res.Body.Close())
+				}
+			})
+			tc.wantAssertion(t, res, tc.storeMock)
+			testServer.Close()
+		})
+	}
+}
+
+func Test_API_Service_SearchProducts(t *testing.T) {
+	t.Parallel()
+	testCases := map[string]struct {
+		query         string
+		storeMock     *ProductStorableMock
+		wantAssertion func(t *testing.T, got *http.Response, storeMock *ProductStorableMock)
+	}{
+		"success/fuzzy_match": {
+			query: "?q=mozarella",
+			storeMock: &ProductStorableMock{
+				SearchProductsFunc: func(ctx context.Context, query string, limit int) ([]models.ProductMatch, error) {
+					return []models.ProductMatch{
+						{
+							Product:  models.Product{ID: testProductID, Name: "Mozzarella Sticks", Category: "Sides", Price: 5.5},
+							Score:    0.9,
+							Headline: "Mozzarella Sticks",
+						},
+					}, nil
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *ProductStorableMock) {
+				require.Equal(t, http.StatusOK, got.StatusCode)
+				require.Len(t, storeMock.SearchProductsCalls(), 1)
+				assert.Equal(t, "mozarella", storeMock.SearchProductsCalls()[0].Query)
+				assert.Equal(t, mapper.DefaultSearchLimit, storeMock.SearchProductsCalls()[0].Limit)
+
+				want := mapper.SearchProductsResponse{
+					Query: "mozarella",
+					Results: []mapper.ProductMatch{
+						{
+							Product:   mapper.Product{ID: testProductID, Name: "Mozzarella Sticks", Category: "Sides", Price: 5.5},
+							Match:     mapper.MatchFuzzy,
+							Score:     0.9,
+							Highlight: "Mozzarella Sticks",
+						},
+					},
+				}
+
+				actual := testhelper.PayloadAsType[mapper.SearchProductsResponse](t, got.Body)
+				assert.Equal(t, want, actual)
+			},
+		},
+		"success/highlighted": {
+			query: "?q=waffle&limit=5",
+			storeMock: &ProductStorableMock{
+				SearchProductsFunc: func(ctx context.Context, query string, limit int) ([]models.ProductMatch, error) {
+					return []models.ProductMatch{
+						{
+							Product:  models.Product{ID: testProductID, Name: "Chicken Waffle", Category: "Waffle", Price: 12.5},
+							FullText: true,
+							Score:    0.6,
+							Headline: "Chicken <mark>Waffle</mark>",
+						},
+					}, nil
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *ProductStorableMock) {
+				require.Equal(t, http.StatusOK, got.StatusCode)
+				require.Len(t, storeMock.SearchProductsCalls(), 1)
+				assert.Equal(t, 5, storeMock.SearchProductsCalls()[0].Limit)
+
+				actual := testhelper.PayloadAsType[mapper.SearchProductsResponse](t, got.Body)
+				require.Len(t, actual.Results, 1)
+				assert.Equal(t, mapper.MatchFullText, actual.Results[0].Match)
+				assert.Equal(t, "Chicken <mark>Waffle</mark>", actual.Results[0].Highlight)
+			},
+		},
+		"error/missing_query": {
+			query:     "?q=%20",
+			storeMock: &ProductStorableMock{},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *ProductStorableMock) {
+				require.Equal(t, http.StatusBadRequest, got.StatusCode)
+				require.Len(t, storeMock.SearchProductsCalls(), 0)
+
+				actual := testhelper.PayloadAsType[web.ErrorResponse](t, got.Body)
+				expectedError := testhelper.MapExpectedErrorResponse(Err400InvalidQuery)
+				assert.Equal(t, expectedError, actual)
+			},
+		},
+		"error/limit_too_large": {
+			query:     "?q=waffle&limit=51",
+			storeMock: &ProductStorableMock{},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *ProductStorableMock) {
+				require.Equal(t, http.StatusBadRequest, got.StatusCode)
+				require.Len(t, storeMock.SearchProductsCalls(), 0)
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			log := logger.NewLogger(
+				logger.WithLevel(slog.LevelDebug),
+				logger.WithFormat(logger.HandlerJSON),
+			)
+
+			svc := NewService(tc.storeMock, testAPIKey)
+			testServer := testhelper.SetupServer(svc, *log)
+
+			url := fmt.Sprintf("%s/api/v1/product/search%s", testServer.URL, tc.query)
 			res := testhelper.SendRequest[any](t, "GET", url, nil, nil)
 			t.Cleanup(func() {
 				if res.Body != nil {
@@ -187,3 +553,584 @@ func Test_API_Service_ListProducts(t *testing.T) {
 		})
 	}
 }
+
+func Test_API_Service_CreateProduct(t *testing.T) {
+	t.Parallel()
+	testCases := map[string]struct {
+		headers       map[string]string
+		body          *mapper.CreateProductRequest
+		storeMock     *ProductStorableMock
+		wantAssertion func(t *testing.T, got *http.Response, storeMock *ProductStorableMock)
+	}{
+		"success/created": {
+			headers: authHeaders,
+			body:    &mapper.CreateProductRequest{Name: "eggs", Category: "breakfast", Price: 8.99},
+			storeMock: &ProductStorableMock{
+				CreateProductFunc: func(ctx context.Context, product models.Product) (models.Product, error) {
+					product.ID = testProductID
+					return product, nil
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *ProductStorableMock) {
+				require.Equal(t, http.StatusCreated, got.StatusCode)
+				require.Len(t, storeMock.CreateProductCalls(), 1)
+
+				want := mapper.GetProductResponse{
+					ID:        testProductID,
+					Name:      "eggs",
+					Catergory: "breakfast",
+					Price:     8.99,
+				}
+
+				actual := testhelper.PayloadAsType[mapper.GetProductResponse](t, got.Body)
+				assert.Equal(t, want, actual)
+			},
+		},
+		"error/missing_api_key": {
+			body:      &mapper.CreateProductRequest{Name: "eggs", Price: 8.99},
+			storeMock: &ProductStorableMock{},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *ProductStorableMock) {
+				require.Equal(t, http.StatusUnauthorized, got.StatusCode)
+				require.Len(t, storeMock.CreateProductCalls(), 0)
+
+				actual := testhelper.PayloadAsType[web.ErrorResponse](t, got.Body)
+				expectedError := testhelper.MapExpectedErrorResponse(web.Err401Default)
+				assert.Equal(t, expectedError, actual)
+			},
+		},
+		"error/missing_name": {
+			headers:   authHeaders,
+			body:      &mapper.CreateProductRequest{Price: 8.99},
+			storeMock: &ProductStorableMock{},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *ProductStorableMock) {
+				require.Equal(t, http.StatusUnprocessableEntity, got.StatusCode)
+				require.Len(t, storeMock.CreateProductCalls(), 0)
+
+				actual := testhelper.PayloadAsType[web.ErrorResponse](t, got.Body)
+				expectedError := testhelper.MapExpectedErrorResponse(Err422Validation)
+				assert.Equal(t, expectedError, actual)
+			},
+		},
+		"error/unknown_category": {
+			headers: authHeaders,
+			body:    &mapper.CreateProductRequest{Name: "eggs", Category: "brunch", Price: 8.99},
+			storeMock: &ProductStorableMock{
+				CreateProductFunc: func(ctx context.Context, product models.Product) (models.Product, error) {
+					return models.Product{}, store.ErrCategoryNotFound
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *ProductStorableMock) {
+				require.Equal(t, http.StatusUnprocessableEntity, got.StatusCode)
+				require.Len(t, storeMock.CreateProductCalls(), 1)
+
+				actual := testhelper.PayloadAsType[web.ErrorResponse](t, got.Body)
+				expectedError := testhelper.MapExpectedErrorResponse(Err422UnknownCategory)
+				assert.Equal(t, expectedError, actual)
+			},
+		},
+		"error/invalid_category_id": {
+			headers:   authHeaders,
+			body:      &mapper.CreateProductRequest{Name: "eggs", CategoryID: "breakfast", Price: 8.99},
+			storeMock: &ProductStorableMock{},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *ProductStorableMock) {
+				require.Equal(t, http.StatusUnprocessableEntity, got.StatusCode)
+				require.Len(t, storeMock.CreateProductCalls(), 0)
+			},
+		},
+		"error/negative_price": {
+			headers:   authHeaders,
+			body:      &mapper.CreateProductRequest{Name: "eggs", Price: -1},
+			storeMock: &ProductStorableMock{},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *ProductStorableMock) {
+				require.Equal(t, http.StatusUnprocessableEntity, got.StatusCode)
+				require.Len(t, storeMock.CreateProductCalls(), 0)
+			},
+		},
+		"error/invalid_body": {
+			headers:   authHeaders,
+			storeMock: &ProductStorableMock{},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *ProductStorableMock) {
+				require.Equal(t, http.StatusBadRequest, got.StatusCode)
+
+				actual := testhelper.PayloadAsType[web.ErrorResponse](t, got.Body)
+				expectedError := testhelper.MapExpectedErrorResponse(Err400InvalidRequestBody)
+				assert.Equal(t, expectedError, actual)
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			log := logger.NewLogger(
+				logger.WithLevel(slog.LevelDebug),
+				logger.WithFormat(logger.HandlerJSON),
+			)
+
+			svc := NewService(tc.storeMock, testAPIKey)
+			testServer := testhelper.SetupServer(svc, *log)
+
+			url := fmt.Sprintf("%s/api/v1/product", testServer.URL)
+			res := testhelper.SendRequest(t, "POST", url, tc.body, tc.headers)
+			t.Cleanup(func() {
+				if res.Body != nil {
+					require.NoError(t, res.Body.Close())
+				}
+			})
+			tc.wantAssertion(t, res, tc.storeMock)
+			testServer.Close()
+		})
+	}
+}
+
+func Test_API_Service_UpdateProduct(t *testing.T) {
+	t.Parallel()
+	testCases := map[string]struct {
+		productID     string
+		body          *mapper.UpdateProductRequest
+		storeMock     *ProductStorableMock
+		wantAssertion func(t *testing.T, got *http.Response, storeMock *ProductStorableMock)
+	}{
+		"success/updated": {
+			productID: testProductID,
+			body:      &mapper.UpdateProductRequest{Name: "scrambled eggs", Price: 9.5},
+			storeMock: &ProductStorableMock{
+				UpdateProductFunc: func(ctx context.Context, product models.Product) (models.Product, error) {
+					return product, nil
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *ProductStorableMock) {
+				require.Equal(t, http.StatusOK, got.StatusCode)
+				require.Len(t, storeMock.UpdateProductCalls(), 1)
+				assert.Equal(t, models.Product{
+					ID:    testProductID,
+					Name:  "scrambled eggs",
+					Price: 9.5,
+				}, storeMock.UpdateProductCalls()[0].Product)
+			},
+		},
+		"error/invalid_product_id": {
+			productID: "invalid-uuid",
+			body:      &mapper.UpdateProductRequest{Name: "eggs", Price: 9.5},
+			storeMock: &ProductStorableMock{},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *ProductStorableMock) {
+				require.Equal(t, http.StatusBadRequest, got.StatusCode)
+				require.Len(t, storeMock.UpdateProductCalls(), 0)
+			},
+		},
+		"error/not_found": {
+			productID: testProductID,
+			body:      &mapper.UpdateProductRequest{Name: "eggs", Price: 9.5},
+			storeMock: &ProductStorableMock{
+				UpdateProductFunc: func(ctx context.Context, product models.Product) (models.Product, error) {
+					return models.Product{}, store.ErrProductNotFound
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *ProductStorableMock) {
+				require.Equal(t, http.StatusNotFound, got.StatusCode)
+
+				actual := testhelper.PayloadAsType[web.ErrorResponse](t, got.Body)
+				expectedError := testhelper.MapExpectedErrorResponse(Err404ProductNotFound)
+				assert.Equal(t, expectedError, actual)
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			log := logger.NewLogger(
+				logger.WithLevel(slog.LevelDebug),
+				logger.WithFormat(logger.HandlerJSON),
+			)
+
+			svc := NewService(tc.storeMock, testAPIKey)
+			testServer := testhelper.SetupServer(svc, *log)
+
+			url := fmt.Sprintf("%s/api/v1/product/%s", testServer.URL, tc.productID)
+			res := testhelper.SendRequest(t, "PUT", url, tc.body, authHeaders)
+			t.Cleanup(func() {
+				if res.Body != nil {
+					require.NoError(t, res.Body.Close())
+				}
+			})
+			tc.wantAssertion(t, res, tc.storeMock)
+			testServer.Close()
+		})
+	}
+}
+
+func Test_API_Service_PatchProduct(t *testing.T) {
+	t.Parallel()
+	price := float32(7.25)
+	empty := ""
+	testCases := map[string]struct {
+		body          *mapper.PatchProductRequest
+		storeMock     *ProductStorableMock
+		wantAssertion func(t *testing.T, got *http.Response, storeMock *ProductStorableMock)
+	}{
+		"success/price_only": {
+			body: &mapper.PatchProductRequest{Price: &price},
+			storeMock: &ProductStorableMock{
+				PatchProductFunc: func(ctx context.Context, id string, patch models.ProductPatch) (models.Product, error) {
+					return models.Product{ID: id, Name: "eggs", Category: "breakfast", Price: *patch.Price}, nil
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *ProductStorableMock) {
+				require.Equal(t, http.StatusOK, got.StatusCode)
+				require.Len(t, storeMock.PatchProductCalls(), 1)
+
+				patch := storeMock.PatchProductCalls()[0].Patch
+				assert.Nil(t, patch.Name)
+				assert.Nil(t, patch.Category)
+				require.NotNil(t, patch.Price)
+				assert.Equal(t, price, *patch.Price)
+
+				actual := testhelper.PayloadAsType[mapper.GetProductResponse](t, got.Body)
+				assert.Equal(t, "eggs", actual.Name)
+				assert.Equal(t, price, actual.Price)
+			},
+		},
+		"error/no_fields": {
+			body:      &mapper.PatchProductRequest{},
+			storeMock: &ProductStorableMock{},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *ProductStorableMock) {
+				require.Equal(t, http.StatusUnprocessableEntity, got.StatusCode)
+				require.Len(t, storeMock.PatchProductCalls(), 0)
+			},
+		},
+		"error/empty_name": {
+			body:      &mapper.PatchProductRequest{Name: &empty},
+			storeMock: &ProductStorableMock{},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *ProductStorableMock) {
+				require.Equal(t, http.StatusUnprocessableEntity, got.StatusCode)
+				require.Len(t, storeMock.PatchProductCalls(), 0)
+			},
+		},
+		"error/not_found": {
+			body: &mapper.PatchProductRequest{Price: &price},
+			storeMock: &ProductStorableMock{
+				PatchProductFunc: func(ctx context.Context, id string, patch models.ProductPatch) (models.Product, error) {
+					return models.Product{}, store.ErrProductNotFound
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *ProductStorableMock) {
+				require.Equal(t, http.StatusNotFound, got.StatusCode)
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			log := logger.NewLogger(
+				logger.WithLevel(slog.LevelDebug),
+				logger.WithFormat(logger.HandlerJSON),
+			)
+
+			svc := NewService(tc.storeMock, testAPIKey)
+			testServer := testhelper.SetupServer(svc, *log)
+
+			url := fmt.Sprintf("%s/api/v1/product/%s", testServer.URL, testProductID)
+			res := testhelper.SendRequest(t, "PATCH", url, tc.body, authHeaders)
+			t.Cleanup(func() {
+				if res.Body != nil {
+					require.NoError(t, res.Body.Close())
+				}
+			})
+			tc.wantAssertion(t, res, tc.storeMock)
+			testServer.Close()
+		})
+	}
+}
+
+func Test_API_Service_DeleteProduct(t *testing.T) {
+	t.Parallel()
+	testCases := map[string]struct {
+		headers       map[string]string
+		storeMock     *ProductStorableMock
+		wantAssertion func(t *testing.T, got *http.Response, storeMock *ProductStorableMock)
+	}{
+		"success/deleted": {
+			headers: authHeaders,
+			storeMock: &ProductStorableMock{
+				DeleteProductFunc: func(ctx context.Context, id string) error {
+					return nil
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *ProductStorableMock) {
+				require.Equal(t, http.StatusNoContent, got.StatusCode)
+				require.Len(t, storeMock.DeleteProductCalls(), 1)
+				assert.Equal(t, testProductID, storeMock.DeleteProductCalls()[0].ID)
+			},
+		},
+		"error/already_deleted": {
+			headers: authHeaders,
+			storeMock: &ProductStorableMock{
+				DeleteProductFunc: func(ctx context.Context, id string) error {
+					return store.ErrProductNotFound
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *ProductStorableMock) {
+				require.Equal(t, http.StatusNotFound, got.StatusCode)
+
+				actual := testhelper.PayloadAsType[web.ErrorResponse](t, got.Body)
+				expectedError := testhelper.MapExpectedErrorResponse(Err404ProductNotFound)
+				assert.Equal(t, expectedError, actual)
+			},
+		},
+		"error/wrong_api_key": {
+			headers: map[string]string{
+				"api_key": "not-the-key",
+			},
+			storeMock: &ProductStorableMock{},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *ProductStorableMock) {
+				require.Equal(t, http.StatusUnauthorized, got.StatusCode)
+				require.Len(t, storeMock.DeleteProductCalls(), 0)
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			log := logger.NewLogger(
+				logger.WithLevel(slog.LevelDebug),
+				logger.WithFormat(logger.HandlerJSON),
+			)
+
+			svc := NewService(tc.storeMock, testAPIKey)
+			testServer := testhelper.SetupServer(svc, *log)
+
+			url := fmt.Sprintf("%s/api/v1/product/%s", testServer.URL, testProductID)
+			res := testhelper.SendRequest(t, "DELETE", url, &struct{}{}, tc.headers)
+			t.Cleanup(func() {
+				if res.Body != nil {
+					require.NoError(t, res.Body.Close())
+				}
+			})
+			tc.wantAssertion(t, res, tc.storeMock)
+			testServer.Close()
+		})
+	}
+}
+
+func Test_API_Service_SetProductStock(t *testing.T) {
+	t.Parallel()
+	stock := 12
+	negative := -1
+	testCases := map[string]struct {
+		body          *mapper.SetProductStockRequest
+		headers       map[string]string
+		storeMock     *ProductStorableMock
+		wantAssertion func(t *testing.T, got *http.Response, storeMock *ProductStorableMock)
+	}{
+		"success/set": {
+			body:    &mapper.SetProductStockRequest{Stock: &stock},
+			headers: authHeaders,
+			storeMock: &ProductStorableMock{
+				SetProductStockFunc: func(ctx context.Context, id string, stock *int) (models.Product, error) {
+					return models.Product{ID: id, Name: "eggs", Price: 8.99, Stock: stock}, nil
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *ProductStorableMock) {
+				require.Equal(t, http.StatusOK, got.StatusCode)
+				require.Len(t, storeMock.SetProductStockCalls(), 1)
+				assert.Equal(t, testProductID, storeMock.SetProductStockCalls()[0].ID)
+				require.NotNil(t, storeMock.SetProductStockCalls()[0].Stock)
+				assert.Equal(t, stock, *storeMock.SetProductStockCalls()[0].Stock)
+
+				actual := testhelper.PayloadAsType[mapper.Product](t, got.Body)
+				require.NotNil(t, actual.Stock)
+				assert.Equal(t, stock, *actual.Stock)
+			},
+		},
+		"error/missing_stock": {
+			body:      &mapper.SetProductStockRequest{},
+			headers:   authHeaders,
+			storeMock: &ProductStorableMock{},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *ProductStorableMock) {
+				require.Equal(t, http.StatusUnprocessableEntity, got.StatusCode)
+				require.Len(t, storeMock.SetProductStockCalls(), 0)
+			},
+		},
+		"error/negative_stock": {
+			body:      &mapper.SetProductStockRequest{Stock: &negative},
+			headers:   authHeaders,
+			storeMock: &ProductStorableMock{},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *ProductStorableMock) {
+				require.Equal(t, http.StatusUnprocessableEntity, got.StatusCode)
+				require.Len(t, storeMock.SetProductStockCalls(), 0)
+			},
+		},
+		"error/not_found": {
+			body:    &mapper.SetProductStockRequest{Stock: &stock},
+			headers: authHeaders,
+			storeMock: &ProductStorableMock{
+				SetProductStockFunc: func(ctx context.Context, id string, stock *int) (models.Product, error) {
+					return models.Product{}, store.ErrProductNotFound
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *ProductStorableMock) {
+				require.Equal(t, http.StatusNotFound, got.StatusCode)
+			},
+		},
+		"error/missing_api_key": {
+			body:      &mapper.SetProductStockRequest{Stock: &stock},
+			storeMock: &ProductStorableMock{},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *ProductStorableMock) {
+				require.Equal(t, http.StatusUnauthorized, got.StatusCode)
+				require.Len(t, storeMock.SetProductStockCalls(), 0)
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			log := logger.NewLogger(
+				logger.WithLevel(slog.LevelDebug),
+				logger.WithFormat(logger.HandlerJSON),
+			)
+
+			svc := NewService(tc.storeMock, testAPIKey)
+			testServer := testhelper.SetupServer(svc, *log)
+
+			url := fmt.Sprintf("%s/api/v1/product/%s/stock", testServer.URL, testProductID)
+			res := testhelper.SendRequest(t, "PUT", url, tc.body, tc.headers)
+			t.Cleanup(func() {
+				if res.Body != nil {
+					require.NoError(t, res.Body.Close())
+				}
+			})
+			tc.wantAssertion(t, res, tc.storeMock)
+			testServer.Close()
+		})
+	}
+}
+
+func Test_API_Service_AdjustProductStock(t *testing.T) {
+	t.Parallel()
+	testCases := map[string]struct {
+		body          *mapper.AdjustProductStockRequest
+		storeMock     *ProductStorableMock
+		wantAssertion func(t *testing.T, got *http.Response, storeMock *ProductStorableMock)
+	}{
+		"success/restocked": {
+			body: &mapper.AdjustProductStockRequest{Delta: 5},
+			storeMock: &ProductStorableMock{
+				AdjustProductStockFunc: func(ctx context.Context, id string, delta int) (models.Product, error) {
+					stock := 7 + delta
+					return models.Product{ID: id, Name: "eggs", Price: 8.99, Stock: &stock}, nil
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *ProductStorableMock) {
+				require.Equal(t, http.StatusOK, got.StatusCode)
+				require.Len(t, storeMock.AdjustProductStockCalls(), 1)
+				assert.Equal(t, 5, storeMock.AdjustProductStockCalls()[0].Delta)
+
+				actual := testhelper.PayloadAsType[mapper.Product](t, got.Body)
+				require.NotNil(t, actual.Stock)
+				assert.Equal(t, 12, *actual.Stock)
+			},
+		},
+		"error/zero_delta": {
+			body:      &mapper.AdjustProductStockRequest{},
+			storeMock: &ProductStorableMock{},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *ProductStorableMock) {
+				require.Equal(t, http.StatusUnprocessableEntity, got.StatusCode)
+				require.Len(t, storeMock.AdjustProductStockCalls(), 0)
+			},
+		},
+		"error/insufficient_stock": {
+			body: &mapper.AdjustProductStockRequest{Delta: -10},
+			storeMock: &ProductStorableMock{
+				AdjustProductStockFunc: func(ctx context.Context, id string, delta int) (models.Product, error) {
+					return models.Product{}, store.ErrInsufficientStock
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *ProductStorableMock) {
+				require.Equal(t, http.StatusConflict, got.StatusCode)
+
+				actual := testhelper.PayloadAsType[web.ErrorResponse](t, got.Body)
+				expectedError := testhelper.MapExpectedErrorResponse(Err409InsufficientStock)
+				assert.Equal(t, expectedError, actual)
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			log := logger.NewLogger(
+				logger.WithLevel(slog.LevelDebug),
+				logger.WithFormat(logger.HandlerJSON),
+			)
+
+			svc := NewService(tc.storeMock, testAPIKey)
+			testServer := testhelper.SetupServer(svc, *log)
+
+			url := fmt.Sprintf("%s/api/v1/product/%s/stock/adjustments", testServer.URL, testProductID)
+			res := testhelper.SendRequest(t, "POST", url, tc.body, authHeaders)
+			t.Cleanup(func() {
+				if res.Body != nil {
+					require.NoError(t, res.Body.Close())
+				}
+			})
+			tc.wantAssertion(t, res, tc.storeMock)
+			testServer.Close()
+		})
+	}
+}
+
+func Test_API_Service_UntrackProductStock(t *testing.T) {
+	t.Parallel()
+	testCases := map[string]struct {
+		storeMock     *ProductStorableMock
+		wantAssertion func(t *testing.T, got *http.Response, storeMock *ProductStorableMock)
+	}{
+		"success/untracked": {
+			storeMock: &ProductStorableMock{
+				SetProductStockFunc: func(ctx context.Context, id string, stock *int) (models.Product, error) {
+					return models.Product{ID: id, Name: "eggs", Price: 8.99}, nil
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *ProductStorableMock) {
+				require.Equal(t, http.StatusOK, got.StatusCode)
+				require.Len(t, storeMock.SetProductStockCalls(), 1)
+				assert.Nil(t, storeMock.SetProductStockCalls()[0].Stock)
+
+				assert.JSONEq(t,
+					`{"id":"`+testProductID+`","name":"eggs","catergory":"","price":8.99}`,
+					testhelper.PayloadAsString(t, got.Body),
+				)
+			},
+		},
+		"error/not_found": {
+			storeMock: &ProductStorableMock{
+				SetProductStockFunc: func(ctx context.Context, id string, stock *int) (models.Product, error) {
+					return models.Product{}, store.ErrProductNotFound
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *ProductStorableMock) {
+				require.Equal(t, http.StatusNotFound, got.StatusCode)
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			log := logger.NewLogger(
+				logger.WithLevel(slog.LevelDebug),
+				logger.WithFormat(logger.HandlerJSON),
+			)
+
+			svc := NewService(tc.storeMock, testAPIKey)
+			testServer := testhelper.SetupServer(svc, *log)
+
+			url := fmt.Sprintf("%s/api/v1/product/%s/stock", testServer.URL, testProductID)
+			res := testhelper.SendRequest(t, "DELETE", url, &struct{}{}, authHeaders)
+			t.Cleanup(func() {
+				if res.Body != nil {
+					require.NoError(t, res.Body.Close())
+				}
+			})
+			tc.wantAssertion(t, res, tc.storeMock)
+			testServer.Close()
+		})
+	}
+}
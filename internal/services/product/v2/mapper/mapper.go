@@ -0,0 +1,60 @@
+package mapper
+
+import (
+	v1mapper "github.com/sgrumley/kart-challenge/internal/services/product/v1/mapper"
+	"github.com/sgrumley/kart-challenge/pkg/models"
+)
+
+type Category struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type Product struct {
+	ID             string                   `json:"id"`
+	Name           string                   `json:"name"`
+	Category       *Category                `json:"category"`
+	Price          float32                  `json:"price"`
+	Stock          *int                     `json:"stock"`
+	ModifierGroups []v1mapper.ModifierGroup `json:"modifier_groups"`
+}
+
+type ListProductsResponse struct {
+	Products   []Product           `json:"products"`
+	Pagination v1mapper.Pagination `json:"pagination"`
+}
+
+func ProductToResponse(product models.Product) Product {
+	res := Product{
+		ID:             product.ID,
+		Name:           product.Name,
+		Price:          product.Price,
+		Stock:          product.Stock,
+		ModifierGroups: []v1mapper.ModifierGroup{},
+	}
+	if groups := v1mapper.ModifierGroupsToResponse(product.ModifierGroups); groups != nil {
+		res.ModifierGroups = groups
+	}
+	if product.CategoryID != "" {
+		res.Category = &Category{
+			ID:   product.CategoryID,
+			Name: product.Category,
+		}
+	}
+	return res
+}
+
+func ListProductsToResponse(page models.ProductPage, limit int) ListProductsResponse {
+	res := ListProductsResponse{
+		Products: make([]Product, len(page.Products)),
+		Pagination: v1mapper.Pagination{
+			Limit:      limit,
+			Count:      len(page.Products),
+			NextCursor: page.NextCursor,
+		},
+	}
+	for i, p := range page.Products {
+		res.Products[i] = ProductToResponse(p)
+	}
+	return res
+}
@@ -0,0 +1,134 @@
+package v2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	v1mapper "github.com/sgrumley/kart-challenge/internal/services/product/v1/mapper"
+	"github.com/sgrumley/kart-challenge/internal/services/product/v2/mapper"
+	"github.com/sgrumley/kart-challenge/internal/store"
+	"github.com/sgrumley/kart-challenge/pkg/logger"
+	"github.com/sgrumley/kart-challenge/pkg/middleware"
+	"github.com/sgrumley/kart-challenge/pkg/models"
+	"github.com/sgrumley/kart-challenge/pkg/web"
+)
+
+//go:generate moq -out ./mocks_test.go . ProductStorable
+
+var _ ProductStorable = (*store.Store)(nil)
+
+type ProductStorable interface {
+	GetProduct(ctx context.Context, id string) (models.Product, error)
+	ListProductsPage(ctx context.Context, filter models.ProductFilter) (models.ProductPage, error)
+}
+
+// NewService builds the v2 product reads. They differ from v1 by returning the
+// category as an object and always paginating the listing. The apiKey lets admins
+// list unavailable products.
+func NewService(store ProductStorable, apiKey string) *ProductService {
+	return &ProductService{
+		store:    store,
+		apiKey:   apiKey,
+		validate: validator.New(),
+	}
+}
+
+type ProductService struct {
+	validate *validator.Validate
+	store    ProductStorable
+	apiKey   string
+}
+
+var (
+	Err400InvalidProductID = &web.Error{
+		Status:      http.StatusBadRequest,
+		Code:        "invalid_product_id",
+		Description: "Invalid ID supplied",
+	}
+
+	Err400InvalidQuery = &web.Error{
+		Status:      http.StatusBadRequest,
+		Code:        "invalid_query_parameters",
+		Description: "Invalid query parameters supplied",
+	}
+
+	Err404ProductNotFound = &web.Error{
+		Status:      http.StatusNotFound,
+		Code:        "product_not_found",
+		Description: "Product not found",
+	}
+)
+
+func (s *ProductService) GetProduct(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	productID := chi.URLParam(r, "product_id")
+	if _, err := uuid.Parse(productID); err != nil {
+		logger.Error(ctx, "invalid product id is not uuid", Err400InvalidProductID)
+		web.RespondJSONError(w, Err400InvalidProductID)
+		return
+	}
+
+	product, err := s.store.GetProduct(ctx, productID)
+	if err != nil {
+		logger.Error(ctx, "could not find product with id: "+productID, err)
+		web.RespondJSONError(w, Err404ProductNotFound)
+		return
+	}
+
+	web.Respond(w, http.StatusOK, mapper.ProductToResponse(product))
+}
+
+// ListProducts takes the same filters as v1 but always returns a page.
+func (s *ProductService) ListProducts(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	include, err := v1mapper.IncludeUnavailableFromQuery(r.URL.Query())
+	if err != nil {
+		logger.Error(ctx, "invalid query parameters", err)
+		web.RespondJSONError(w, Err400InvalidQuery)
+		return
+	}
+	if include && !middleware.HasAPIKey(r, s.apiKey) {
+		logger.Error(ctx, "unauthorized request", fmt.Errorf("include_unavailable needs the %s header", middleware.APIKeyHeader))
+		web.RespondJSONError(w, web.Err401Default)
+		return
+	}
+
+	req, err := v1mapper.ListProductsRequestFromQuery(r.URL.Query())
+	if err != nil {
+		logger.Error(ctx, "invalid query parameters", err)
+		web.RespondJSONError(w, Err400InvalidQuery)
+		return
+	}
+
+	if err := s.validate.Struct(req); err != nil {
+		logger.Error(ctx, "validation failed", err)
+		web.RespondJSONError(w, Err400InvalidQuery)
+		return
+	}
+
+	filter := v1mapper.ProductFilterFromRequest(req)
+	if !include {
+		filter.AvailableAt = time.Now()
+	}
+
+	page, err := s.store.ListProductsPage(ctx, filter)
+	if err != nil {
+		if errors.Is(err, store.ErrInvalidCursor) {
+			logger.Error(ctx, "invalid cursor", err)
+			web.RespondJSONError(w, Err400InvalidQuery)
+			return
+		}
+		logger.Error(ctx, "failed listing products in store", err)
+		web.RespondJSONError(w, fmt.Errorf("failed listing products in store: %w", err))
+		return
+	}
+
+	web.Respond(w, http.StatusOK, mapper.ListProductsToResponse(page, req.Limit))
+}
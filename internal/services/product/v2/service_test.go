@@ -0,0 +1,270 @@
+package v2
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	v1mapper "github.com/sgrumley/kart-challenge/internal/services/product/v1/mapper"
+	"github.com/sgrumley/kart-challenge/internal/services/product/v2/mapper"
+	"github.com/sgrumley/kart-challenge/pkg/logger"
+	"github.com/sgrumley/kart-challenge/pkg/models"
+	"github.com/sgrumley/kart-challenge/pkg/testhelper"
+	"github.com/sgrumley/kart-challenge/pkg/web"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testAPIKey     = "a-secret-key"
+	testProductID  = "00000000-0000-0000-0000-000000000001"
+	testCategoryID = "10000000-0000-0000-0000-000000000001"
+	testGroupID    = "20000000-0000-0000-0000-000000000001"
+	testModifierID = "30000000-0000-0000-0000-000000000001"
+)
+
+var authHeaders = map[string]string{
+	"api_key": testAPIKey,
+}
+
+func Test_API_Service_GetProduct(t *testing.T) {
+	t.Parallel()
+	stock := 5
+	testCases := map[string]struct {
+		productID     string
+		storeMock     *ProductStorableMock
+		wantAssertion func(t *testing.T, got *http.Response, storeMock *ProductStorableMock)
+	}{
+		"success/with_category": {
+			productID: testProductID,
+			storeMock: &ProductStorableMock{
+				GetProductFunc: func(ctx context.Context, id string) (models.Product, error) {
+					return models.Product{
+						ID:         testProductID,
+						Name:       "eggs",
+						Category:   "breakfast",
+						CategoryID: testCategoryID,
+						Price:      8.99,
+						Stock:      &stock,
+					}, nil
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *ProductStorableMock) {
+				require.Equal(t, http.StatusOK, got.StatusCode)
+				require.Len(t, storeMock.GetProductCalls(), 1)
+
+				want := mapper.Product{
+					ID:   testProductID,
+					Name: "eggs",
+					Category: &mapper.Category{
+						ID:   testCategoryID,
+						Name: "breakfast",
+					},
+					Price:          8.99,
+					Stock:          &stock,
+					ModifierGroups: []v1mapper.ModifierGroup{},
+				}
+
+				actual := testhelper.PayloadAsType[mapper.Product](t, got.Body)
+				assert.Equal(t, want, actual)
+			},
+		},
+		"success/without_category": {
+			productID: testProductID,
+			storeMock: &ProductStorableMock{
+				GetProductFunc: func(ctx context.Context, id string) (models.Product, error) {
+					return models.Product{ID: testProductID, Name: "eggs", Price: 8.99}, nil
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *ProductStorableMock) {
+				require.Equal(t, http.StatusOK, got.StatusCode)
+				assert.JSONEq(t,
+					`{"id":"`+testProductID+`","name":"eggs","category":null,"price":8.99,"stock":null,"modifier_groups":[]}`,
+					testhelper.PayloadAsString(t, got.Body),
+				)
+			},
+		},
+		"success/with_modifier_groups": {
+			productID: testProductID,
+			storeMock: &ProductStorableMock{
+				GetProductFunc: func(ctx context.Context, id string) (models.Product, error) {
+					return models.Product{
+						ID:    testProductID,
+						Name:  "burger",
+						Price: 15.99,
+						ModifierGroups: []models.ModifierGroup{
+							{
+								ID:        testGroupID,
+								Name:      "Extras",
+								MinSelect: 0,
+								MaxSelect: 2,
+								Modifiers: []models.Modifier{
+									{ID: testModifierID, Name: "Bacon", PriceDelta: 2.5},
+								},
+							},
+						},
+					}, nil
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *ProductStorableMock) {
+				require.Equal(t, http.StatusOK, got.StatusCode)
+				assert.JSONEq(t,
+					`{"id":"`+testProductID+`","name":"burger","category":null,"price":15.99,"stock":null,"modifier_groups":[`+
+						`{"id":"`+testGroupID+`","name":"Extras","min_select":0,"max_select":2,"modifiers":[`+
+						`{"id":"`+testModifierID+`","name":"Bacon","price_delta":2.5}]}]}`,
+					testhelper.PayloadAsString(t, got.Body),
+				)
+			},
+		},
+		"error/invalid_product_id": {
+			productID: "invalid-uuid",
+			storeMock: &ProductStorableMock{},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *ProductStorableMock) {
+				require.Equal(t, http.StatusBadRequest, got.StatusCode)
+				require.Len(t, storeMock.GetProductCalls(), 0)
+
+				actual := testhelper.PayloadAsType[web.ErrorResponse](t, got.Body)
+				expectedError := testhelper.MapExpectedErrorResponse(Err400InvalidProductID)
+				assert.Equal(t, expectedError, actual)
+			},
+		},
+		"error/store_not_found": {
+			productID: testProductID,
+			storeMock: &ProductStorableMock{
+				GetProductFunc: func(ctx context.Context, id string) (models.Product, error) {
+					return models.Product{}, fmt.Errorf("error")
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *ProductStorableMock) {
+				require.Equal(t, http.StatusNotFound, got.StatusCode)
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			log := logger.NewLogger(
+				logger.WithLevel(slog.LevelDebug),
+				logger.WithFormat(logger.HandlerJSON),
+			)
+
+			svc := NewService(tc.storeMock, testAPIKey)
+			testServer := testhelper.SetupVersionedServer(svc, *log, "v2")
+
+			url := fmt.Sprintf("%s/api/v2/product/%s", testServer.URL, tc.productID)
+			res := testhelper.SendRequest[any](t, "GET", url, nil, nil)
+			t.Cleanup(func() {
+				if res.Body != nil {
+					require.NoError(t, res.Body.Close())
+				}
+			})
+			tc.wantAssertion(t, res, tc.storeMock)
+			testServer.Close()
+		})
+	}
+}
+
+func Test_API_Service_ListProducts(t *testing.T) {
+	t.Parallel()
+	testCases := map[string]struct {
+		query         string
+		headers       map[string]string
+		storeMock     *ProductStorableMock
+		wantAssertion func(t *testing.T, got *http.Response, storeMock *ProductStorableMock)
+	}{
+		"success/always_paged": {
+			storeMock: &ProductStorableMock{
+				ListProductsPageFunc: func(ctx context.Context, filter models.ProductFilter) (models.ProductPage, error) {
+					return models.ProductPage{
+						Products: []models.Product{
+							{ID: testProductID, Name: "eggs", Category: "breakfast", CategoryID: testCategoryID, Price: 8.99},
+						},
+					}, nil
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *ProductStorableMock) {
+				require.Equal(t, http.StatusOK, got.StatusCode)
+				require.Len(t, storeMock.ListProductsPageCalls(), 1)
+				assert.Equal(t, v1mapper.DefaultListLimit, storeMock.ListProductsPageCalls()[0].Filter.Limit)
+				assert.False(t, storeMock.ListProductsPageCalls()[0].Filter.AvailableAt.IsZero())
+
+				want := mapper.ListProductsResponse{
+					Products: []mapper.Product{
+						{
+							ID:             testProductID,
+							Name:           "eggs",
+							Category:       &mapper.Category{ID: testCategoryID, Name: "breakfast"},
+							Price:          8.99,
+							ModifierGroups: []v1mapper.ModifierGroup{},
+						},
+					},
+					Pagination: v1mapper.Pagination{
+						Limit: v1mapper.DefaultListLimit,
+						Count: 1,
+					},
+				}
+
+				actual := testhelper.PayloadAsType[mapper.ListProductsResponse](t, got.Body)
+				assert.Equal(t, want, actual)
+			},
+		},
+		"success/include_unavailable_for_admin": {
+			query:   "?include_unavailable=true",
+			headers: authHeaders,
+			storeMock: &ProductStorableMock{
+				ListProductsPageFunc: func(ctx context.Context, filter models.ProductFilter) (models.ProductPage, error) {
+					return models.ProductPage{}, nil
+				},
+			},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *ProductStorableMock) {
+				require.Equal(t, http.StatusOK, got.StatusCode)
+				require.Len(t, storeMock.ListProductsPageCalls(), 1)
+				assert.True(t, storeMock.ListProductsPageCalls()[0].Filter.AvailableAt.IsZero())
+			},
+		},
+		"error/include_unavailable_without_api_key": {
+			query:     "?include_unavailable=true",
+			storeMock: &ProductStorableMock{},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *ProductStorableMock) {
+				require.Equal(t, http.StatusUnauthorized, got.StatusCode)
+				require.Len(t, storeMock.ListProductsPageCalls(), 0)
+			},
+		},
+		"error/invalid_limit": {
+			query:     "?limit=0",
+			storeMock: &ProductStorableMock{},
+			wantAssertion: func(t *testing.T, got *http.Response, storeMock *ProductStorableMock) {
+				require.Equal(t, http.StatusBadRequest, got.StatusCode)
+				require.Len(t, storeMock.ListProductsPageCalls(), 0)
+
+				actual := testhelper.PayloadAsType[web.ErrorResponse](t, got.Body)
+				expectedError := testhelper.MapExpectedErrorResponse(Err400InvalidQuery)
+				assert.Equal(t, expectedError, actual)
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			log := logger.NewLogger(
+				logger.WithLevel(slog.LevelDebug),
+				logger.WithFormat(logger.HandlerJSON),
+			)
+
+			svc := NewService(tc.storeMock, testAPIKey)
+			testServer := testhelper.SetupVersionedServer(svc, *log, "v2")
+
+			url := fmt.Sprintf("%s/api/v2/product%s", testServer.URL, tc.query)
+			res := testhelper.SendRequest[any](t, "GET", url, nil, tc.headers)
+			t.Cleanup(func() {
+				if res.Body != nil {
+					require.NoError(t, res.Body.Close())
+				}
+			})
+			tc.wantAssertion(t, res, tc.storeMock)
+			testServer.Close()
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sgrumley/kart-challenge/internal/store/dbgen"
+)
+
+// menuTime converts at to the day of the week and minute of the day that
+// availability windows are written in.
+func (s *Store) menuTime(at time.Time) (day, minute int32) {
+	loc := s.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	local := at.In(loc)
+	return int32(local.Weekday()), int32(local.Hour()*60 + local.Minute())
+}
+
+// availableAt is the day and minute products must be available at to be listed,
+// both are null when at is zero so every product is listed.
+func (s *Store) availableAt(at time.Time) (day, minute sql.NullInt32) {
+	if at.IsZero() {
+		return sql.NullInt32{}, sql.NullInt32{}
+	}
+	d, m := s.menuTime(at)
+	return sql.NullInt32{Int32: d, Valid: true}, sql.NullInt32{Int32: m, Valid: true}
+}
+
+// UnavailableProducts returns the ids of the products that cannot be ordered at
+// the given time because it falls outside their availability windows.
+func (s *Store) UnavailableProducts(ctx context.Context, ids []string, at time.Time) ([]string, error) {
+	uids := make([]uuid.UUID, len(ids))
+	for i, id := range ids {
+		uid, err := uuid.Parse(id)
+		if err != nil {
+			return nil, fmt.Errorf("product id %s was not uuid: %w", id, err)
+		}
+		uids[i] = uid
+	}
+
+	day, minute := s.menuTime(at)
+	unavailable, err := s.Queries.ListUnavailableProductIDs(ctx, dbgen.ListUnavailableProductIDsParams{
+		Ids:             uids,
+		AvailableDay:    day,
+		AvailableMinute: minute,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to check product availability: %w", err)
+	}
+
+	res := make([]string, len(unavailable))
+	for i, id := range unavailable {
+		res[i] = id.String()
+	}
+	return res, nil
+}
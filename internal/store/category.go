@@ -0,0 +1,66 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/sgrumley/kart-challenge/internal/store/dbgen"
+	"github.com/sgrumley/kart-challenge/pkg/models"
+)
+
+// ListCategories returns every category in display order, children are linked
+// to their parent through ParentID.
+func (s *Store) ListCategories(ctx context.Context) ([]models.Category, error) {
+	categories, err := s.Queries.ListCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]models.Category, len(categories))
+	for i, c := range categories {
+		res[i] = CategoryFromDB(c)
+	}
+	return res, nil
+}
+
+func CategoryFromDB(c dbgen.Category) models.Category {
+	return models.Category{
+		ID:           c.ID.String(),
+		Name:         c.Name,
+		ParentID:     nullUUIDString(c.ParentID),
+		DisplayOrder: int(c.DisplayOrder),
+	}
+}
+
+// resolveCategory finds the category a product is filed under, by id when given
+// and otherwise by name for clients that only know the name. Both are empty when
+// the product has no category.
+func resolveCategory(ctx context.Context, q *dbgen.Queries, id, name string) (uuid.NullUUID, sql.NullString, error) {
+	var (
+		category dbgen.Category
+		err      error
+	)
+	switch {
+	case id != "":
+		uid, parseErr := uuid.Parse(id)
+		if parseErr != nil {
+			return uuid.NullUUID{}, sql.NullString{}, fmt.Errorf("%w: %w", ErrCategoryNotFound, parseErr)
+		}
+		category, err = q.GetCategory(ctx, uid)
+	case name != "":
+		category, err = q.GetCategoryByName(ctx, name)
+	default:
+		return uuid.NullUUID{}, sql.NullString{}, nil
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return uuid.NullUUID{}, sql.NullString{}, ErrCategoryNotFound
+	}
+	if err != nil {
+		return uuid.NullUUID{}, sql.NullString{}, err
+	}
+
+	return uuid.NullUUID{UUID: category.ID, Valid: true}, sql.NullString{String: category.Name, Valid: true}, nil
+}
@@ -0,0 +1,271 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/bits"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sgrumley/kart-challenge/internal/couponindex"
+	"github.com/sgrumley/kart-challenge/internal/store/dbgen"
+	"github.com/sgrumley/kart-challenge/pkg/models"
+)
+
+// redeemCoupon records the coupon against the order once its limits have been
+// checked. The limits row is locked for the rest of the transaction so concurrent
+// orders for the same coupon are counted one after the other.
+func redeemCoupon(ctx context.Context, qtx *dbgen.Queries, order models.Order, orderID uuid.UUID, createdAt int64) error {
+	customerID := sql.NullString{String: order.CustomerID, Valid: order.CustomerID != ""}
+
+	limits, err := qtx.GetCouponLimitsForUpdate(ctx, order.CouponCode)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		// coupons without limits can be redeemed any number of times
+
+	case err != nil:
+		return fmt.Errorf("failed to load coupon limits: %w", err)
+
+	default:
+		if err := checkCouponLimits(ctx, qtx, limits, customerID, time.Now()); err != nil {
+			return err
+		}
+	}
+
+	_, err = qtx.AddCouponRedemption(ctx, dbgen.AddCouponRedemptionParams{
+		ID:         GenerateUUIDv4(),
+		CouponCode: order.CouponCode,
+		OrderID:    orderID,
+		CreatedAt:  createdAt,
+		CustomerID: customerID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to redeem coupon: %w", err)
+	}
+
+	return nil
+}
+
+func checkCouponLimits(ctx context.Context, qtx *dbgen.Queries, limits dbgen.CouponLimit, customerID sql.NullString, now time.Time) error {
+	if limits.ValidFrom.Valid && now.Before(limits.ValidFrom.Time) {
+		return ErrCouponNotYetActive
+	}
+
+	if limits.ValidUntil.Valid && !now.Before(limits.ValidUntil.Time) {
+		return ErrCouponExpired
+	}
+
+	if limits.MaxRedemptions.Valid {
+		redeemed, err := qtx.CountActiveCouponRedemptions(ctx, limits.CouponCode)
+		if err != nil {
+			return fmt.Errorf("failed to count coupon redemptions: %w", err)
+		}
+		if redeemed >= int64(limits.MaxRedemptions.Int32) {
+			return ErrCouponExhausted
+		}
+	}
+
+	// anonymous orders have no verified customer to count against, only the total
+	// redemptions limit them
+	if limits.PerCustomerLimit.Valid && customerID.Valid {
+		redeemed, err := qtx.CountActiveCustomerCouponRedemptions(ctx, dbgen.CountActiveCustomerCouponRedemptionsParams{
+			CouponCode: limits.CouponCode,
+			CustomerID: customerID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to count customer coupon redemptions: %w", err)
+		}
+		if redeemed >= int64(limits.PerCustomerLimit.Int32) {
+			return ErrCouponExhausted
+		}
+	}
+
+	return nil
+}
+
+// scanPageSize is the number of coupons fetched per page by ScanCoupons.
+const scanPageSize = 100_000
+
+// ScanCoupons walks every enabled coupon in key order and hands it to fn.
+func (s *Store) ScanCoupons(ctx context.Context, fn func(couponindex.Entry) error) error {
+	after := ""
+	for {
+		coupons, err := s.Queries.ListCouponsAfter(ctx, dbgen.ListCouponsAfterParams{
+			After:    after,
+			RowLimit: scanPageSize,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to list coupons: %w", err)
+		}
+
+		for _, c := range coupons {
+			// disabled codes are left out so the index reports them invalid
+			if c.DisabledAt.Valid {
+				continue
+			}
+			entry := couponindex.Entry{
+				Code:    c.Code,
+				Sources: uint32(c.Sources),
+				Manual:  c.Manual,
+			}
+			if err := fn(entry); err != nil {
+				return err
+			}
+		}
+
+		if len(coupons) < scanPageSize {
+			return nil
+		}
+		after = coupons[len(coupons)-1].Code
+	}
+}
+
+// GetCoupon returns the coupon with its sources and discount rules.
+func (s *Store) GetCoupon(ctx context.Context, code string) (models.Coupon, error) {
+	c, err := s.Queries.GetCoupon(ctx, code)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Coupon{}, ErrCouponNotFound
+	}
+	if err != nil {
+		return models.Coupon{}, err
+	}
+
+	rules, err := s.Queries.ListCouponRules(ctx, code)
+	if err != nil {
+		return models.Coupon{}, err
+	}
+
+	coupon := CouponFromDB(c)
+	coupon.Rules = CouponRulesFromDB(rules)
+	return coupon, nil
+}
+
+// CreateCoupon adds a manual coupon, valid without appearing in any file.
+func (s *Store) CreateCoupon(ctx context.Context, code string) (models.Coupon, error) {
+	c, err := s.Queries.CreateCoupon(ctx, code)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Coupon{}, ErrCouponExists
+	}
+	if err != nil {
+		return models.Coupon{}, err
+	}
+
+	coupon := CouponFromDB(c)
+	s.overrideCouponIndex(coupon)
+	return coupon, nil
+}
+
+// SetCouponDisabled disables or re-enables a coupon. Disabled coupons fail
+// CheckCoupon whatever files they appear in.
+func (s *Store) SetCouponDisabled(ctx context.Context, code string, disabled bool) (models.Coupon, error) {
+	disabledAt := sql.NullInt64{}
+	if disabled {
+		disabledAt = sql.NullInt64{Int64: int64(TimeStampNow()), Valid: true}
+	}
+
+	c, err := s.Queries.SetCouponDisabledAt(ctx, dbgen.SetCouponDisabledAtParams{
+		DisabledAt: disabledAt,
+		Code:       code,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Coupon{}, ErrCouponNotFound
+	}
+	if err != nil {
+		return models.Coupon{}, err
+	}
+
+	rules, err := s.Queries.ListCouponRules(ctx, code)
+	if err != nil {
+		return models.Coupon{}, err
+	}
+
+	coupon := CouponFromDB(c)
+	coupon.Rules = CouponRulesFromDB(rules)
+	s.overrideCouponIndex(coupon)
+	return coupon, nil
+}
+
+func (s *Store) AddCouponRule(ctx context.Context, rule models.CouponRule) (models.CouponRule, error) {
+	if _, err := s.Queries.GetCoupon(ctx, rule.CouponCode); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.CouponRule{}, ErrCouponNotFound
+		}
+		return models.CouponRule{}, err
+	}
+
+	r, err := s.Queries.AddCouponRule(ctx, dbgen.AddCouponRuleParams{
+		ID:         GenerateUUIDv4(),
+		CouponCode: rule.CouponCode,
+		Kind:       string(rule.Kind),
+		Value:      float64(rule.Value),
+		Category:   sql.NullString{String: rule.Category, Valid: rule.Category != ""},
+		MinBasket:  float64(rule.MinBasket),
+		CreatedAt:  int64(TimeStampNow()),
+	})
+	if err != nil {
+		return models.CouponRule{}, fmt.Errorf("failed to add coupon rule: %w", err)
+	}
+
+	return CouponRulesFromDB([]dbgen.CouponRule{r})[0], nil
+}
+
+// ListCouponRedemptions returns the most recent redemptions of a coupon first.
+func (s *Store) ListCouponRedemptions(ctx context.Context, code string, limit int) ([]models.CouponRedemption, error) {
+	if _, err := s.Queries.GetCoupon(ctx, code); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrCouponNotFound
+		}
+		return nil, err
+	}
+
+	redemptions, err := s.Queries.ListCouponRedemptions(ctx, dbgen.ListCouponRedemptionsParams{
+		CouponCode: code,
+		RowLimit:   int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]models.CouponRedemption, len(redemptions))
+	for i, r := range redemptions {
+		res[i] = models.CouponRedemption{
+			ID:         r.ID.String(),
+			CouponCode: r.CouponCode,
+			OrderID:    r.OrderID.String(),
+			CustomerID: r.CustomerID.String,
+			CreatedAt:  r.CreatedAt,
+			ReleasedAt: r.ReleasedAt.Int64,
+		}
+	}
+	return res, nil
+}
+
+func CouponFromDB(c dbgen.Coupon) models.Coupon {
+	sources := make([]int, 0, bits.OnesCount32(uint32(c.Sources)))
+	for n := 1; n <= 32; n++ {
+		if c.Sources&(1<<(n-1)) != 0 {
+			sources = append(sources, n)
+		}
+	}
+
+	disabled := c.DisabledAt.Valid
+	return models.Coupon{
+		Code:       c.Code,
+		Sources:    sources,
+		Manual:     c.Manual,
+		Disabled:   disabled,
+		DisabledAt: c.DisabledAt.Int64,
+		Valid:      !disabled && (c.Manual || len(sources) >= couponindex.MinSources),
+		Rules:      []models.CouponRule{},
+	}
+}
+
+// overrideCouponIndex lets the in-memory index see a coupon change before the
+// next reload.
+func (s *Store) overrideCouponIndex(coupon models.Coupon) {
+	if s.CouponIndex != nil {
+		s.CouponIndex.Override(coupon.Code, coupon.Valid)
+	}
+}
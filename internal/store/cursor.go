@@ -0,0 +1,67 @@
+package store
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// EncodeCursor returns an opaque cursor pointing at the row with the given sort key.
+func EncodeCursor(createdAt int64, id uuid.UUID) string {
+	raw := fmt.Sprintf("%d:%s", createdAt, id.String())
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func DecodeCursor(cursor string) (int64, uuid.UUID, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, uuid.UUID{}, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+
+	createdAtStr, idStr, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return 0, uuid.UUID{}, ErrInvalidCursor
+	}
+
+	createdAt, err := strconv.ParseInt(createdAtStr, 10, 64)
+	if err != nil {
+		return 0, uuid.UUID{}, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return 0, uuid.UUID{}, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+
+	return createdAt, id, nil
+}
+
+// EncodeProductCursor returns an opaque cursor pointing at the product with the
+// given sort key. The sort is kept so a cursor cannot be reused with another one.
+func EncodeProductCursor(sort string, key string, id uuid.UUID) string {
+	raw := fmt.Sprintf("%s:%s:%s", sort, id.String(), key)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func DecodeProductCursor(cursor string) (string, string, uuid.UUID, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", "", uuid.UUID{}, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+
+	// the key goes last as a product name may contain the separator
+	parts := strings.SplitN(string(raw), ":", 3)
+	if len(parts) != 3 {
+		return "", "", uuid.UUID{}, ErrInvalidCursor
+	}
+
+	id, err := uuid.Parse(parts[1])
+	if err != nil {
+		return "", "", uuid.UUID{}, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+
+	return parts[0], parts[2], id, nil
+}
@@ -7,17 +7,392 @@ package dbgen
 
 import (
 	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
 )
 
-const getCouponByID = `-- name: GetCouponByID :one
-SELECT id 
+const addCouponRedemption = `-- name: AddCouponRedemption :one
+INSERT INTO coupon_redemptions (
+    id,
+    coupon_code,
+    order_id,
+    created_at,
+    customer_id
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+) RETURNING id, coupon_code, order_id, created_at, released_at, customer_id
+`
+
+type AddCouponRedemptionParams struct {
+	ID         uuid.UUID
+	CouponCode string
+	OrderID    uuid.UUID
+	CreatedAt  int64
+	CustomerID sql.NullString
+}
+
+func (q *Queries) AddCouponRedemption(ctx context.Context, arg AddCouponRedemptionParams) (CouponRedemption, error) {
+	row := q.db.QueryRowContext(ctx, addCouponRedemption,
+		arg.ID,
+		arg.CouponCode,
+		arg.OrderID,
+		arg.CreatedAt,
+		arg.CustomerID,
+	)
+	var i CouponRedemption
+	err := row.Scan(
+		&i.ID,
+		&i.CouponCode,
+		&i.OrderID,
+		&i.CreatedAt,
+		&i.ReleasedAt,
+		&i.CustomerID,
+	)
+	return i, err
+}
+
+const addCouponRule = `-- name: AddCouponRule :one
+INSERT INTO coupon_rules (
+    id,
+    coupon_code,
+    kind,
+    value,
+    category,
+    min_basket,
+    created_at
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7
+) RETURNING id, coupon_code, kind, value, category, min_basket, created_at
+`
+
+type AddCouponRuleParams struct {
+	ID         uuid.UUID
+	CouponCode string
+	Kind       string
+	Value      float64
+	Category   sql.NullString
+	MinBasket  float64
+	CreatedAt  int64
+}
+
+func (q *Queries) AddCouponRule(ctx context.Context, arg AddCouponRuleParams) (CouponRule, error) {
+	row := q.db.QueryRowContext(ctx, addCouponRule,
+		arg.ID,
+		arg.CouponCode,
+		arg.Kind,
+		arg.Value,
+		arg.Category,
+		arg.MinBasket,
+		arg.CreatedAt,
+	)
+	var i CouponRule
+	err := row.Scan(
+		&i.ID,
+		&i.CouponCode,
+		&i.Kind,
+		&i.Value,
+		&i.Category,
+		&i.MinBasket,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const checkCouponSources = `-- name: CheckCouponSources :one
+SELECT EXISTS (
+    SELECT 1
+    FROM coupons
+    WHERE code = $1
+      AND disabled_at IS NULL
+      AND (manual OR bit_count(sources::bit(32)) >= $2::INTEGER)
+)
+`
+
+type CheckCouponSourcesParams struct {
+	Code       string
+	MinSources int32
+}
+
+// Check a coupon code is enabled and either manual or in at least min_sources of the coupon files
+func (q *Queries) CheckCouponSources(ctx context.Context, arg CheckCouponSourcesParams) (bool, error) {
+	row := q.db.QueryRowContext(ctx, checkCouponSources, arg.Code, arg.MinSources)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const countActiveCouponRedemptions = `-- name: CountActiveCouponRedemptions :one
+SELECT COUNT(*)
+FROM coupon_redemptions
+WHERE coupon_code = $1 AND released_at IS NULL
+`
+
+func (q *Queries) CountActiveCouponRedemptions(ctx context.Context, couponCode string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countActiveCouponRedemptions, couponCode)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countActiveCustomerCouponRedemptions = `-- name: CountActiveCustomerCouponRedemptions :one
+SELECT COUNT(*)
+FROM coupon_redemptions
+WHERE coupon_code = $1 AND customer_id = $2 AND released_at IS NULL
+`
+
+type CountActiveCustomerCouponRedemptionsParams struct {
+	CouponCode string
+	CustomerID sql.NullString
+}
+
+func (q *Queries) CountActiveCustomerCouponRedemptions(ctx context.Context, arg CountActiveCustomerCouponRedemptionsParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countActiveCustomerCouponRedemptions, arg.CouponCode, arg.CustomerID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createCoupon = `-- name: CreateCoupon :one
+INSERT INTO coupons (
+    code,
+    sources,
+    manual
+) VALUES (
+    $1,
+    0,
+    true
+) ON CONFLICT (code) DO NOTHING
+RETURNING code, sources, manual, disabled_at
+`
+
+// Create a manual coupon, returns no rows when the code already exists
+func (q *Queries) CreateCoupon(ctx context.Context, code string) (Coupon, error) {
+	row := q.db.QueryRowContext(ctx, createCoupon, code)
+	var i Coupon
+	err := row.Scan(
+		&i.Code,
+		&i.Sources,
+		&i.Manual,
+		&i.DisabledAt,
+	)
+	return i, err
+}
+
+const getCoupon = `-- name: GetCoupon :one
+SELECT code, sources, manual, disabled_at
 FROM coupons
-WHERE id = $1
+WHERE code = $1
 `
 
-// Get Coupon by ID
-func (q *Queries) GetCouponByID(ctx context.Context, id string) (string, error) {
-	row := q.db.QueryRowContext(ctx, getCouponByID, id)
-	err := row.Scan(&id)
-	return id, err
+func (q *Queries) GetCoupon(ctx context.Context, code string) (Coupon, error) {
+	row := q.db.QueryRowContext(ctx, getCoupon, code)
+	var i Coupon
+	err := row.Scan(
+		&i.Code,
+		&i.Sources,
+		&i.Manual,
+		&i.DisabledAt,
+	)
+	return i, err
+}
+
+const getCouponLimitsForUpdate = `-- name: GetCouponLimitsForUpdate :one
+SELECT coupon_code, max_redemptions, per_customer_limit, valid_from, valid_until
+FROM coupon_limits
+WHERE coupon_code = $1
+FOR UPDATE
+`
+
+// Lock the limits of a coupon until the surrounding transaction ends
+func (q *Queries) GetCouponLimitsForUpdate(ctx context.Context, couponCode string) (CouponLimit, error) {
+	row := q.db.QueryRowContext(ctx, getCouponLimitsForUpdate, couponCode)
+	var i CouponLimit
+	err := row.Scan(
+		&i.CouponCode,
+		&i.MaxRedemptions,
+		&i.PerCustomerLimit,
+		&i.ValidFrom,
+		&i.ValidUntil,
+	)
+	return i, err
+}
+
+const listCouponRedemptions = `-- name: ListCouponRedemptions :many
+SELECT id, coupon_code, order_id, created_at, released_at, customer_id
+FROM coupon_redemptions
+WHERE coupon_code = $1
+ORDER BY created_at DESC, id
+LIMIT $2
+`
+
+type ListCouponRedemptionsParams struct {
+	CouponCode string
+	RowLimit   int32
+}
+
+// List the most recent redemptions of a coupon code, released ones included
+func (q *Queries) ListCouponRedemptions(ctx context.Context, arg ListCouponRedemptionsParams) ([]CouponRedemption, error) {
+	rows, err := q.db.QueryContext(ctx, listCouponRedemptions, arg.CouponCode, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CouponRedemption
+	for rows.Next() {
+		var i CouponRedemption
+		if err := rows.Scan(
+			&i.ID,
+			&i.CouponCode,
+			&i.OrderID,
+			&i.CreatedAt,
+			&i.ReleasedAt,
+			&i.CustomerID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCouponRules = `-- name: ListCouponRules :many
+SELECT id, coupon_code, kind, value, category, min_basket, created_at
+FROM coupon_rules
+WHERE coupon_code = $1
+ORDER BY created_at, id
+`
+
+// List the discount rules attached to a coupon code
+func (q *Queries) ListCouponRules(ctx context.Context, couponCode string) ([]CouponRule, error) {
+	rows, err := q.db.QueryContext(ctx, listCouponRules, couponCode)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CouponRule
+	for rows.Next() {
+		var i CouponRule
+		if err := rows.Scan(
+			&i.ID,
+			&i.CouponCode,
+			&i.Kind,
+			&i.Value,
+			&i.Category,
+			&i.MinBasket,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCouponsAfter = `-- name: ListCouponsAfter :many
+SELECT code, sources, manual, disabled_at
+FROM coupons
+WHERE code > $1
+ORDER BY code
+LIMIT $2
+`
+
+type ListCouponsAfterParams struct {
+	After    string
+	RowLimit int32
+}
+
+// Page through coupon codes in key order, used to build the in-memory coupon index
+func (q *Queries) ListCouponsAfter(ctx context.Context, arg ListCouponsAfterParams) ([]Coupon, error) {
+	rows, err := q.db.QueryContext(ctx, listCouponsAfter, arg.After, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Coupon
+	for rows.Next() {
+		var i Coupon
+		if err := rows.Scan(
+			&i.Code,
+			&i.Sources,
+			&i.Manual,
+			&i.DisabledAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const releaseCouponRedemptions = `-- name: ReleaseCouponRedemptions :execrows
+UPDATE coupon_redemptions
+SET released_at = $1
+WHERE order_id = $2 AND released_at IS NULL
+`
+
+type ReleaseCouponRedemptionsParams struct {
+	ReleasedAt sql.NullInt64
+	OrderID    uuid.UUID
+}
+
+// Release the coupon redemptions held by an Order
+func (q *Queries) ReleaseCouponRedemptions(ctx context.Context, arg ReleaseCouponRedemptionsParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, releaseCouponRedemptions, arg.ReleasedAt, arg.OrderID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setCouponDisabledAt = `-- name: SetCouponDisabledAt :one
+UPDATE coupons
+SET disabled_at = $1
+WHERE code = $2
+RETURNING code, sources, manual, disabled_at
+`
+
+type SetCouponDisabledAtParams struct {
+	DisabledAt sql.NullInt64
+	Code       string
+}
+
+func (q *Queries) SetCouponDisabledAt(ctx context.Context, arg SetCouponDisabledAtParams) (Coupon, error) {
+	row := q.db.QueryRowContext(ctx, setCouponDisabledAt, arg.DisabledAt, arg.Code)
+	var i Coupon
+	err := row.Scan(
+		&i.Code,
+		&i.Sources,
+		&i.Manual,
+		&i.DisabledAt,
+	)
+	return i, err
 }
@@ -10,26 +10,111 @@ import (
 	"github.com/google/uuid"
 )
 
+type Category struct {
+	ID           uuid.UUID
+	Name         string
+	ParentID     uuid.NullUUID
+	DisplayOrder int32
+	CreatedAt    int64
+}
+
 type Coupon struct {
-	ID string
+	Code       string
+	Sources    int32
+	Manual     bool
+	DisabledAt sql.NullInt64
+}
+
+type CouponLimit struct {
+	CouponCode       string
+	MaxRedemptions   sql.NullInt32
+	PerCustomerLimit sql.NullInt32
+	ValidFrom        sql.NullTime
+	ValidUntil       sql.NullTime
+}
+
+type CouponRedemption struct {
+	ID         uuid.UUID
+	CouponCode string
+	OrderID    uuid.UUID
+	CreatedAt  int64
+	ReleasedAt sql.NullInt64
+	CustomerID sql.NullString
+}
+
+type CouponRule struct {
+	ID         uuid.UUID
+	CouponCode string
+	Kind       string
+	Value      float64
+	Category   sql.NullString
+	MinBasket  float64
+	CreatedAt  int64
+}
+
+type Modifier struct {
+	ID           uuid.UUID
+	GroupID      uuid.UUID
+	Name         string
+	PriceDelta   float64
+	DisplayOrder int32
+	CreatedAt    int64
+}
+
+type ModifierGroup struct {
+	ID           uuid.UUID
+	ProductID    uuid.UUID
+	Name         string
+	MinSelect    int32
+	MaxSelect    int32
+	DisplayOrder int32
+	CreatedAt    int64
 }
 
 type Order struct {
 	ID         uuid.UUID
 	CouponCode sql.NullString
 	CreatedAt  int64
+	Subtotal   float64
+	Discount   float64
+	Total      float64
+	Status     string
+	CustomerID sql.NullString
 }
 
 type OrderProduct struct {
 	ID        uuid.UUID
 	OrderID   uuid.UUID
 	ProductID uuid.UUID
+	Quantity  int32
+	UnitPrice float64
+}
+
+type OrderProductModifier struct {
+	ID             uuid.UUID
+	OrderProductID uuid.UUID
+	ModifierID     uuid.UUID
+	Name           string
+	PriceDelta     float64
+}
+
+type OrderStatusTransition struct {
+	ID         uuid.UUID
+	OrderID    uuid.UUID
+	FromStatus sql.NullString
+	ToStatus   string
+	Reason     sql.NullString
+	CreatedAt  int64
 }
 
 type Product struct {
-	ID        uuid.UUID
-	Name      string
-	Category  sql.NullString
-	Price     float64
-	CreatedAt int64
+	ID         uuid.UUID
+	Name       string
+	Category   sql.NullString
+	Price      float64
+	CreatedAt  int64
+	UpdatedAt  sql.NullInt64
+	DeletedAt  sql.NullInt64
+	CategoryID uuid.NullUUID
+	Stock      sql.NullInt32
 }
@@ -12,28 +12,137 @@ import (
 	"github.com/google/uuid"
 )
 
+const addModifierToOrderLine = `-- name: AddModifierToOrderLine :one
+INSERT INTO order_product_modifiers (
+    id,
+    order_product_id,
+    modifier_id,
+    name,
+    price_delta
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+) RETURNING id, order_product_id, modifier_id, name, price_delta
+`
+
+type AddModifierToOrderLineParams struct {
+	ID             uuid.UUID
+	OrderProductID uuid.UUID
+	ModifierID     uuid.UUID
+	Name           string
+	PriceDelta     float64
+}
+
+func (q *Queries) AddModifierToOrderLine(ctx context.Context, arg AddModifierToOrderLineParams) (OrderProductModifier, error) {
+	row := q.db.QueryRowContext(ctx, addModifierToOrderLine,
+		arg.ID,
+		arg.OrderProductID,
+		arg.ModifierID,
+		arg.Name,
+		arg.PriceDelta,
+	)
+	var i OrderProductModifier
+	err := row.Scan(
+		&i.ID,
+		&i.OrderProductID,
+		&i.ModifierID,
+		&i.Name,
+		&i.PriceDelta,
+	)
+	return i, err
+}
+
+const addOrderStatusTransition = `-- name: AddOrderStatusTransition :one
+INSERT INTO order_status_transitions (
+    id,
+    order_id,
+    from_status,
+    to_status,
+    reason,
+    created_at
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6
+) RETURNING id, order_id, from_status, to_status, reason, created_at
+`
+
+type AddOrderStatusTransitionParams struct {
+	ID         uuid.UUID
+	OrderID    uuid.UUID
+	FromStatus sql.NullString
+	ToStatus   string
+	Reason     sql.NullString
+	CreatedAt  int64
+}
+
+func (q *Queries) AddOrderStatusTransition(ctx context.Context, arg AddOrderStatusTransitionParams) (OrderStatusTransition, error) {
+	row := q.db.QueryRowContext(ctx, addOrderStatusTransition,
+		arg.ID,
+		arg.OrderID,
+		arg.FromStatus,
+		arg.ToStatus,
+		arg.Reason,
+		arg.CreatedAt,
+	)
+	var i OrderStatusTransition
+	err := row.Scan(
+		&i.ID,
+		&i.OrderID,
+		&i.FromStatus,
+		&i.ToStatus,
+		&i.Reason,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const addProductToOrder = `-- name: AddProductToOrder :one
 INSERT INTO order_product (
     id,
     order_id,
-    product_id
+    product_id,
+    quantity,
+    unit_price
 ) VALUES (
     $1,
     $2,
-    $3
-) RETURNING id, order_id, product_id
+    $3,
+    $4,
+    $5
+) RETURNING id, order_id, product_id, quantity, unit_price
 `
 
 type AddProductToOrderParams struct {
 	ID        uuid.UUID
 	OrderID   uuid.UUID
 	ProductID uuid.UUID
+	Quantity  int32
+	UnitPrice float64
 }
 
 func (q *Queries) AddProductToOrder(ctx context.Context, arg AddProductToOrderParams) (OrderProduct, error) {
-	row := q.db.QueryRowContext(ctx, addProductToOrder, arg.ID, arg.OrderID, arg.ProductID)
+	row := q.db.QueryRowContext(ctx, addProductToOrder,
+		arg.ID,
+		arg.OrderID,
+		arg.ProductID,
+		arg.Quantity,
+		arg.UnitPrice,
+	)
 	var i OrderProduct
-	err := row.Scan(&i.ID, &i.OrderID, &i.ProductID)
+	err := row.Scan(
+		&i.ID,
+		&i.OrderID,
+		&i.ProductID,
+		&i.Quantity,
+		&i.UnitPrice,
+	)
 	return i, err
 }
 
@@ -41,23 +150,352 @@ const createOrder = `-- name: CreateOrder :one
 INSERT INTO orders (
     id,
     coupon_code,
-    created_at
+    created_at,
+    subtotal,
+    discount,
+    total,
+    status,
+    customer_id
 ) VALUES (
     $1,
     $2,
-    $3
-) RETURNING id, coupon_code, created_at
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8
+) RETURNING id, coupon_code, created_at, subtotal, discount, total, status, customer_id
 `
 
 type CreateOrderParams struct {
 	ID         uuid.UUID
 	CouponCode sql.NullString
 	CreatedAt  int64
+	Subtotal   float64
+	Discount   float64
+	Total      float64
+	Status     string
+	CustomerID sql.NullString
 }
 
 func (q *Queries) CreateOrder(ctx context.Context, arg CreateOrderParams) (Order, error) {
-	row := q.db.QueryRowContext(ctx, createOrder, arg.ID, arg.CouponCode, arg.CreatedAt)
+	row := q.db.QueryRowContext(ctx, createOrder,
+		arg.ID,
+		arg.CouponCode,
+		arg.CreatedAt,
+		arg.Subtotal,
+		arg.Discount,
+		arg.Total,
+		arg.Status,
+		arg.CustomerID,
+	)
 	var i Order
-	err := row.Scan(&i.ID, &i.CouponCode, &i.CreatedAt)
+	err := row.Scan(
+		&i.ID,
+		&i.CouponCode,
+		&i.CreatedAt,
+		&i.Subtotal,
+		&i.Discount,
+		&i.Total,
+		&i.Status,
+		&i.CustomerID,
+	)
 	return i, err
 }
+
+const getOrderByID = `-- name: GetOrderByID :one
+SELECT id, coupon_code, created_at, subtotal, discount, total, status, customer_id
+FROM orders
+WHERE id = $1
+`
+
+// Get Order by ID
+func (q *Queries) GetOrderByID(ctx context.Context, id uuid.UUID) (Order, error) {
+	row := q.db.QueryRowContext(ctx, getOrderByID, id)
+	var i Order
+	err := row.Scan(
+		&i.ID,
+		&i.CouponCode,
+		&i.CreatedAt,
+		&i.Subtotal,
+		&i.Discount,
+		&i.Total,
+		&i.Status,
+		&i.CustomerID,
+	)
+	return i, err
+}
+
+const listOrderLineModifiers = `-- name: ListOrderLineModifiers :many
+SELECT opm.order_product_id, opm.modifier_id, opm.name, opm.price_delta
+FROM order_product_modifiers opm
+JOIN order_product op ON op.id = opm.order_product_id
+WHERE op.order_id = $1
+ORDER BY opm.order_product_id, opm.name
+`
+
+type ListOrderLineModifiersRow struct {
+	OrderProductID uuid.UUID
+	ModifierID     uuid.UUID
+	Name           string
+	PriceDelta     float64
+}
+
+// List the Modifiers chosen for every line of an Order
+func (q *Queries) ListOrderLineModifiers(ctx context.Context, orderID uuid.UUID) ([]ListOrderLineModifiersRow, error) {
+	rows, err := q.db.QueryContext(ctx, listOrderLineModifiers, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListOrderLineModifiersRow
+	for rows.Next() {
+		var i ListOrderLineModifiersRow
+		if err := rows.Scan(
+			&i.OrderProductID,
+			&i.ModifierID,
+			&i.Name,
+			&i.PriceDelta,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOrderLines = `-- name: ListOrderLines :many
+SELECT op.id, op.product_id, op.quantity, op.unit_price, p.name, p.category, p.price
+FROM order_product op
+JOIN products p ON p.id = op.product_id
+WHERE op.order_id = $1
+ORDER BY op.id
+`
+
+type ListOrderLinesRow struct {
+	ID        uuid.UUID
+	ProductID uuid.UUID
+	Quantity  int32
+	UnitPrice float64
+	Name      string
+	Category  sql.NullString
+	Price     float64
+}
+
+// List the lines of an Order joined with their Products
+func (q *Queries) ListOrderLines(ctx context.Context, orderID uuid.UUID) ([]ListOrderLinesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listOrderLines, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListOrderLinesRow
+	for rows.Next() {
+		var i ListOrderLinesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProductID,
+			&i.Quantity,
+			&i.UnitPrice,
+			&i.Name,
+			&i.Category,
+			&i.Price,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOrderStatusTransitions = `-- name: ListOrderStatusTransitions :many
+SELECT id, order_id, from_status, to_status, reason, created_at
+FROM order_status_transitions
+WHERE order_id = $1
+ORDER BY created_at, id
+`
+
+// List the status history of an Order
+func (q *Queries) ListOrderStatusTransitions(ctx context.Context, orderID uuid.UUID) ([]OrderStatusTransition, error) {
+	rows, err := q.db.QueryContext(ctx, listOrderStatusTransitions, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []OrderStatusTransition
+	for rows.Next() {
+		var i OrderStatusTransition
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrderID,
+			&i.FromStatus,
+			&i.ToStatus,
+			&i.Reason,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOrdersAsc = `-- name: ListOrdersAsc :many
+SELECT id, coupon_code, created_at, subtotal, discount, total, status, customer_id
+FROM orders
+WHERE ($1::bigint IS NULL OR created_at >= $1)
+  AND ($2::bigint IS NULL OR created_at <= $2)
+  AND ($3::text IS NULL OR coupon_code = $3)
+  AND ($4::bigint IS NULL OR (created_at, id) > ($4, $5::uuid))
+ORDER BY created_at ASC, id ASC
+LIMIT $6
+`
+
+type ListOrdersAscParams struct {
+	CreatedFrom     sql.NullInt64
+	CreatedTo       sql.NullInt64
+	CouponCode      sql.NullString
+	CursorCreatedAt sql.NullInt64
+	CursorID        uuid.NullUUID
+	RowLimit        int32
+}
+
+// List Orders oldest first using keyset pagination on (created_at, id)
+func (q *Queries) ListOrdersAsc(ctx context.Context, arg ListOrdersAscParams) ([]Order, error) {
+	rows, err := q.db.QueryContext(ctx, listOrdersAsc,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.CouponCode,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Order
+	for rows.Next() {
+		var i Order
+		if err := rows.Scan(
+			&i.ID,
+			&i.CouponCode,
+			&i.CreatedAt,
+			&i.Subtotal,
+			&i.Discount,
+			&i.Total,
+			&i.Status,
+			&i.CustomerID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOrdersDesc = `-- name: ListOrdersDesc :many
+SELECT id, coupon_code, created_at, subtotal, discount, total, status, customer_id
+FROM orders
+WHERE ($1::bigint IS NULL OR created_at >= $1)
+  AND ($2::bigint IS NULL OR created_at <= $2)
+  AND ($3::text IS NULL OR coupon_code = $3)
+  AND ($4::bigint IS NULL OR (created_at, id) < ($4, $5::uuid))
+ORDER BY created_at DESC, id DESC
+LIMIT $6
+`
+
+type ListOrdersDescParams struct {
+	CreatedFrom     sql.NullInt64
+	CreatedTo       sql.NullInt64
+	CouponCode      sql.NullString
+	CursorCreatedAt sql.NullInt64
+	CursorID        uuid.NullUUID
+	RowLimit        int32
+}
+
+// List Orders newest first using keyset pagination on (created_at, id)
+func (q *Queries) ListOrdersDesc(ctx context.Context, arg ListOrdersDescParams) ([]Order, error) {
+	rows, err := q.db.QueryContext(ctx, listOrdersDesc,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.CouponCode,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Order
+	for rows.Next() {
+		var i Order
+		if err := rows.Scan(
+			&i.ID,
+			&i.CouponCode,
+			&i.CreatedAt,
+			&i.Subtotal,
+			&i.Discount,
+			&i.Total,
+			&i.Status,
+			&i.CustomerID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateOrderStatus = `-- name: UpdateOrderStatus :execrows
+UPDATE orders
+SET status = $1
+WHERE id = $2 AND status = $3
+`
+
+type UpdateOrderStatusParams struct {
+	ToStatus   string
+	ID         uuid.UUID
+	FromStatus string
+}
+
+// Move an Order to a new status only if it is still in the expected status
+func (q *Queries) UpdateOrderStatus(ctx context.Context, arg UpdateOrderStatusParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateOrderStatus, arg.ToStatus, arg.ID, arg.FromStatus)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
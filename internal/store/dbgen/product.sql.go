@@ -7,14 +7,121 @@ package dbgen
 
 import (
 	"context"
+	"database/sql"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
+const adjustProductStock = `-- name: AdjustProductStock :one
+UPDATE products
+SET stock = COALESCE(stock, 0) + $1::integer,
+    updated_at = $2
+WHERE id = $3 AND deleted_at IS NULL
+  AND COALESCE(stock, 0) + $1::integer >= 0
+RETURNING id, name, category, price, created_at, updated_at, deleted_at, category_id, stock
+`
+
+type AdjustProductStockParams struct {
+	Delta     int32
+	UpdatedAt sql.NullInt64
+	ID        uuid.UUID
+}
+
+// Add delta to the stock of a Product, untracked stock counts as zero. No row is returned when the stock would go below zero
+func (q *Queries) AdjustProductStock(ctx context.Context, arg AdjustProductStockParams) (Product, error) {
+	row := q.db.QueryRowContext(ctx, adjustProductStock, arg.Delta, arg.UpdatedAt, arg.ID)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Category,
+		&i.Price,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.CategoryID,
+		&i.Stock,
+	)
+	return i, err
+}
+
+const createProduct = `-- name: CreateProduct :one
+INSERT INTO products (
+    id,
+    name,
+    category,
+    category_id,
+    price,
+    created_at
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6
+) RETURNING id, name, category, price, created_at, updated_at, deleted_at, category_id, stock
+`
+
+type CreateProductParams struct {
+	ID         uuid.UUID
+	Name       string
+	Category   sql.NullString
+	CategoryID uuid.NullUUID
+	Price      float64
+	CreatedAt  int64
+}
+
+// Create a Product
+func (q *Queries) CreateProduct(ctx context.Context, arg CreateProductParams) (Product, error) {
+	row := q.db.QueryRowContext(ctx, createProduct,
+		arg.ID,
+		arg.Name,
+		arg.Category,
+		arg.CategoryID,
+		arg.Price,
+		arg.CreatedAt,
+	)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Category,
+		&i.Price,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.CategoryID,
+		&i.Stock,
+	)
+	return i, err
+}
+
+const deleteProduct = `-- name: DeleteProduct :execrows
+UPDATE products
+SET deleted_at = $1
+WHERE id = $2 AND deleted_at IS NULL
+`
+
+type DeleteProductParams struct {
+	DeletedAt sql.NullInt64
+	ID        uuid.UUID
+}
+
+// Soft delete a Product, it stays referenced by past orders
+func (q *Queries) DeleteProduct(ctx context.Context, arg DeleteProductParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteProduct, arg.DeletedAt, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const getProductByID = `-- name: GetProductByID :one
-SELECT id, name, category, price, created_at
+SELECT id, name, category, price, created_at, updated_at, deleted_at, category_id, stock
 FROM products
-WHERE id = $1
+WHERE id = $1 AND deleted_at IS NULL
 `
 
 // Get Product by ID
@@ -27,19 +134,72 @@ func (q *Queries) GetProductByID(ctx context.Context, id uuid.UUID) (Product, er
 		&i.Category,
 		&i.Price,
 		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.CategoryID,
+		&i.Stock,
 	)
 	return i, err
 }
 
 const listProducts = `-- name: ListProducts :many
-SELECT id, name, category, price, created_at
+SELECT id, name, category, price, created_at, updated_at, deleted_at, category_id, stock
+FROM products
+WHERE deleted_at IS NULL
+  AND ($1::integer IS NULL
+    OR product_available_at(id, category_id, $1, $2::integer))
+ORDER BY name
+`
+
+type ListProductsParams struct {
+	AvailableDay    sql.NullInt32
+	AvailableMinute sql.NullInt32
+}
+
+// List all Products, only those available at the given day and minute when one is given
+func (q *Queries) ListProducts(ctx context.Context, arg ListProductsParams) ([]Product, error) {
+	rows, err := q.db.QueryContext(ctx, listProducts, arg.AvailableDay, arg.AvailableMinute)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Product
+	for rows.Next() {
+		var i Product
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Category,
+			&i.Price,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.CategoryID,
+			&i.Stock,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listProductsByIDs = `-- name: ListProductsByIDs :many
+SELECT id, name, category, price, created_at, updated_at, deleted_at, category_id, stock
 FROM products
+WHERE id = ANY($1::uuid[]) AND deleted_at IS NULL
 ORDER BY name
 `
 
-// List all Products
-func (q *Queries) ListProducts(ctx context.Context) ([]Product, error) {
-	rows, err := q.db.QueryContext(ctx, listProducts)
+// List Products matching any of the given IDs
+func (q *Queries) ListProductsByIDs(ctx context.Context, ids []uuid.UUID) ([]Product, error) {
+	rows, err := q.db.QueryContext(ctx, listProductsByIDs, pq.Array(ids))
 	if err != nil {
 		return nil, err
 	}
@@ -53,6 +213,10 @@ func (q *Queries) ListProducts(ctx context.Context) ([]Product, error) {
 			&i.Category,
 			&i.Price,
 			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.CategoryID,
+			&i.Stock,
 		); err != nil {
 			return nil, err
 		}
@@ -66,3 +230,370 @@ func (q *Queries) ListProducts(ctx context.Context) ([]Product, error) {
 	}
 	return items, nil
 }
+
+const listProductsPage = `-- name: ListProductsPage :many
+SELECT id, name, category, price, created_at, updated_at, deleted_at, category_id, stock
+FROM products
+WHERE deleted_at IS NULL
+  AND ($1::text IS NULL OR category = $1)
+  AND ($2::float IS NULL OR price >= $2)
+  AND ($3::float IS NULL OR price <= $3)
+  AND ($4::text IS NULL OR strpos(lower(name), lower($4)) > 0)
+  AND ($5::uuid IS NULL
+    OR ($6::text = 'name' AND (name, id) > ($7::text, $5))
+    OR ($6 = '-name' AND (name, id) < ($7, $5))
+    OR ($6 = 'price' AND (price, id) > ($8::float, $5))
+    OR ($6 = '-price' AND (price, id) < ($8, $5))
+    OR ($6 = 'created_at' AND (created_at, id) > ($9::bigint, $5))
+    OR ($6 = '-created_at' AND (created_at, id) < ($9, $5)))
+  AND ($10::integer IS NULL
+    OR product_available_at(id, category_id, $10, $11::integer))
+ORDER BY
+  CASE WHEN $6 = 'name' THEN name END ASC,
+  CASE WHEN $6 = '-name' THEN name END DESC,
+  CASE WHEN $6 = 'price' THEN price END ASC,
+  CASE WHEN $6 = '-price' THEN price END DESC,
+  CASE WHEN $6 = 'created_at' THEN created_at END ASC,
+  CASE WHEN $6 = '-created_at' THEN created_at END DESC,
+  CASE WHEN left($6, 1) = '-' THEN id END DESC,
+  id ASC
+LIMIT $12
+`
+
+type ListProductsPageParams struct {
+	Category        sql.NullString
+	MinPrice        sql.NullFloat64
+	MaxPrice        sql.NullFloat64
+	Q               sql.NullString
+	CursorID        uuid.NullUUID
+	Sort            string
+	CursorName      sql.NullString
+	CursorPrice     sql.NullFloat64
+	CursorCreatedAt sql.NullInt64
+	AvailableDay    sql.NullInt32
+	AvailableMinute sql.NullInt32
+	RowLimit        int32
+}
+
+// List a page of Products matching the filters using keyset pagination on the sort key and id
+func (q *Queries) ListProductsPage(ctx context.Context, arg ListProductsPageParams) ([]Product, error) {
+	rows, err := q.db.QueryContext(ctx, listProductsPage,
+		arg.Category,
+		arg.MinPrice,
+		arg.MaxPrice,
+		arg.Q,
+		arg.CursorID,
+		arg.Sort,
+		arg.CursorName,
+		arg.CursorPrice,
+		arg.CursorCreatedAt,
+		arg.AvailableDay,
+		arg.AvailableMinute,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Product
+	for rows.Next() {
+		var i Product
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Category,
+			&i.Price,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.CategoryID,
+			&i.Stock,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUnavailableProductIDs = `-- name: ListUnavailableProductIDs :many
+SELECT id
+FROM products
+WHERE id = ANY($1::uuid[])
+  AND NOT product_available_at(id, category_id, $2::integer, $3::integer)
+ORDER BY id
+`
+
+type ListUnavailableProductIDsParams struct {
+	Ids             []uuid.UUID
+	AvailableDay    int32
+	AvailableMinute int32
+}
+
+// List the given Products that are outside their availability windows
+func (q *Queries) ListUnavailableProductIDs(ctx context.Context, arg ListUnavailableProductIDsParams) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, listUnavailableProductIDs, pq.Array(arg.Ids), arg.AvailableDay, arg.AvailableMinute)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const patchProduct = `-- name: PatchProduct :one
+UPDATE products
+SET name = COALESCE($1, name),
+    category = COALESCE($2, category),
+    category_id = COALESCE($3, category_id),
+    price = COALESCE($4, price),
+    updated_at = $5
+WHERE id = $6 AND deleted_at IS NULL
+RETURNING id, name, category, price, created_at, updated_at, deleted_at, category_id, stock
+`
+
+type PatchProductParams struct {
+	Name       sql.NullString
+	Category   sql.NullString
+	CategoryID uuid.NullUUID
+	Price      sql.NullFloat64
+	UpdatedAt  sql.NullInt64
+	ID         uuid.UUID
+}
+
+// Update only the fields of a Product that are given
+func (q *Queries) PatchProduct(ctx context.Context, arg PatchProductParams) (Product, error) {
+	row := q.db.QueryRowContext(ctx, patchProduct,
+		arg.Name,
+		arg.Category,
+		arg.CategoryID,
+		arg.Price,
+		arg.UpdatedAt,
+		arg.ID,
+	)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Category,
+		&i.Price,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.CategoryID,
+		&i.Stock,
+	)
+	return i, err
+}
+
+const releaseOrderStock = `-- name: ReleaseOrderStock :execrows
+UPDATE products p
+SET stock = p.stock + op.quantity
+FROM (
+    SELECT product_id, SUM(quantity)::integer AS quantity
+    FROM order_product
+    WHERE order_id = $1
+    GROUP BY product_id
+) op
+WHERE p.id = op.product_id AND p.stock IS NOT NULL
+`
+
+// Give back the stock held by the lines of an Order, summed per Product as a Product can be on several lines
+func (q *Queries) ReleaseOrderStock(ctx context.Context, orderID uuid.UUID) (int64, error) {
+	result, err := q.db.ExecContext(ctx, releaseOrderStock, orderID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const reserveProductStock = `-- name: ReserveProductStock :execrows
+UPDATE products
+SET stock = stock - $1::integer
+WHERE id = $2
+  AND (stock IS NULL OR stock >= $1::integer)
+`
+
+type ReserveProductStockParams struct {
+	Quantity int32
+	ID       uuid.UUID
+}
+
+// Take quantity off the stock of a Product, untracked stock is left as is. No row is updated when there is not enough stock
+func (q *Queries) ReserveProductStock(ctx context.Context, arg ReserveProductStockParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, reserveProductStock, arg.Quantity, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const searchProducts = `-- name: SearchProducts :many
+SELECT p.id, p.name, p.category, p.price, p.created_at, p.category_id, p.stock,
+    (s.document @@ s.query)::boolean AS full_text,
+    (CASE WHEN s.document @@ s.query THEN ts_rank(s.document, s.query)
+          ELSE word_similarity($1, p.name) END)::real AS score,
+    ts_headline('english', p.name, s.query, 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true')::text AS headline
+FROM products p
+CROSS JOIN LATERAL (
+    SELECT setweight(to_tsvector('english', p.name), 'A') ||
+           setweight(to_tsvector('english', coalesce(p.category, '')), 'B') AS document,
+           websearch_to_tsquery('english', $1) AS query
+) s
+-- the filter repeats the indexed expressions verbatim, a LATERAL column would hide them from the planner
+WHERE p.deleted_at IS NULL
+  AND ((setweight(to_tsvector('english', p.name), 'A') ||
+        setweight(to_tsvector('english', coalesce(p.category, '')), 'B')) @@ websearch_to_tsquery('english', $1)
+       OR $1 <% p.name)
+ORDER BY full_text DESC, score DESC, p.name, p.id
+LIMIT $2
+`
+
+type SearchProductsParams struct {
+	Q        string
+	RowLimit int32
+}
+
+type SearchProductsRow struct {
+	ID         uuid.UUID
+	Name       string
+	Category   sql.NullString
+	Price      float64
+	CreatedAt  int64
+	CategoryID uuid.NullUUID
+	Stock      sql.NullInt32
+	FullText   bool
+	Score      float32
+	Headline   string
+}
+
+// Search Products by full-text match on name and category, falling back to trigram word similarity on the name to catch typos
+func (q *Queries) SearchProducts(ctx context.Context, arg SearchProductsParams) ([]SearchProductsRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchProducts, arg.Q, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchProductsRow
+	for rows.Next() {
+		var i SearchProductsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Category,
+			&i.Price,
+			&i.CreatedAt,
+			&i.CategoryID,
+			&i.Stock,
+			&i.FullText,
+			&i.Score,
+			&i.Headline,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setProductStock = `-- name: SetProductStock :one
+UPDATE products
+SET stock = $1,
+    updated_at = $2
+WHERE id = $3 AND deleted_at IS NULL
+RETURNING id, name, category, price, created_at, updated_at, deleted_at, category_id, stock
+`
+
+type SetProductStockParams struct {
+	Stock     sql.NullInt32
+	UpdatedAt sql.NullInt64
+	ID        uuid.UUID
+}
+
+// Set the stock of a Product, null stops tracking it
+func (q *Queries) SetProductStock(ctx context.Context, arg SetProductStockParams) (Product, error) {
+	row := q.db.QueryRowContext(ctx, setProductStock, arg.Stock, arg.UpdatedAt, arg.ID)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Category,
+		&i.Price,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.CategoryID,
+		&i.Stock,
+	)
+	return i, err
+}
+
+const updateProduct = `-- name: UpdateProduct :one
+UPDATE products
+SET name = $1,
+    category = $2,
+    category_id = $3,
+    price = $4,
+    updated_at = $5
+WHERE id = $6 AND deleted_at IS NULL
+RETURNING id, name, category, price, created_at, updated_at, deleted_at, category_id, stock
+`
+
+type UpdateProductParams struct {
+	Name       string
+	Category   sql.NullString
+	CategoryID uuid.NullUUID
+	Price      float64
+	UpdatedAt  sql.NullInt64
+	ID         uuid.UUID
+}
+
+// Replace every field of a Product
+func (q *Queries) UpdateProduct(ctx context.Context, arg UpdateProductParams) (Product, error) {
+	row := q.db.QueryRowContext(ctx, updateProduct,
+		arg.Name,
+		arg.Category,
+		arg.CategoryID,
+		arg.Price,
+		arg.UpdatedAt,
+		arg.ID,
+	)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Category,
+		&i.Price,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.CategoryID,
+		&i.Stock,
+	)
+	return i, err
+}
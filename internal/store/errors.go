@@ -0,0 +1,36 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrInvalidCursor  = errors.New("invalid cursor")
+	ErrStatusConflict = errors.New("order status changed concurrently")
+
+	ErrProductNotFound   = errors.New("product not found")
+	ErrCategoryNotFound  = errors.New("category not found")
+	ErrOutOfStock        = errors.New("product is out of stock")
+	ErrInsufficientStock = errors.New("stock cannot go below zero")
+
+	ErrCouponNotYetActive = errors.New("coupon is not active yet")
+	ErrCouponExpired      = errors.New("coupon has expired")
+	ErrCouponExhausted    = errors.New("coupon has no redemptions left")
+	ErrCouponNotFound     = errors.New("coupon not found")
+	ErrCouponExists       = errors.New("coupon already exists")
+)
+
+// OutOfStockError names the product an order asked for more of than is in stock.
+// It matches ErrOutOfStock with errors.Is.
+type OutOfStockError struct {
+	ProductID string
+}
+
+func (e *OutOfStockError) Error() string {
+	return fmt.Sprintf("product %s is out of stock", e.ProductID)
+}
+
+func (e *OutOfStockError) Is(target error) bool {
+	return target == ErrOutOfStock
+}
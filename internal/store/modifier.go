@@ -0,0 +1,87 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/sgrumley/kart-challenge/internal/store/dbgen"
+	"github.com/sgrumley/kart-challenge/pkg/models"
+)
+
+// withModifierGroups loads the modifier groups of every product with a single
+// query and sets them on the products in place.
+func (s *Store) withModifierGroups(ctx context.Context, products []models.Product) error {
+	if len(products) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, len(products))
+	for i, p := range products {
+		uid, err := uuid.Parse(p.ID)
+		if err != nil {
+			return fmt.Errorf("product id %s was not uuid: %w", p.ID, err)
+		}
+		ids[i] = uid
+	}
+
+	rows, err := s.Queries.ListModifiersByProductIDs(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("failed to list modifiers: %w", err)
+	}
+
+	groups := ModifierGroupsFromDB(rows)
+	for i := range products {
+		products[i].ModifierGroups = groups[products[i].ID]
+	}
+	return nil
+}
+
+// ModifierGroupsFromDB groups the rows by product id. Rows must be ordered by
+// product and group as ListModifiersByProductIDs returns them.
+func ModifierGroupsFromDB(rows []dbgen.ListModifiersByProductIDsRow) map[string][]models.ModifierGroup {
+	res := make(map[string][]models.ModifierGroup)
+	for _, r := range rows {
+		productID := r.ProductID.String()
+		groups := res[productID]
+		if len(groups) == 0 || groups[len(groups)-1].ID != r.GroupID.String() {
+			groups = append(groups, models.ModifierGroup{
+				ID:        r.GroupID.String(),
+				Name:      r.GroupName,
+				MinSelect: int(r.MinSelect),
+				MaxSelect: int(r.MaxSelect),
+			})
+		}
+
+		last := &groups[len(groups)-1]
+		last.Modifiers = append(last.Modifiers, models.Modifier{
+			ID:         r.ID.String(),
+			Name:       r.Name,
+			PriceDelta: float32(r.PriceDelta),
+		})
+		res[productID] = groups
+	}
+	return res
+}
+
+// addLineModifiers snapshots the modifiers picked for an order line.
+func addLineModifiers(ctx context.Context, q *dbgen.Queries, lineID uuid.UUID, modifiers []models.Modifier) error {
+	for _, m := range modifiers {
+		mid, err := uuid.Parse(m.ID)
+		if err != nil {
+			return fmt.Errorf("modifier id %s was not uuid: %w", m.ID, err)
+		}
+
+		_, err = q.AddModifierToOrderLine(ctx, dbgen.AddModifierToOrderLineParams{
+			ID:             GenerateUUIDv4(),
+			OrderProductID: lineID,
+			ModifierID:     mid,
+			Name:           m.Name,
+			PriceDelta:     float64(m.PriceDelta),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
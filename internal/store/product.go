@@ -0,0 +1,316 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/sgrumley/kart-challenge/internal/store/dbgen"
+	"github.com/sgrumley/kart-challenge/pkg/models"
+)
+
+// CreateProduct files the product under the category matching its CategoryID,
+// or its Category name when no id is given.
+func (s *Store) CreateProduct(ctx context.Context, product models.Product) (models.Product, error) {
+	categoryID, category, err := resolveCategory(ctx, s.Queries, product.CategoryID, product.Category)
+	if err != nil {
+		return models.Product{}, err
+	}
+
+	created, err := s.Queries.CreateProduct(ctx, dbgen.CreateProductParams{
+		ID:         GenerateUUIDv4(),
+		Name:       product.Name,
+		Category:   category,
+		CategoryID: categoryID,
+		Price:      float64(product.Price),
+		CreatedAt:  int64(TimeStampNow()),
+	})
+	if err != nil {
+		return models.Product{}, fmt.Errorf("failed to create product: %w", err)
+	}
+
+	return ProductFromDB(created), nil
+}
+
+// UpdateProduct replaces every field of a product that has not been deleted.
+func (s *Store) UpdateProduct(ctx context.Context, product models.Product) (models.Product, error) {
+	uid, err := uuid.Parse(product.ID)
+	if err != nil {
+		return models.Product{}, fmt.Errorf("product id %s was not uuid: %w", product.ID, err)
+	}
+
+	categoryID, category, err := resolveCategory(ctx, s.Queries, product.CategoryID, product.Category)
+	if err != nil {
+		return models.Product{}, err
+	}
+
+	updated, err := s.Queries.UpdateProduct(ctx, dbgen.UpdateProductParams{
+		Name:       product.Name,
+		Category:   category,
+		CategoryID: categoryID,
+		Price:      float64(product.Price),
+		UpdatedAt:  sql.NullInt64{Int64: int64(TimeStampNow()), Valid: true},
+		ID:         uid,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Product{}, ErrProductNotFound
+	}
+	if err != nil {
+		return models.Product{}, fmt.Errorf("failed to update product: %w", err)
+	}
+
+	return ProductFromDB(updated), nil
+}
+
+// PatchProduct changes only the fields set in patch.
+func (s *Store) PatchProduct(ctx context.Context, id string, patch models.ProductPatch) (models.Product, error) {
+	uid, err := uuid.Parse(id)
+	if err != nil {
+		return models.Product{}, fmt.Errorf("product id %s was not uuid: %w", id, err)
+	}
+
+	params := dbgen.PatchProductParams{
+		UpdatedAt: sql.NullInt64{Int64: int64(TimeStampNow()), Valid: true},
+		ID:        uid,
+	}
+	if patch.Name != nil {
+		params.Name = sql.NullString{String: *patch.Name, Valid: true}
+	}
+	if patch.CategoryID != nil || patch.Category != nil {
+		var id, name string
+		if patch.CategoryID != nil {
+			id = *patch.CategoryID
+		}
+		if patch.Category != nil {
+			name = *patch.Category
+			// an empty name must not fall through to leaving the category as is
+			if name == "" && id == "" {
+				return models.Product{}, ErrCategoryNotFound
+			}
+		}
+		params.CategoryID, params.Category, err = resolveCategory(ctx, s.Queries, id, name)
+		if err != nil {
+			return models.Product{}, err
+		}
+	}
+	if patch.Price != nil {
+		params.Price = sql.NullFloat64{Float64: float64(*patch.Price), Valid: true}
+	}
+
+	patched, err := s.Queries.PatchProduct(ctx, params)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Product{}, ErrProductNotFound
+	}
+	if err != nil {
+		return models.Product{}, fmt.Errorf("failed to patch product: %w", err)
+	}
+
+	return ProductFromDB(patched), nil
+}
+
+// DeleteProduct soft deletes a product. It disappears from the catalogue and
+// can no longer be ordered but past orders keep showing it.
+func (s *Store) DeleteProduct(ctx context.Context, id string) error {
+	uid, err := uuid.Parse(id)
+	if err != nil {
+		return fmt.Errorf("product id %s was not uuid: %w", id, err)
+	}
+
+	deleted, err := s.Queries.DeleteProduct(ctx, dbgen.DeleteProductParams{
+		DeletedAt: sql.NullInt64{Int64: int64(TimeStampNow()), Valid: true},
+		ID:        uid,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete product: %w", err)
+	}
+	if deleted == 0 {
+		return ErrProductNotFound
+	}
+
+	return nil
+}
+
+// SetProductStock sets the stock of a product, nil stops tracking its stock.
+func (s *Store) SetProductStock(ctx context.Context, id string, stock *int) (models.Product, error) {
+	uid, err := uuid.Parse(id)
+	if err != nil {
+		return models.Product{}, fmt.Errorf("product id %s was not uuid: %w", id, err)
+	}
+
+	params := dbgen.SetProductStockParams{
+		UpdatedAt: sql.NullInt64{Int64: int64(TimeStampNow()), Valid: true},
+		ID:        uid,
+	}
+	if stock != nil {
+		params.Stock = sql.NullInt32{Int32: int32(*stock), Valid: true}
+	}
+
+	updated, err := s.Queries.SetProductStock(ctx, params)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Product{}, ErrProductNotFound
+	}
+	if err != nil {
+		return models.Product{}, fmt.Errorf("failed to set product stock: %w", err)
+	}
+
+	return ProductFromDB(updated), nil
+}
+
+// AdjustProductStock adds delta to the stock of a product, a negative delta takes
+// stock away. Adjusting a product without tracked stock starts tracking it from
+// zero.
+func (s *Store) AdjustProductStock(ctx context.Context, id string, delta int) (models.Product, error) {
+	uid, err := uuid.Parse(id)
+	if err != nil {
+		return models.Product{}, fmt.Errorf("product id %s was not uuid: %w", id, err)
+	}
+
+	adjusted, err := s.Queries.AdjustProductStock(ctx, dbgen.AdjustProductStockParams{
+		Delta:     int32(delta),
+		UpdatedAt: sql.NullInt64{Int64: int64(TimeStampNow()), Valid: true},
+		ID:        uid,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		// no row is either a missing product or one without enough stock
+		_, getErr := s.Queries.GetProductByID(ctx, uid)
+		switch {
+		case errors.Is(getErr, sql.ErrNoRows):
+			return models.Product{}, ErrProductNotFound
+		case getErr != nil:
+			return models.Product{}, fmt.Errorf("failed to get product: %w", getErr)
+		}
+		return models.Product{}, ErrInsufficientStock
+	}
+	if err != nil {
+		return models.Product{}, fmt.Errorf("failed to adjust product stock: %w", err)
+	}
+
+	return ProductFromDB(adjusted), nil
+}
+
+// ListProductsPage returns a single page of products matching the filter. One
+// extra row is fetched to decide whether a cursor for the next page is needed.
+func (s *Store) ListProductsPage(ctx context.Context, filter models.ProductFilter) (models.ProductPage, error) {
+	if filter.Limit <= 0 {
+		return models.ProductPage{}, fmt.Errorf("limit must be positive, got %d", filter.Limit)
+	}
+	if filter.Sort == "" {
+		filter.Sort = models.ProductSortName
+	}
+
+	params := dbgen.ListProductsPageParams{
+		Category: sql.NullString{String: filter.Category, Valid: filter.Category != ""},
+		MinPrice: sql.NullFloat64{Float64: float64(filter.MinPrice), Valid: filter.MinPrice != 0},
+		MaxPrice: sql.NullFloat64{Float64: float64(filter.MaxPrice), Valid: filter.MaxPrice != 0},
+		Q:        sql.NullString{String: filter.Query, Valid: filter.Query != ""},
+		Sort:     string(filter.Sort),
+		RowLimit: int32(filter.Limit + 1),
+	}
+	params.AvailableDay, params.AvailableMinute = s.availableAt(filter.AvailableAt)
+
+	if filter.Cursor != "" {
+		if err := setProductCursor(&params, filter.Cursor); err != nil {
+			return models.ProductPage{}, err
+		}
+	}
+
+	products, err := s.Queries.ListProductsPage(ctx, params)
+	if err != nil {
+		return models.ProductPage{}, err
+	}
+
+	var nextCursor string
+	if len(products) > filter.Limit {
+		products = products[:filter.Limit]
+		last := products[len(products)-1]
+		nextCursor = EncodeProductCursor(params.Sort, productSortKey(filter.Sort, last), last.ID)
+	}
+
+	res := ProductsFromDB(products)
+	if err := s.withModifierGroups(ctx, res); err != nil {
+		return models.ProductPage{}, err
+	}
+
+	return models.ProductPage{
+		Products:   res,
+		NextCursor: nextCursor,
+	}, nil
+}
+
+// productSortKey formats the value a product is sorted by for use in a cursor.
+func productSortKey(sort models.ProductSort, p dbgen.Product) string {
+	switch sort {
+	case models.ProductSortPrice, models.ProductSortPriceDesc:
+		return strconv.FormatFloat(p.Price, 'g', -1, 64)
+	case models.ProductSortCreatedAt, models.ProductSortCreatedAtDesc:
+		return strconv.FormatInt(p.CreatedAt, 10)
+	default:
+		return p.Name
+	}
+}
+
+// setProductCursor decodes the cursor into the keyset parameter matching the sort.
+func setProductCursor(params *dbgen.ListProductsPageParams, cursor string) error {
+	sort, key, id, err := DecodeProductCursor(cursor)
+	if err != nil {
+		return err
+	}
+	if sort != params.Sort {
+		return fmt.Errorf("%w: cursor is for sort %q", ErrInvalidCursor, sort)
+	}
+
+	switch models.ProductSort(sort) {
+	case models.ProductSortPrice, models.ProductSortPriceDesc:
+		price, err := strconv.ParseFloat(key, 64)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+		}
+		params.CursorPrice = sql.NullFloat64{Float64: price, Valid: true}
+	case models.ProductSortCreatedAt, models.ProductSortCreatedAtDesc:
+		createdAt, err := strconv.ParseInt(key, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+		}
+		params.CursorCreatedAt = sql.NullInt64{Int64: createdAt, Valid: true}
+	default:
+		params.CursorName = sql.NullString{String: key, Valid: true}
+	}
+	params.CursorID = uuid.NullUUID{UUID: id, Valid: true}
+
+	return nil
+}
+
+// SearchProducts returns the products best matching the query. Full-text matches
+// on the name and category come first, then names close to the query to cope
+// with typos.
+func (s *Store) SearchProducts(ctx context.Context, query string, limit int) ([]models.ProductMatch, error) {
+	rows, err := s.Queries.SearchProducts(ctx, dbgen.SearchProductsParams{
+		Q:        query,
+		RowLimit: int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search products: %w", err)
+	}
+
+	res := make([]models.ProductMatch, len(rows))
+	for i, r := range rows {
+		res[i] = models.ProductMatch{
+			Product: models.Product{
+				ID:         r.ID.String(),
+				Name:       r.Name,
+				Category:   r.Category.String,
+				CategoryID: nullUUIDString(r.CategoryID),
+				Price:      float32(r.Price),
+				Stock:      nullInt32Ptr(r.Stock),
+				CreatedAt:  r.CreatedAt,
+			},
+			FullText: r.FullText,
+			Score:    r.Score,
+			Headline: r.Headline,
+		}
+	}
+	return res, nil
+}
@@ -5,8 +5,11 @@ import (
 	"database/sql"
 	"fmt"
 	"log/slog"
+	"math"
+	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/sgrumley/kart-challenge/internal/couponindex"
 	"github.com/sgrumley/kart-challenge/internal/store/dbgen"
 	"github.com/sgrumley/kart-challenge/pkg/logger"
 	"github.com/sgrumley/kart-challenge/pkg/models"
@@ -17,6 +20,12 @@ import (
 type Store struct {
 	Queries *dbgen.Queries
 	DB      *sqlx.DB
+
+	// CouponIndex answers coupon checks from memory once it has loaded.
+	CouponIndex *couponindex.Index
+
+	// Location is the timezone availability windows are written in, UTC when nil.
+	Location *time.Location
 }
 
 func New(client *sqlx.DB) *Store {
@@ -37,106 +46,457 @@ func (s *Store) GetProduct(ctx context.Context, id string) (models.Product, erro
 		return models.Product{}, err
 	}
 
-	return ProductFromDB(product), nil
+	res := []models.Product{ProductFromDB(product)}
+	if err := s.withModifierGroups(ctx, res); err != nil {
+		return models.Product{}, err
+	}
+
+	return res[0], nil
 }
 
 func ProductFromDB(product dbgen.Product) models.Product {
 	return models.Product{
-		ID:       product.ID.String(),
-		Name:     product.Name,
-		Category: product.Category.String,
-		Price:    float32(product.Price),
+		ID:         product.ID.String(),
+		Name:       product.Name,
+		Category:   product.Category.String,
+		CategoryID: nullUUIDString(product.CategoryID),
+		Price:      float32(product.Price),
+		Stock:      nullInt32Ptr(product.Stock),
+		CreatedAt:  product.CreatedAt,
 	}
 }
 
-func (s *Store) ListProducts(ctx context.Context) ([]models.Product, error) {
-	products, err := s.Queries.ListProducts(ctx)
+// ListProducts returns the whole catalogue, only the products available at
+// availableAt unless it is zero.
+func (s *Store) ListProducts(ctx context.Context, availableAt time.Time) ([]models.Product, error) {
+	day, minute := s.availableAt(availableAt)
+	products, err := s.Queries.ListProducts(ctx, dbgen.ListProductsParams{
+		AvailableDay:    day,
+		AvailableMinute: minute,
+	})
 	if err != nil {
 		return []models.Product{}, err
 	}
 
-	return ProductsFromDB(products), nil
+	res := ProductsFromDB(products)
+	if err := s.withModifierGroups(ctx, res); err != nil {
+		return []models.Product{}, err
+	}
+
+	return res, nil
 }
 
 func ProductsFromDB(products []dbgen.Product) []models.Product {
 	res := make([]models.Product, len(products))
 	for i, p := range products {
-		res[i] = models.Product{
-			ID:       p.ID.String(),
-			Name:     p.Name,
-			Category: p.Category.String,
-			Price:    float32(p.Price),
-		}
+		res[i] = ProductFromDB(p)
 	}
 	return res
 }
 
+func (s *Store) GetProducts(ctx context.Context, ids []string) ([]models.Product, error) {
+	uids := make([]uuid.UUID, len(ids))
+	for i, id := range ids {
+		uid, err := uuid.Parse(id)
+		if err != nil {
+			return []models.Product{}, fmt.Errorf("product id %s was not uuid: %w", id, err)
+		}
+		uids[i] = uid
+	}
+
+	products, err := s.Queries.ListProductsByIDs(ctx, uids)
+	if err != nil {
+		return []models.Product{}, err
+	}
+
+	res := ProductsFromDB(products)
+	if err := s.withModifierGroups(ctx, res); err != nil {
+		return []models.Product{}, err
+	}
+
+	return res, nil
+}
+
+// CreateOrder persists an order that has already been priced by the order service.
 func (s *Store) CreateOrder(ctx context.Context, order models.Order) (models.Order, error) {
 	tx, err := s.DB.Begin()
 	if err != nil {
 		return models.Order{}, err
 	}
+	defer tx.Rollback() // no-op once committed
 
 	qtx := s.Queries.WithTx(tx)
 
 	// create order
 	orderID := GenerateUUIDv4()
 	logger.Info(ctx, "creating order", slog.String("id", orderID.String()))
-	_, err = qtx.CreateOrder(ctx, dbgen.CreateOrderParams{
+	created, err := qtx.CreateOrder(ctx, dbgen.CreateOrderParams{
 		ID: orderID,
 		CouponCode: sql.NullString{
 			String: order.CouponCode,
 			Valid:  order.CouponCode != "",
 		},
 		CreatedAt: int64(TimeStampNow()),
+		Subtotal:  float64(order.Subtotal),
+		Discount:  float64(order.Discount),
+		Total:     float64(order.Total),
+		Status:    string(models.OrderStatusPlaced),
+		CustomerID: sql.NullString{
+			String: order.CustomerID,
+			Valid:  order.CustomerID != "",
+		},
+	})
+	if err != nil {
+		return models.Order{}, fmt.Errorf("failed to create order: %w", err)
+	}
+
+	_, err = qtx.AddOrderStatusTransition(ctx, dbgen.AddOrderStatusTransitionParams{
+		ID:        GenerateUUIDv4(),
+		OrderID:   orderID,
+		ToStatus:  created.Status,
+		CreatedAt: created.CreatedAt,
 	})
 	if err != nil {
-		return models.Order{}, nil
+		return models.Order{}, fmt.Errorf("failed to record order status: %w", err)
+	}
+
+	if order.CouponCode != "" {
+		if err := redeemCoupon(ctx, qtx, order, orderID, created.CreatedAt); err != nil {
+			return models.Order{}, err
+		}
 	}
 
-	products := make([]models.Product, len(order.Items))
+	products := make([]models.Product, 0, len(order.Items))
+
+	for _, item := range order.Items {
+		pid, err := uuid.Parse(item.ProductID)
+		if err != nil {
+			return models.Order{}, fmt.Errorf("product id %s was not uuid: %w", item.ProductID, err)
+		}
 
-	for _, product := range order.Items {
-		pid, err := uuid.Parse(product.ProductID)
+		p, err := qtx.GetProductByID(ctx, pid)
 		if err != nil {
-			return models.Order{}, fmt.Errorf("product id %s was not uuid: %w", product.ProductID, err)
+			return models.Order{}, fmt.Errorf("product id %s not found: %w", item.ProductID, err)
 		}
 
-		p, err := s.Queries.GetProductByID(ctx, pid)
+		// the conditional update locks the product row so concurrent orders
+		// cannot both take the last units
+		reserved, err := qtx.ReserveProductStock(ctx, dbgen.ReserveProductStockParams{
+			Quantity: int32(item.Quantity),
+			ID:       pid,
+		})
 		if err != nil {
-			return models.Order{}, fmt.Errorf("product id %s not found: %w", product.ProductID, err)
+			return models.Order{}, fmt.Errorf("failed to reserve stock: %w", err)
+		}
+		if reserved == 0 {
+			return models.Order{}, &OutOfStockError{ProductID: item.ProductID}
 		}
 
 		products = append(products, ProductFromDB(p))
 
-		_, err = qtx.AddProductToOrder(ctx, dbgen.AddProductToOrderParams{
+		// the unit price is snapshotted so historic orders survive product price changes
+		line, err := qtx.AddProductToOrder(ctx, dbgen.AddProductToOrderParams{
 			ID:        GenerateUUIDv4(),
 			OrderID:   orderID,
 			ProductID: pid,
+			Quantity:  int32(item.Quantity),
+			UnitPrice: float64(item.UnitPrice),
 		})
 		if err != nil {
 			return models.Order{}, err
 		}
+
+		if err := addLineModifiers(ctx, qtx, line.ID, item.Modifiers); err != nil {
+			return models.Order{}, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.Order{}, err
+	}
+
+	return models.Order{
+		ID:         orderID.String(),
+		CouponCode: order.CouponCode,
+		CustomerID: order.CustomerID,
+		Items:      order.Items,
+		Products:   products,
+		Subtotal:   order.Subtotal,
+		Discount:   order.Discount,
+		Total:      order.Total,
+		Status:     models.OrderStatus(created.Status),
+		CreatedAt:  created.CreatedAt,
+	}, nil
+}
+
+func (s *Store) GetOrder(ctx context.Context, id string) (models.Order, error) {
+	uid, err := uuid.Parse(id)
+	if err != nil {
+		return models.Order{}, err
+	}
+
+	order, err := s.Queries.GetOrderByID(ctx, uid)
+	if err != nil {
+		return models.Order{}, err
+	}
+
+	lines, err := s.Queries.ListOrderLines(ctx, uid)
+	if err != nil {
+		return models.Order{}, err
+	}
+
+	modifiers, err := s.Queries.ListOrderLineModifiers(ctx, uid)
+	if err != nil {
+		return models.Order{}, err
+	}
+
+	transitions, err := s.Queries.ListOrderStatusTransitions(ctx, uid)
+	if err != nil {
+		return models.Order{}, err
+	}
+
+	res := OrderFromDB(order, lines, modifiers)
+	res.StatusHistory = StatusTransitionsFromDB(transitions)
+	return res, nil
+}
+
+func StatusTransitionsFromDB(transitions []dbgen.OrderStatusTransition) []models.StatusTransition {
+	res := make([]models.StatusTransition, len(transitions))
+	for i, t := range transitions {
+		res[i] = models.StatusTransition{
+			From:      models.OrderStatus(t.FromStatus.String),
+			To:        models.OrderStatus(t.ToStatus),
+			Reason:    t.Reason.String,
+			CreatedAt: t.CreatedAt,
+		}
+	}
+	return res
+}
+
+func OrderFromDB(order dbgen.Order, lines []dbgen.ListOrderLinesRow, modifiers []dbgen.ListOrderLineModifiersRow) models.Order {
+	lineModifiers := make(map[uuid.UUID][]models.Modifier)
+	for _, m := range modifiers {
+		lineModifiers[m.OrderProductID] = append(lineModifiers[m.OrderProductID], models.Modifier{
+			ID:         m.ModifierID.String(),
+			Name:       m.Name,
+			PriceDelta: float32(m.PriceDelta),
+		})
+	}
+
+	items := make([]models.Item, len(lines))
+	products := make([]models.Product, len(lines))
+	for i, l := range lines {
+		items[i] = models.Item{
+			ProductID: l.ProductID.String(),
+			Quantity:  int(l.Quantity),
+			Modifiers: lineModifiers[l.ID],
+			UnitPrice: float32(l.UnitPrice),
+			LineTotal: float32(math.Round(l.UnitPrice*float64(l.Quantity)*100) / 100),
+		}
+		products[i] = models.Product{
+			ID:       l.ProductID.String(),
+			Name:     l.Name,
+			Category: l.Category.String,
+			Price:    float32(l.Price),
+		}
 	}
 
 	return models.Order{
-		ID:       orderID.String(),
-		Items:    order.Items,
-		Products: products,
-	}, tx.Commit()
+		ID:         order.ID.String(),
+		CouponCode: order.CouponCode.String,
+		CustomerID: order.CustomerID.String,
+		Items:      items,
+		Products:   products,
+		Subtotal:   float32(order.Subtotal),
+		Discount:   float32(order.Discount),
+		Total:      float32(order.Total),
+		Status:     models.OrderStatus(order.Status),
+		CreatedAt:  order.CreatedAt,
+	}
+}
+
+// ListOrders returns a page of orders, newest first unless filter.Ascending.
+func (s *Store) ListOrders(ctx context.Context, filter models.OrderFilter) (models.OrderPage, error) {
+	if filter.Limit <= 0 {
+		return models.OrderPage{}, fmt.Errorf("limit must be positive, got %d", filter.Limit)
+	}
+
+	params := dbgen.ListOrdersDescParams{
+		CreatedFrom: sql.NullInt64{Int64: filter.CreatedFrom, Valid: filter.CreatedFrom != 0},
+		CreatedTo:   sql.NullInt64{Int64: filter.CreatedTo, Valid: filter.CreatedTo != 0},
+		CouponCode:  sql.NullString{String: filter.CouponCode, Valid: filter.CouponCode != ""},
+		RowLimit:    int32(filter.Limit + 1),
+	}
+
+	if filter.Cursor != "" {
+		createdAt, id, err := DecodeCursor(filter.Cursor)
+		if err != nil {
+			return models.OrderPage{}, err
+		}
+		params.CursorCreatedAt = sql.NullInt64{Int64: createdAt, Valid: true}
+		params.CursorID = uuid.NullUUID{UUID: id, Valid: true}
+	}
+
+	var (
+		orders []dbgen.Order
+		err    error
+	)
+	if filter.Ascending {
+		orders, err = s.Queries.ListOrdersAsc(ctx, dbgen.ListOrdersAscParams(params))
+	} else {
+		orders, err = s.Queries.ListOrdersDesc(ctx, params)
+	}
+	if err != nil {
+		return models.OrderPage{}, err
+	}
+
+	var nextCursor string
+	if len(orders) > filter.Limit {
+		orders = orders[:filter.Limit]
+		last := orders[len(orders)-1]
+		nextCursor = EncodeCursor(last.CreatedAt, last.ID)
+	}
+
+	res := make([]models.Order, len(orders))
+	for i, o := range orders {
+		res[i] = OrderFromDB(o, nil, nil)
+	}
+
+	return models.OrderPage{
+		Orders:     res,
+		NextCursor: nextCursor,
+	}, nil
+}
+
+// UpdateOrderStatus moves an order from one status to another and records the
+// transition. ErrStatusConflict is returned if the order is no longer in the from status.
+func (s *Store) UpdateOrderStatus(ctx context.Context, id string, from, to models.OrderStatus, reason string) (models.Order, error) {
+	uid, err := uuid.Parse(id)
+	if err != nil {
+		return models.Order{}, err
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return models.Order{}, err
+	}
+	defer tx.Rollback() // no-op once committed
+
+	qtx := s.Queries.WithTx(tx)
+
+	if err := updateOrderStatus(ctx, qtx, uid, from, to, reason); err != nil {
+		return models.Order{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.Order{}, err
+	}
+
+	return s.GetOrder(ctx, id)
+}
+
+func updateOrderStatus(ctx context.Context, qtx *dbgen.Queries, id uuid.UUID, from, to models.OrderStatus, reason string) error {
+	updated, err := qtx.UpdateOrderStatus(ctx, dbgen.UpdateOrderStatusParams{
+		ToStatus:   string(to),
+		ID:         id,
+		FromStatus: string(from),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update order status: %w", err)
+	}
+	if updated == 0 {
+		return ErrStatusConflict
+	}
+
+	logger.Info(ctx, "order status changed",
+		slog.String("id", id.String()),
+		slog.String("from", string(from)),
+		slog.String("to", string(to)),
+	)
+
+	_, err = qtx.AddOrderStatusTransition(ctx, dbgen.AddOrderStatusTransitionParams{
+		ID:         GenerateUUIDv4(),
+		OrderID:    id,
+		FromStatus: sql.NullString{String: string(from), Valid: true},
+		ToStatus:   string(to),
+		Reason:     sql.NullString{String: reason, Valid: reason != ""},
+		CreatedAt:  int64(TimeStampNow()),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record order status: %w", err)
+	}
+
+	if to == models.OrderStatusCancelled || to == models.OrderStatusRejected {
+		return releaseOrder(ctx, qtx, id)
+	}
+
+	return nil
+}
+
+// releaseOrder hands back everything an order was holding so that a cancelled or
+// rejected order does not count against coupon limits or hold on to stock.
+func releaseOrder(ctx context.Context, qtx *dbgen.Queries, id uuid.UUID) error {
+	released, err := qtx.ReleaseCouponRedemptions(ctx, dbgen.ReleaseCouponRedemptionsParams{
+		ReleasedAt: sql.NullInt64{Int64: int64(TimeStampNow()), Valid: true},
+		OrderID:    id,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to release coupon redemption: %w", err)
+	}
+
+	restocked, err := qtx.ReleaseOrderStock(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to release stock: %w", err)
+	}
+
+	logger.Info(ctx, "order released",
+		slog.String("id", id.String()),
+		slog.Int64("coupon_redemptions", released),
+		slog.Int64("restocked_products", restocked),
+	)
+
+	return nil
 }
 
 func (s *Store) CheckCoupon(ctx context.Context, coupon string) bool {
-	matches := make([]string, 0)
-	for i := 1; i < 4; i++ {
-		couponID := fmt.Sprintf("%s-%d", coupon, i)
-		logger.Info(ctx, "couponID", slog.String("id", couponID))
-		match, err := s.Queries.GetCouponByID(ctx, couponID)
-		if err == nil {
-			matches = append(matches, match)
+	if s.CouponIndex != nil {
+		if valid, ok := s.CouponIndex.Contains(coupon); ok {
+			return valid
 		}
 	}
 
-	logger.Info(ctx, "coupon matches", slog.Any("id", matches))
-	return len(matches) > 1
+	valid, err := s.Queries.CheckCouponSources(ctx, dbgen.CheckCouponSourcesParams{
+		Code:       coupon,
+		MinSources: couponindex.MinSources,
+	})
+	if err != nil {
+		logger.Error(ctx, "failed to check coupon", err)
+		return false
+	}
+
+	return valid
+}
+
+func (s *Store) GetCouponRules(ctx context.Context, coupon string) ([]models.CouponRule, error) {
+	rules, err := s.Queries.ListCouponRules(ctx, coupon)
+	if err != nil {
+		return []models.CouponRule{}, err
+	}
+
+	return CouponRulesFromDB(rules), nil
+}
+
+func CouponRulesFromDB(rules []dbgen.CouponRule) []models.CouponRule {
+	res := make([]models.CouponRule, len(rules))
+	for i, r := range rules {
+		res[i] = models.CouponRule{
+			ID:         r.ID.String(),
+			CouponCode: r.CouponCode,
+			Kind:       models.DiscountKind(r.Kind),
+			Value:      float32(r.Value),
+			Category:   r.Category.String,
+			MinBasket:  float32(r.MinBasket),
+		}
+	}
+	return res
 }
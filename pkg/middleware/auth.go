@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+
+	"github.com/sgrumley/kart-challenge/pkg/logger"
+	"github.com/sgrumley/kart-challenge/pkg/web"
+)
+
+const APIKeyHeader = "api_key"
+
+// RequireAPIKey rejects requests whose api_key header does not match key. An
+// empty key rejects every request so an unconfigured server is never open.
+func RequireAPIKey(key string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !HasAPIKey(r, key) {
+				logger.Error(r.Context(), "unauthorized request", fmt.Errorf("missing or invalid %s header", APIKeyHeader))
+				web.RespondJSONError(w, web.Err401Default)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// HasAPIKey reports whether the api_key header of r matches key, for handlers
+// that are public but unlock more for admins. An empty key never matches.
+func HasAPIKey(r *http.Request, key string) bool {
+	got := r.Header.Get(APIKeyHeader)
+	return key != "" && subtle.ConstantTimeCompare([]byte(got), []byte(key)) == 1
+}
@@ -1,20 +1,186 @@
 package models
 
+import "time"
+
+// Product.Category is the name of the category CategoryID points at.
+// Product.Stock is nil when stock is not tracked for the product.
 type Product struct {
-	ID       string
-	Name     string
-	Category string
-	Price    float32
+	ID             string
+	Name           string
+	Category       string
+	CategoryID     string
+	Price          float32
+	Stock          *int
+	CreatedAt      int64
+	ModifierGroups []ModifierGroup
 }
 
-type Order struct {
+// ModifierGroup is a choice offered on a product, such as a size or extras.
+// Between MinSelect and MaxSelect of its modifiers must be picked.
+type ModifierGroup struct {
+	ID        string
+	Name      string
+	MinSelect int
+	MaxSelect int
+	Modifiers []Modifier
+}
+
+// Modifier adds PriceDelta to the price of the product it is picked for.
+type Modifier struct {
 	ID         string
-	CouponCode string
-	Items      []Item
+	Name       string
+	PriceDelta float32
+}
+
+// Category groups products, ParentID is empty for top level categories.
+type Category struct {
+	ID           string
+	Name         string
+	ParentID     string
+	DisplayOrder int
+}
+
+type ProductSort string
+
+const (
+	ProductSortName          ProductSort = "name"
+	ProductSortNameDesc      ProductSort = "-name"
+	ProductSortPrice         ProductSort = "price"
+	ProductSortPriceDesc     ProductSort = "-price"
+	ProductSortCreatedAt     ProductSort = "created_at"
+	ProductSortCreatedAtDesc ProductSort = "-created_at"
+)
+
+// ProductFilter narrows a product listing, zero values are not applied.
+// Query matches any part of the product name ignoring case, AvailableAt leaves
+// out the products outside their availability windows at that time.
+type ProductFilter struct {
+	Category    string
+	MinPrice    float32
+	MaxPrice    float32
+	Query       string
+	AvailableAt time.Time
+	Sort        ProductSort
+	Cursor      string
+	Limit       int
+}
+
+type ProductPage struct {
 	Products   []Product
+	NextCursor string
+}
+
+// ProductMatch is a product found by search. FullText is false when the product
+// only matched the query loosely, Headline is the name with the matched words
+// wrapped in <mark> tags.
+type ProductMatch struct {
+	Product  Product
+	FullText bool
+	Score    float32
+	Headline string
+}
+
+// ProductPatch holds the product fields to change, nil fields are left as they are.
+type ProductPatch struct {
+	Name       *string
+	Category   *string
+	CategoryID *string
+	Price      *float32
+}
+
+type OrderStatus string
+
+const (
+	OrderStatusPlaced    OrderStatus = "placed"
+	OrderStatusAccepted  OrderStatus = "accepted"
+	OrderStatusPreparing OrderStatus = "preparing"
+	OrderStatusReady     OrderStatus = "ready"
+	OrderStatusCompleted OrderStatus = "completed"
+	OrderStatusCancelled OrderStatus = "cancelled"
+	OrderStatusRejected  OrderStatus = "rejected"
+)
+
+type Order struct {
+	ID            string
+	CouponCode    string
+	CustomerID    string
+	Items         []Item
+	Products      []Product
+	Subtotal      float32
+	Discount      float32
+	Total         float32
+	Status        OrderStatus
+	StatusHistory []StatusTransition
+	CreatedAt     int64
 }
 
+type StatusTransition struct {
+	From      OrderStatus
+	To        OrderStatus
+	Reason    string
+	CreatedAt int64
+}
+
+// Item.UnitPrice is the product price plus the price delta of its Modifiers.
 type Item struct {
 	ProductID string
 	Quantity  int
+	Modifiers []Modifier
+	UnitPrice float32
+	LineTotal float32
+}
+
+type OrderFilter struct {
+	CreatedFrom int64
+	CreatedTo   int64
+	CouponCode  string
+	Ascending   bool
+	Cursor      string
+	Limit       int
+}
+
+type OrderPage struct {
+	Orders     []Order
+	NextCursor string
+}
+
+type DiscountKind string
+
+const (
+	DiscountPercentage       DiscountKind = "percentage"
+	DiscountFixedAmount      DiscountKind = "fixed_amount"
+	DiscountFreeCheapestItem DiscountKind = "free_cheapest_item"
+)
+
+// CouponRule describes a discount granted by a coupon. Category restricts the
+// discount to lines of that category and MinBasket is the subtotal required for
+// the rule to apply.
+type CouponRule struct {
+	ID         string
+	CouponCode string
+	Kind       DiscountKind
+	Value      float32
+	Category   string
+	MinBasket  float32
+}
+
+// Coupon is a code along with the coupon files it appears in. Manual coupons
+// were created by an admin and are valid without appearing in any file.
+type Coupon struct {
+	Code       string
+	Sources    []int
+	Manual     bool
+	Disabled   bool
+	DisabledAt int64
+	Valid      bool
+	Rules      []CouponRule
+}
+
+type CouponRedemption struct {
+	ID         string
+	CouponCode string
+	OrderID    string
+	CustomerID string
+	CreatedAt  int64
+	ReleasedAt int64
 }
@@ -23,12 +23,17 @@ type Service interface {
 }
 
 func SetupServer(service Service, log slog.Logger) *httptest.Server {
+	return SetupVersionedServer(service, log, "v1")
+}
+
+// SetupVersionedServer mounts the service routes under /api/{version}.
+func SetupVersionedServer(service Service, log slog.Logger, version string) *httptest.Server {
 	testRouter := chi.NewRouter()
 	testRouter.Use(middleware.AddLogger(log))
-	v1Router := chi.NewRouter()
+	versionRouter := chi.NewRouter()
 
-	service.GetRoutes(v1Router)
-	testRouter.Mount("/api/v1", v1Router)
+	service.GetRoutes(versionRouter)
+	testRouter.Mount("/api/"+version, versionRouter)
 
 	return httptest.NewServer(testRouter)
 }
@@ -67,6 +67,13 @@ var (
 		Code:        "generic_bad_request",
 	}
 
+	// Err401Default ...
+	Err401Default = &Error{
+		Status:      http.StatusUnauthorized,
+		Description: http.StatusText(http.StatusUnauthorized),
+		Code:        "generic_unauthorized",
+	}
+
 	// Err404Default ...
 	Err404Default = &Error{
 		Status:      http.StatusNotFound,